@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"os"
 
+	_ "github.com/lib/pq"
+
 	"github.com/DanilaKorobkov/defi-monitoring/internal/presentation/cli"
 	"github.com/DanilaKorobkov/defi-monitoring/migrations"
 )
@@ -20,6 +22,15 @@ func main() {
 				Logger:             logger,
 			},
 		},
+		SubjectsCommandConfig: cli.SubjectsCommandConfig{
+			PostgresURLEnvName: "POSTGRES_URL",
+		},
+		PositionsCommandConfig: cli.PositionsCommandConfig{
+			PostgresURLEnvName: "POSTGRES_URL",
+		},
+		OutboxCommandConfig: cli.OutboxCommandConfig{
+			PostgresURLEnvName: "POSTGRES_URL",
+		},
 	}
 	command := cli.New(config)
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,7 +11,8 @@ import (
 	"time"
 
 	"github.com/caarlos0/env/v11"
-	"github.com/hasura/go-graphql-client"
+	"github.com/jmoiron/sqlx"
+	"github.com/sourcegraph/conc"
 
 	_ "github.com/lib/pq"
 
@@ -18,25 +20,132 @@ import (
 
 	"github.com/DanilaKorobkov/defi-monitoring/internal"
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	outboxservice "github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/outbox"
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/watcher"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/discord"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/email"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/slack"
 	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/telegram"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/webhook"
 	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers"
 	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/base/aerodrome"
-	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/base/uniswap_v3"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/links"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/onchain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/price_providers"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/price_providers/static"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/price_providers/thegraph"
+	dashboardspg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/dashboards/postgres"
+	gaugesscanspg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/gauges_scans/postgres"
+	outboxpg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/outbox/postgres"
+	historypg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/positions_history/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/subjects/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/presentation/telegrambot"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
 )
 
+var errNoPositionsSource = errors.New("either <CHAIN>_RPC_URL or THE_GRAPH_TOKEN must be set")
+
 const (
 	baseUniswapV3GraphID = "HMuAwufqZ1YCRmzL2SfHTVkzZovC9VL2UAKhjvRqKiR1"
 	baseAerodromeGraphID = "GENunSHWLBXm59mBSgPzQ8metBEp9YDfdqwFr91Av1UM"
+
+	baseAerodromePositionManager = "0x827922686190790b37229fd06084350E74485b72"
+	baseAerodromeFactory         = "0x5e7BB104d84c7CB9B682AaC2F3d509f5F406809A"
+	baseAerodromeVoter           = "0x16613524e02ad97eDfeF371bC883F2F5d6C480A5"
+	// baseAerodromeGaugesFromBlock precedes the Slipstream launch, no gauge deposits were made earlier.
+	baseAerodromeGaugesFromBlock = 12_000_000
+
+	// Telegram allows about 30 messages per second overall and 1 message per second to a chat.
+	telegramGlobalInterval = time.Second / 30
+	telegramChatInterval   = time.Second
+	telegramMaxRetries     = 3
+
+	// notifiersHTTPTimeout bounds a webhook request, so a hung endpoint does not stall the delivery.
+	notifiersHTTPTimeout  = 10 * time.Second
+	chatWebhookMaxRetries = 3
+	// smtpTimeout bounds the SMTP session of the email.
+	smtpTimeout = 10 * time.Second
+
+	outboxPollInterval   = 10 * time.Second
+	outboxBatchSize      = 100
+	outboxMaxAttempts    = 8
+	outboxInitialBackoff = 30 * time.Second
+	outboxLease          = 5 * time.Minute
 )
 
 type Config struct {
 	TelegramBotToken            string        `env:"TELEGRAM_BOT_TOKEN,required,unset"`
+	TelegramCharts              bool          `env:"TELEGRAM_CHARTS" envDefault:"false"`
 	ErrorReceiverTelegramUserID int64         `env:"ERROR_RECEIVER_TELEGRAM_USER_ID,required,unset"`
-	SubjectTelegramUserID       int64         `env:"SUBJECT_TELEGRAM_USER_ID,required,unset"`
-	SubjectWallet               string        `env:"SUBJECT_WALLET,required,unset"`
-	TheGraphToken               string        `env:"THE_GRAPH_TOKEN,required,unset"`
+	BotAllowedTelegramUserIDs   []int64       `env:"BOT_ALLOWED_TELEGRAM_USER_IDS,required"`
+	BotMaxWallets               int           `env:"BOT_MAX_WALLETS" envDefault:"10"`
+	TheGraphToken               string        `env:"THE_GRAPH_TOKEN,unset"`
+	Base                        ChainConfig   `envPrefix:"BASE_"`
+	Ethereum                    ChainConfig   `envPrefix:"ETHEREUM_"`
+	Arbitrum                    ChainConfig   `envPrefix:"ARBITRUM_"`
+	Optimism                    ChainConfig   `envPrefix:"OPTIMISM_"`
+	Polygon                     ChainConfig   `envPrefix:"POLYGON_"`
+	LogsBlockRange              uint64        `env:"LOGS_BLOCK_RANGE" envDefault:"10000"`
+	GaugesMaxScanBlocks         uint64        `env:"GAUGES_MAX_SCAN_BLOCKS" envDefault:"500000"`
+	PricesFile                  string        `env:"PRICES_FILE"`
+	SMTPHost                    string        `env:"SMTP_HOST"`
+	SMTPPort                    int           `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername                string        `env:"SMTP_USERNAME"`
+	SMTPPassword                string        `env:"SMTP_PASSWORD,unset"`
+	SMTPFrom                    string        `env:"SMTP_FROM"`
+	SMTPStartTLS                bool          `env:"SMTP_STARTTLS" envDefault:"true"`
 	CheckInterval               time.Duration `env:"CHECK_INTERVAL,required"`
+	SubjectsRefreshInterval     time.Duration `env:"SUBJECTS_REFRESH_INTERVAL,required"`
+	PostgresHost                string        `env:"POSTGRES_HOST,required"`
+	PostgresPort                string        `env:"POSTGRES_PORT,required"`
+	PostgresUser                string        `env:"POSTGRES_USER,required"`
+	PostgresPassword            string        `env:"POSTGRES_PASSWORD,required,unset"`
+	PostgresDB                  string        `env:"POSTGRES_DB,required"`
+}
+
+// ChainConfig is the positions source of the chain, see positions_providers.ChainSource.
+type ChainConfig struct {
+	RPCURL               string `env:"RPC_URL,unset"`
+	UniswapV3GraphID     string `env:"UNISWAP_V3_GRAPH_ID"`
+	UniswapV4GraphID     string `env:"UNISWAP_V4_GRAPH_ID"`
+	PancakeSwapV3GraphID string `env:"PANCAKESWAP_V3_GRAPH_ID"`
+	SushiSwapV3GraphID   string `env:"SUSHISWAP_V3_GRAPH_ID"`
+}
+
+// GetChainSources returns positions sources of all chains, Base is watched with the default subgraph.
+func (config Config) GetChainSources() []positions_providers.ChainSource {
+	makeSource := func(chain domain.Chain, chainConfig ChainConfig) positions_providers.ChainSource {
+		return positions_providers.ChainSource{
+			Chain:                chain,
+			RPCURL:               chainConfig.RPCURL,
+			UniswapV3GraphID:     chainConfig.UniswapV3GraphID,
+			UniswapV4GraphID:     chainConfig.UniswapV4GraphID,
+			PancakeSwapV3GraphID: chainConfig.PancakeSwapV3GraphID,
+			SushiSwapV3GraphID:   chainConfig.SushiSwapV3GraphID,
+		}
+	}
+
+	base := makeSource(domain.ChainBase, config.Base)
+	if base.UniswapV3GraphID == "" {
+		base.UniswapV3GraphID = baseUniswapV3GraphID
+	}
+
+	return []positions_providers.ChainSource{
+		base,
+		makeSource(domain.ChainEthereum, config.Ethereum),
+		makeSource(domain.ChainArbitrum, config.Arbitrum),
+		makeSource(domain.ChainOptimism, config.Optimism),
+		makeSource(domain.ChainPolygon, config.Polygon),
+	}
+}
+
+func (config Config) MakePostgresURL() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		config.PostgresUser, config.PostgresPassword, config.PostgresHost, config.PostgresPort, config.PostgresDB,
+	)
 }
 
 //nolint:funlen,maintidx // How to make better?
@@ -62,49 +171,218 @@ func main() {
 		fatal(slog.Default(), fmt.Errorf("tgbotapi.NewBotAPI: %w", err))
 	}
 
-	telegramNotifier := telegram.NewNotifier(telegramBot)
+	db, err := sqlx.Connect("postgres", config.MakePostgresURL())
+	if err != nil {
+		fatal(slog.Default(), fmt.Errorf("sqlx.Connect: %w", err))
+	}
+
+	subjects := postgres.NewSubjectsRepository(db)
+	positionsHistory := historypg.NewPositionsHistoryRepository(db)
+
+	outbox := outboxpg.NewOutboxRepository(db)
+
+	telegramNotifier := telegram.NewNotifier(telegram.NotifierConfig{
+		TelegramBot:    telegramBot,
+		Dashboards:     dashboardspg.NewDashboardsRepository(db),
+		GlobalInterval: telegramGlobalInterval,
+		ChatInterval:   telegramChatInterval,
+		MaxRetries:     telegramMaxRetries,
+		Charts:         config.TelegramCharts,
+		History:        positionsHistory,
+		Logger:         logger,
+	})
+	notifier := notifiers.NewFanOut(makeNotifiers(config, telegramNotifier))
 
-	baseUniswapV3 := makeBaseUniswapV3Provider(config)
-	baseAerodrome := makeBaseAerodromeProvider(config)
-	lp := positions_providers.NewComposite(baseUniswapV3, baseAerodrome)
+	lp, err := makePositionsProvider(config, gaugesscanspg.NewGaugesScansRepository(db), logger)
+	if err != nil {
+		fatal(slog.Default(), fmt.Errorf("makePositionsProvider: %w", err))
+	}
+
+	prices, err := makePriceProvider(config)
+	if err != nil {
+		fatal(slog.Default(), fmt.Errorf("makePriceProvider: %w", err))
+	}
 
 	watcherConfig := watcher.ServiceConfig{
 		LiquidityPoolPositions: lp,
-		Notifier:               telegramNotifier,
+		Notifier:               notifier,
+		PositionsHistory:       positionsHistory,
+		Outbox:                 outbox,
+		Prices:                 prices,
+		Dashboard:              telegramNotifier,
 		CheckInterval:          config.CheckInterval,
 		Logger:                 logger,
 	}
 	watcherService := watcher.NewService(watcherConfig)
 
-	subject := domain.Subject{
-		TelegramUserID: config.SubjectTelegramUserID,
-		Wallets:        []string{config.SubjectWallet},
+	supervisorConfig := watcher.SupervisorConfig{
+		Subjects:        subjects,
+		Watcher:         watcherService,
+		RefreshInterval: config.SubjectsRefreshInterval,
+		Logger:          logger,
+	}
+	supervisor := watcher.NewSupervisor(supervisorConfig)
+
+	botConfig := telegrambot.BotConfig{
+		TelegramBot:    telegramBot,
+		Subjects:       subjects,
+		Positions:      watcherService,
 		CheckInterval:  config.CheckInterval,
+		AllowedUserIDs: config.BotAllowedTelegramUserIDs,
+		MaxWallets:     config.BotMaxWallets,
+		Logger:         logger,
 	}
+	bot := telegrambot.NewBot(botConfig)
+
+	dispatcherConfig := outboxservice.DispatcherConfig{
+		Outbox:         outbox,
+		Subjects:       subjects,
+		Notifier:       notifier,
+		PollInterval:   outboxPollInterval,
+		BatchSize:      outboxBatchSize,
+		MaxAttempts:    outboxMaxAttempts,
+		InitialBackoff: outboxInitialBackoff,
+		Lease:          outboxLease,
+		Logger:         logger,
+	}
+	dispatcher := outboxservice.NewDispatcher(dispatcherConfig)
+
+	var wg conc.WaitGroup
 
 	logger.Info("starting watcher")
-	watcherService.StartWatching(ctx, subject)
+	wg.Go(func() {
+		bot.Run(ctx)
+	})
+	wg.Go(func() {
+		dispatcher.Run(ctx)
+	})
+	supervisor.Run(ctx)
+	wg.Wait()
 	logger.Info("watcher finished")
 }
 
-func makeBaseUniswapV3Provider(config Config) *uniswap_v3.ProviderTheGraph {
-	url := "https://gateway.thegraph.com/api/subgraphs/id/" + baseUniswapV3GraphID
-	setAuth := func(r *http.Request) {
-		r.Header.Set("Authorization", "Bearer "+config.TheGraphToken)
+// makeNotifiers returns notifiers by channel kind, email is enabled only when SMTP server is configured.
+func makeNotifiers(
+	config Config,
+	telegramNotifier *telegram.Notifier,
+) map[domain.ChannelKind]domain.ChannelNotifier {
+	httpClient := &http.Client{Timeout: notifiersHTTPTimeout}
+	chatWebhooks := chatwebhook.NewPoster(chatwebhook.PosterConfig{
+		HTTPClient: httpClient,
+		MaxRetries: chatWebhookMaxRetries,
+	})
+
+	impls := map[domain.ChannelKind]domain.ChannelNotifier{
+		domain.ChannelTelegram: telegramNotifier,
+		domain.ChannelDiscord:  discord.NewNotifier(chatWebhooks),
+		domain.ChannelSlack:    slack.NewNotifier(chatWebhooks),
+		domain.ChannelWebhook: webhook.NewNotifier(webhook.NotifierConfig{
+			HTTPClient: httpClient,
+		}),
+	}
+
+	if config.SMTPHost != "" {
+		impls[domain.ChannelEmail] = email.NewNotifier(email.NotifierConfig{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.SMTPFrom,
+			StartTLS: config.SMTPStartTLS,
+			Timeout:  smtpTimeout,
+		})
+	}
+
+	return impls
+}
+
+// makePositionsProvider combines providers of the configured chains with Base Aerodrome, links of the positions
+// are made by the single builder.
+func makePositionsProvider(
+	config Config,
+	gaugesScans domain.GaugesScansRepository,
+	logger *slog.Logger,
+) (*links.Provider, error) {
+	impls, err := positions_providers.NewProviders(positions_providers.RegistryConfig{
+		Sources:       config.GetChainSources(),
+		TheGraphToken: config.TheGraphToken,
+		HTTPClient:    http.DefaultClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("NewProviders: %w", err)
+	}
+
+	if aerodromeProvider := makeBaseAerodromeProvider(config, gaugesScans, logger); aerodromeProvider != nil {
+		impls = append(impls, aerodromeProvider)
+	}
+
+	if len(impls) == 0 {
+		return nil, errNoPositionsSource
+	}
+
+	return links.NewProvider(positions_providers.NewComposite(impls...), links.NewBuilder()), nil
+}
+
+// makePriceProvider prefers static prices file, otherwise tokens are priced by the Uniswap V3 subgraph
+// of their chain. Positions are not valued when neither is configured.
+func makePriceProvider(config Config) (domain.PriceProvider, error) {
+	if config.PricesFile != "" {
+		return static.NewProviderStaticFromFile(config.PricesFile)
+	}
+
+	if config.TheGraphToken == "" {
+		return nil, nil
 	}
-	client := graphql.NewClient(url, http.DefaultClient).WithRequestModifier(setAuth)
 
-	return uniswap_v3.NewProviderTheGraph(client)
+	impls := make(map[domain.Chain]domain.PriceProvider)
+
+	for _, source := range config.GetChainSources() {
+		if source.UniswapV3GraphID != "" {
+			client := positions_providers.NewTheGraphClient(http.DefaultClient, config.TheGraphToken, source.UniswapV3GraphID)
+			impls[source.Chain] = thegraph.NewProviderTheGraph(client)
+		}
+	}
+
+	return price_providers.NewByChain(impls), nil
 }
 
-func makeBaseAerodromeProvider(config Config) *aerodrome.ProviderTheGraph {
-	url := "https://gateway.thegraph.com/api/subgraphs/id/" + baseAerodromeGraphID
-	setAuth := func(r *http.Request) {
-		r.Header.Set("Authorization", "Bearer "+config.TheGraphToken)
+// makeBaseAerodromeProvider returns nil if Base is not configured. Positions staked in gauges
+// are discovered only with Base RPC, the subgraph knows the gauge as the owner.
+func makeBaseAerodromeProvider(
+	config Config,
+	gaugesScans domain.GaugesScansRepository,
+	logger *slog.Logger,
+) domain.LiquidityPoolPositionsProvider {
+	if config.Base.RPCURL != "" {
+		client := ethrpc.NewClient(config.Base.RPCURL, http.DefaultClient)
+		return onchain.NewProviderRPC(client, onchain.ProviderRPCConfig{
+			Name:            "Base Aerodrome RPC",
+			Chain:           domain.ChainBase,
+			Dex:             domain.DexAerodrome,
+			PositionManager: baseAerodromePositionManager,
+			Factory:         baseAerodromeFactory,
+			FactoryKind:     onchain.FactorySlipstream,
+			Gauges: &onchain.GaugesConfig{
+				Voter:         baseAerodromeVoter,
+				FromBlock:     baseAerodromeGaugesFromBlock,
+				BlockRange:    config.LogsBlockRange,
+				MaxScanBlocks: config.GaugesMaxScanBlocks,
+				Scans:         gaugesScans,
+			},
+		})
 	}
-	client := graphql.NewClient(url, http.DefaultClient).WithRequestModifier(setAuth)
 
-	return aerodrome.NewProviderTheGraph(client)
+	if config.TheGraphToken == "" {
+		return nil
+	}
+
+	logger.Warn("BASE_RPC_URL is not set, Aerodrome positions staked in gauges are not watched")
+
+	client := positions_providers.NewTheGraphClient(http.DefaultClient, config.TheGraphToken, baseAerodromeGraphID)
+
+	return aerodrome.NewProviderTheGraph(client, aerodrome.ProviderTheGraphConfig{
+		PositionManager: baseAerodromePositionManager,
+	})
 }
 
 func fatal(logger *slog.Logger, err error) {
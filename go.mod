@@ -20,6 +20,7 @@ require (
 	github.com/sourcegraph/conc v0.3.0
 	github.com/stretchr/testify v1.10.0
 	github.com/urfave/cli/v3 v3.3.9
+	golang.org/x/crypto v0.36.0
 )
 
 require (
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	ChannelKind string
+	Severity    string
+)
+
+const (
+	ChannelTelegram ChannelKind = "telegram"
+	ChannelDiscord  ChannelKind = "discord"
+	ChannelSlack    ChannelKind = "slack"
+	ChannelEmail    ChannelKind = "email"
+	ChannelWebhook  ChannelKind = "webhook"
+
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// NotificationChannel is a destination the subject notifications are delivered to.
+type NotificationChannel struct {
+	Kind ChannelKind
+	// Target is a Telegram chat ID, a webhook URL or an email address depending on the kind.
+	Target string
+	// Secret signs webhook payloads, other kinds ignore it.
+	Secret string
+	// MinSeverity filters out less severe notifications, empty accepts all of them.
+	MinSeverity Severity
+}
+
+// ChannelError is the failure of the single channel, notifiers join them so callers may inspect every one.
+type ChannelError struct {
+	Kind ChannelKind
+	// Index is the channel position in the subject channels, targets are not exposed since URLs may be secret.
+	Index int
+	Err   error
+}
+
+func (e *ChannelError) Error() string {
+	return fmt.Sprintf("%s channel #%d: %s", e.Kind, e.Index, redactURL(e.Err))
+}
+
+func (e *ChannelError) Unwrap() error {
+	return e.Err
+}
+
+// PartialDeliveryError is the failure of the split notification after some of its parts are delivered.
+type PartialDeliveryError struct {
+	// SentParts counts the delivered parts including the ones skipped as sent before.
+	SentParts int
+	Err       error
+}
+
+func (e *PartialDeliveryError) Error() string {
+	return fmt.Sprintf("%d parts sent: %s", e.SentParts, e.Err)
+}
+
+func (e *PartialDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+type sentPartsKey struct{}
+
+// WithSentParts returns the context telling the channel notifier how many parts of the split notification
+// are delivered by the previous attempts, so the retry skips them.
+func WithSentParts(ctx context.Context, sentParts int) context.Context {
+	return context.WithValue(ctx, sentPartsKey{}, sentParts)
+}
+
+// GetSentParts returns the parts of the split notification delivered by the previous attempts.
+func GetSentParts(ctx context.Context) int {
+	sentParts, _ := ctx.Value(sentPartsKey{}).(int)
+	return sentParts
+}
+
+// redactURL returns the error message without the requested URL, webhook URLs carry their tokens.
+func redactURL(err error) string {
+	message := err.Error()
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) && urlErr.URL != "" {
+		message = strings.ReplaceAll(message, strconv.Quote(urlErr.URL), `"<redacted>"`)
+		message = strings.ReplaceAll(message, urlErr.URL, "<redacted>")
+	}
+
+	return message
+}
+
+func NewTelegramChannel(chatID int64) NotificationChannel {
+	return NotificationChannel{
+		Kind:   ChannelTelegram,
+		Target: strconv.FormatInt(chatID, 10),
+	}
+}
+
+// Accepts reports whether notifications of the severity are delivered to the channel.
+func (channel NotificationChannel) Accepts(severity Severity) bool {
+	return severity.getRank() >= channel.MinSeverity.getRank()
+}
+
+// IsValid reports whether the severity is one of the known ones.
+func (severity Severity) IsValid() bool {
+	switch severity {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	default:
+		return false
+	}
+}
+
+func (severity Severity) getRank() int {
+	switch severity {
+	case SeverityWarning:
+		return 1
+	case SeverityCritical:
+		return 2 //nolint:mnd // Ordinal.
+	default:
+		return 0
+	}
+}
+
+// GetSeverity tells how urgent the event is, leaving the range is the only one that needs an action.
+func (kind PositionEventKind) GetSeverity() Severity {
+	switch kind {
+	case PositionEventLeftRange:
+		return SeverityCritical
+	case PositionEventApproachingEdge, PositionEventDisappeared:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
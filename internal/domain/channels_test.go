@@ -0,0 +1,96 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type channelsSuite struct {
+	suite.Suite
+}
+
+func TestChannels(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(channelsSuite))
+}
+
+func (s *channelsSuite) TestAccepts() {
+	type TestCase struct {
+		name        string
+		minSeverity domain.Severity
+		severity    domain.Severity
+		expected    bool
+	}
+
+	testCases := []TestCase{
+		{
+			name:     "No filter accepts info",
+			severity: domain.SeverityInfo,
+			expected: true,
+		},
+		{
+			name:        "Same severity",
+			minSeverity: domain.SeverityWarning,
+			severity:    domain.SeverityWarning,
+			expected:    true,
+		},
+		{
+			name:        "More severe",
+			minSeverity: domain.SeverityWarning,
+			severity:    domain.SeverityCritical,
+			expected:    true,
+		},
+		{
+			name:        "Less severe",
+			minSeverity: domain.SeverityCritical,
+			severity:    domain.SeverityWarning,
+			expected:    false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			channel := domain.NotificationChannel{Kind: domain.ChannelSlack, MinSeverity: testCase.minSeverity}
+			s.Require().Equal(testCase.expected, channel.Accepts(testCase.severity))
+		})
+	}
+}
+
+func (s *channelsSuite) TestChannelError_URLError_URLRedacted() {
+	const webhook = "https://discord.com/api/webhooks/1/secret-token"
+
+	cause := errors.New("dial tcp: i/o timeout")
+	err := &domain.ChannelError{
+		Kind:  domain.ChannelDiscord,
+		Index: 1,
+		Err:   fmt.Errorf("http.Do: %w", &url.Error{Op: "Post", URL: webhook, Err: cause}),
+	}
+
+	s.Require().Equal(`discord channel #1: http.Do: Post "<redacted>": dial tcp: i/o timeout`, err.Error())
+	s.Require().ErrorIs(err, cause)
+}
+
+func (s *channelsSuite) TestGetChannels_NoChannels_TelegramChat() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	channels := subject.GetChannels()
+
+	s.Require().Equal([]domain.NotificationChannel{{
+		Kind:   domain.ChannelTelegram,
+		Target: strconv.FormatInt(subject.TelegramUserID, 10),
+	}}, channels)
+}
+
+func (s *channelsSuite) TestGetSeverity() {
+	s.Require().Equal(domain.SeverityCritical, domain.PositionEventLeftRange.GetSeverity())
+	s.Require().Equal(domain.SeverityWarning, domain.PositionEventApproachingEdge.GetSeverity())
+	s.Require().Equal(domain.SeverityInfo, domain.PositionEventReturnedToRange.GetSeverity())
+}
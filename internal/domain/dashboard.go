@@ -0,0 +1,7 @@
+package domain
+
+// Dashboard is the pinned Telegram message of the subject which is edited on every check.
+type Dashboard struct {
+	TelegramUserID int64
+	MessageID      int
+}
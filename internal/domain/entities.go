@@ -2,19 +2,41 @@ package domain
 
 import (
 	"math"
+	"math/big"
+	"strings"
 	"time"
 )
 
 type (
-	Chain string
-	Dex   string
+	Chain             string
+	Dex               string
+	PositionStatus    string
+	PositionEventKind string
 )
 
 const (
-	ChainBase Chain = "Base"
+	ChainBase     Chain = "Base"
+	ChainEthereum Chain = "Ethereum"
+	ChainArbitrum Chain = "Arbitrum"
+	ChainOptimism Chain = "Optimism"
+	ChainPolygon  Chain = "Polygon"
 
-	DexUniswapV3 Dex = "Uniswap V3"
-	DexAerodrome Dex = "Aerodrome"
+	DexUniswapV3     Dex = "Uniswap V3"
+	DexUniswapV4     Dex = "Uniswap V4"
+	DexAerodrome     Dex = "Aerodrome"
+	DexPancakeSwapV3 Dex = "PancakeSwap V3"
+	DexSushiSwapV3   Dex = "SushiSwap V3"
+
+	PositionStatusInRange    PositionStatus = "In range"
+	PositionStatusNearEdge   PositionStatus = "Near edge"
+	PositionStatusOutOfRange PositionStatus = "Out of range"
+
+	PositionEventAppeared        PositionEventKind = "Appeared"
+	PositionEventDisappeared     PositionEventKind = "Disappeared"
+	PositionEventLeftRange       PositionEventKind = "Left range"
+	PositionEventReturnedToRange PositionEventKind = "Returned to range"
+	PositionEventApproachingEdge PositionEventKind = "Approaching edge"
+	PositionEventFeesExceeded    PositionEventKind = "Fees exceeded"
 
 	tickBase float64 = 1.0001
 )
@@ -22,15 +44,132 @@ const (
 type Token struct {
 	Name     string
 	Decimals int
+	Address  string
+	// PriceUSD is zero when the price is unknown.
+	PriceUSD float64
+}
+
+// Prices are USD prices keyed by lower case token address or by token name.
+type Prices map[string]float64
+
+// Get returns the token price looking it up by address first.
+func (prices Prices) Get(token Token) (float64, bool) {
+	if price, ok := prices[strings.ToLower(token.Address)]; ok && token.Address != "" {
+		return price, true
+	}
+
+	price, ok := prices[token.Name]
+
+	return price, ok
+}
+
+// PositionValue is the position worth in USD.
+type PositionValue struct {
+	Token0USD float64
+	Token1USD float64
+	FeesUSD   float64
+}
+
+// GetTotalUSD returns value of the position liquidity without uncollected fees.
+func (value PositionValue) GetTotalUSD() float64 {
+	return value.Token0USD + value.Token1USD
+}
+
+// PositionDeposits is the position liquidity history in token units.
+type PositionDeposits struct {
+	Deposited0     float64
+	Deposited1     float64
+	Withdrawn0     float64
+	Withdrawn1     float64
+	CollectedFees0 float64
+	CollectedFees1 float64
+	OpenedAt       time.Time
+}
+
+// PositionEmissions are gauge rewards of the staked position.
+type PositionEmissions struct {
+	Token Token
+	// Pending is raw amount of the reward token available to claim.
+	Pending *big.Int
+}
+
+// GetPending returns pending rewards in token units.
+func (emissions PositionEmissions) GetPending() float64 {
+	return toUnits(orZero(emissions.Pending), emissions.Token.Decimals)
 }
 
 type Subject struct {
 	TelegramUserID int64
 	Wallets        []string
 	CheckInterval  time.Duration
+	// DigestInterval enables periodic full report of positions, zero disables it.
+	DigestInterval time.Duration
+	// EdgeWarningPercent is a distance to the nearest range bound in percents of the range width
+	// that is considered as approaching the edge, zero disables warnings.
+	EdgeWarningPercent float64
+	// FeesThresholds are uncollected fees amounts in token units keyed by token name
+	// that trigger the collect reminder.
+	FeesThresholds map[string]float64
+	// Channels receive the subject notifications, see GetChannels.
+	Channels []NotificationChannel
+	// LiveDashboard replaces reports with the pinned Telegram message updated on every check,
+	// new messages are sent only about range changes.
+	LiveDashboard bool
+}
+
+// GetChannels returns the subject channels, the Telegram chat of the subject is used when none configured.
+func (subject Subject) GetChannels() []NotificationChannel {
+	if len(subject.Channels) == 0 {
+		return []NotificationChannel{NewTelegramChannel(subject.TelegramUserID)}
+	}
+
+	return subject.Channels
+}
+
+// PositionKey identifies a position across all chains and dexes.
+type PositionKey struct {
+	Chain Chain
+	Dex   Dex
+	ID    string
+}
+
+// PositionEvent describes a change of the position noticed between checks.
+type PositionEvent struct {
+	Kind     PositionEventKind
+	Position LiquidityPoolPosition
+}
+
+// IsRangeChange reports whether the position went out of its range or returned to it.
+func (kind PositionEventKind) IsRangeChange() bool {
+	return kind == PositionEventLeftRange || kind == PositionEventReturnedToRange
+}
+
+// PositionSnapshot is the position state observed by the check.
+type PositionSnapshot struct {
+	Position  LiquidityPoolPosition
+	CheckedAt time.Time
+}
+
+// PositionTimeline is the position snapshots ordered by check time.
+type PositionTimeline []PositionSnapshot
+
+// GetOutOfRangeDuration sums time the position spent out of range. Every snapshot state lasts
+// until the next snapshot, so the last one is not counted.
+func (timeline PositionTimeline) GetOutOfRangeDuration() time.Duration {
+	var duration time.Duration
+
+	for i := 1; i < len(timeline); i++ {
+		if !timeline[i-1].Position.IsInRange() {
+			duration += timeline[i].CheckedAt.Sub(timeline[i-1].CheckedAt)
+		}
+	}
+
+	return duration
 }
 
 type LiquidityPoolPosition struct {
+	ID           string
+	Wallet       string
 	Chain        Chain
 	Dex          Dex
 	PositionLink string
@@ -39,41 +178,178 @@ type LiquidityPoolPosition struct {
 	CurrentTick  int
 	TickLower    int
 	TickUpper    int
+	Liquidity    *big.Int
+	SqrtPriceX96 *big.Int
+	// UncollectedFees0 and UncollectedFees1 are raw amounts of fees available to collect.
+	UncollectedFees0 *big.Int
+	UncollectedFees1 *big.Int
+	// Deposits are nil when the provider does not know the position history.
+	Deposits *PositionDeposits
+	// Staked positions are deposited to the gauge, which owns them on behalf of the wallet. Fees of staked
+	// liquidity go to voters, the position earns emissions instead.
+	Staked bool
+	// Emissions are nil when the position is not staked or the provider does not know the rewards.
+	Emissions *PositionEmissions
+	// Pool is the pool contract address, empty for Uniswap V4 pools kept by the singleton contract.
+	Pool string
+	// NFTContract is the position manager minting the position NFT with the ID.
+	NFTContract string
+	// NFTLink and PoolLink lead to the block explorer, empty when they are not known.
+	NFTLink  string
+	PoolLink string
 }
 
 func (p LiquidityPoolPosition) GetCurrentPrice() float64 {
 	return p.tickToPrice(p.CurrentTick)
 }
 
+// GetFeesExceeding returns names of the tokens which uncollected fees reach the thresholds in token units.
+func (p LiquidityPoolPosition) GetFeesExceeding(thresholds map[string]float64) []string {
+	fees0, fees1 := p.GetUncollectedFees()
+
+	var exceeding []string
+	if threshold, ok := thresholds[p.Token0.Name]; ok && fees0 >= threshold {
+		exceeding = append(exceeding, p.Token0.Name)
+	}
+	if threshold, ok := thresholds[p.Token1.Name]; ok && fees1 >= threshold {
+		exceeding = append(exceeding, p.Token1.Name)
+	}
+
+	return exceeding
+}
+
+func (p LiquidityPoolPosition) GetKey() PositionKey {
+	return PositionKey{
+		Chain: p.Chain,
+		Dex:   p.Dex,
+		ID:    p.ID,
+	}
+}
+
 func (p LiquidityPoolPosition) GetLowerPrice() float64 {
 	return p.tickToPrice(p.TickLower)
 }
 
-func (p LiquidityPoolPosition) GetTokensPercentage() (token0, token1 float64) {
-	if p.CurrentTick <= p.TickLower {
-		return 100, 0
+// GetStatus returns range status taking into account edge warning threshold of the subject.
+func (p LiquidityPoolPosition) GetStatus(edgeWarningPercent float64) PositionStatus {
+	switch {
+	case !p.IsInRange():
+		return PositionStatusOutOfRange
+	case p.IsNearEdge(edgeWarningPercent):
+		return PositionStatusNearEdge
+	default:
+		return PositionStatusInRange
 	}
+}
+
+// GetTokenAmounts returns amounts of tokens held by the position liquidity in token units.
+func (p LiquidityPoolPosition) GetTokenAmounts() (token0, token1 float64) {
+	if p.Liquidity == nil {
+		return 0, 0
+	}
+
+	amount0, amount1 := p.getRawTokenAmounts(p.Liquidity)
 
-	if p.CurrentTick >= p.TickUpper {
-		return 0, 100
+	return toUnits(amount0, p.Token0.Decimals), toUnits(amount1, p.Token1.Decimals)
+}
+
+// GetTokensPercentage returns value shares of the tokens in the position. The shares do not depend on
+// the liquidity, so they are calculated for the unit liquidity.
+func (p LiquidityPoolPosition) GetTokensPercentage() (token0, token1 float64) {
+	amount0, amount1 := p.getRawTokenAmounts(new(big.Int).Lsh(big.NewInt(1), q128Bits))
+
+	// Value of token0 amount in token1 is amount0 * sqrtPrice^2 / 2^192.
+	sqrtPrice := new(big.Float).SetInt(p.getSqrtPriceX96())
+	value0 := new(big.Float).SetInt(amount0)
+	value0.Mul(value0, sqrtPrice).Mul(value0, sqrtPrice)
+	value0.SetMantExp(value0, -2*q96Bits)
+
+	value1 := new(big.Float).SetInt(amount1)
+	total := new(big.Float).Add(value0, value1)
+
+	if total.Sign() == 0 {
+		return 0, 0
 	}
 
-	token1 = float64(p.CurrentTick-p.TickLower) / float64(p.TickUpper-p.TickLower) * 100
-	token0 = 100 - token1
+	share0, _ := new(big.Float).Quo(value0, total).Float64()
+	token0 = share0 * 100
+	token1 = 100 - token0
 
 	return token0, token1
 }
 
+// GetUncollectedFees returns fees available to collect in token units.
+func (p LiquidityPoolPosition) GetUncollectedFees() (token0, token1 float64) {
+	return toUnits(orZero(p.UncollectedFees0), p.Token0.Decimals), toUnits(orZero(p.UncollectedFees1), p.Token1.Decimals)
+}
+
 func (p LiquidityPoolPosition) GetUpperPrice() float64 {
 	return p.tickToPrice(p.TickUpper)
 }
 
+// GetValueUSD returns the position worth, false if price of any token is unknown.
+func (p LiquidityPoolPosition) GetValueUSD() (PositionValue, bool) {
+	if p.Token0.PriceUSD == 0 || p.Token1.PriceUSD == 0 {
+		return PositionValue{}, false
+	}
+
+	amount0, amount1 := p.GetTokenAmounts()
+	fees0, fees1 := p.GetUncollectedFees()
+
+	value := PositionValue{
+		Token0USD: amount0 * p.Token0.PriceUSD,
+		Token1USD: amount1 * p.Token1.PriceUSD,
+		FeesUSD:   fees0*p.Token0.PriceUSD + fees1*p.Token1.PriceUSD,
+	}
+
+	return value, true
+}
+
 func (p LiquidityPoolPosition) IsInRange() bool {
 	return p.TickLower <= p.CurrentTick && p.CurrentTick <= p.TickUpper
 }
 
+// IsNearEdge reports whether the in range position current tick is within edgeWarningPercent
+// of the range width from the nearest bound.
+func (p LiquidityPoolPosition) IsNearEdge(edgeWarningPercent float64) bool {
+	if edgeWarningPercent <= 0 || !p.IsInRange() {
+		return false
+	}
+
+	width := float64(p.TickUpper - p.TickLower)
+	distance := float64(min(p.CurrentTick-p.TickLower, p.TickUpper-p.CurrentTick))
+
+	return distance <= width*edgeWarningPercent/100
+}
+
+func (p LiquidityPoolPosition) getRawTokenAmounts(liquidity *big.Int) (amount0, amount1 *big.Int) {
+	return GetAmountsForLiquidity(
+		p.getSqrtPriceX96(),
+		GetSqrtRatioAtTick(p.TickLower),
+		GetSqrtRatioAtTick(p.TickUpper),
+		liquidity,
+	)
+}
+
+// getSqrtPriceX96 falls back to the current tick price when the provider does not report the exact one.
+func (p LiquidityPoolPosition) getSqrtPriceX96() *big.Int {
+	if p.SqrtPriceX96 != nil {
+		return p.SqrtPriceX96
+	}
+	return GetSqrtRatioAtTick(p.CurrentTick)
+}
+
 func (p LiquidityPoolPosition) tickToPrice(tick int) float64 {
 	decimal0 := p.Token0.Decimals
 	decimal1 := p.Token1.Decimals
 	return math.Pow(tickBase, float64(tick)) * math.Pow(10, math.Abs(float64(decimal0-decimal1)))
 }
+
+func toUnits(amount *big.Int, decimals int) float64 {
+	units := new(big.Float).SetInt(amount)
+	units.Quo(units, new(big.Float).SetFloat64(math.Pow10(decimals)))
+
+	value, _ := units.Float64()
+
+	return value
+}
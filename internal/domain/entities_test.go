@@ -0,0 +1,126 @@
+package domain_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type entitiesSuite struct {
+	suite.Suite
+}
+
+func TestEntities(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(entitiesSuite))
+}
+
+func (s *entitiesSuite) TestGetOutOfRangeDuration() {
+	type TestCase struct {
+		name             string
+		inRange          []bool
+		expectedDuration time.Duration
+	}
+
+	testCases := []TestCase{
+		{
+			name:             "Empty",
+			expectedDuration: 0,
+		},
+		{
+			name:             "Always in range",
+			inRange:          []bool{true, true, true},
+			expectedDuration: 0,
+		},
+		{
+			name:             "Last snapshot is not counted",
+			inRange:          []bool{true, true, false},
+			expectedDuration: 0,
+		},
+		{
+			name:             "Out of range periods",
+			inRange:          []bool{false, true, false, false, true},
+			expectedDuration: 3 * time.Hour,
+		},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			timeline := makeTimeline(testCase.inRange)
+
+			duration := timeline.GetOutOfRangeDuration()
+
+			s.Require().Equal(testCase.expectedDuration, duration)
+		})
+	}
+}
+
+func (s *entitiesSuite) TestGetTokensPercentage() {
+	type TestCase struct {
+		name           string
+		currentTick    int
+		expectedToken0 float64
+		expectedToken1 float64
+	}
+
+	testCases := []TestCase{
+		{name: "Below range", currentTick: -120, expectedToken0: 100, expectedToken1: 0},
+		{name: "Middle of range", currentTick: 0, expectedToken0: 50, expectedToken1: 50},
+		{name: "Above range", currentTick: 120, expectedToken0: 0, expectedToken1: 100},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			position := domain.LiquidityPoolPosition{
+				TickLower:   -60,
+				TickUpper:   60,
+				CurrentTick: testCase.currentTick,
+			}
+
+			token0, token1 := position.GetTokensPercentage()
+
+			s.Require().InDelta(testCase.expectedToken0, token0, 0.01)
+			s.Require().InDelta(testCase.expectedToken1, token1, 0.01)
+		})
+	}
+}
+
+func (s *entitiesSuite) TestGetTokenAmounts() {
+	position := domain.LiquidityPoolPosition{
+		Token0:       domain.Token{Name: "WETH", Decimals: 18},
+		Token1:       domain.Token{Name: "USDC", Decimals: 6},
+		TickLower:    -60,
+		TickUpper:    60,
+		CurrentTick:  0,
+		Liquidity:    big.NewInt(1_000_000_000_000_000_000),
+		SqrtPriceX96: domain.GetSqrtRatioAtTick(0),
+	}
+
+	token0, token1 := position.GetTokenAmounts()
+
+	s.Require().InDelta(0.002995354955910780, token0, 1e-12)
+	s.Require().InDelta(2995354955.910780, token1, 1e-3)
+}
+
+// makeTimeline makes hourly snapshots of the position.
+func makeTimeline(inRange []bool) domain.PositionTimeline {
+	start := time.Now()
+	position := generators.NewPositionGenerator().Slim().Result()
+
+	timeline := make(domain.PositionTimeline, 0, len(inRange))
+	for i, isInRange := range inRange {
+		snapshot := domain.PositionSnapshot{
+			Position:  position,
+			CheckedAt: start.Add(time.Duration(i) * time.Hour),
+		}
+		if !isInRange {
+			snapshot.Position.CurrentTick = position.TickUpper + 1
+		}
+		timeline = append(timeline, snapshot)
+	}
+
+	return timeline
+}
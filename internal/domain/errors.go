@@ -0,0 +1,12 @@
+package domain
+
+import "errors"
+
+var (
+	ErrSubjectNotFound = errors.New("subject not found")
+	ErrNoPositions     = errors.New("no positions with liquidity")
+	// ErrWalletsUnavailable means positions of some wallets could not be read, the rest are reported anyway.
+	ErrWalletsUnavailable = errors.New("wallets unavailable")
+	ErrDashboardNotFound  = errors.New("dashboard not found")
+	ErrGaugesScanNotFound = errors.New("gauges scan not found")
+)
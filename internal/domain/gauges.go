@@ -0,0 +1,11 @@
+package domain
+
+// GaugesScan is the progress of discovering gauges the wallet staked positions to from its deposit events.
+type GaugesScan struct {
+	Chain  Chain
+	Wallet string
+	// NextBlock is the first block not scanned yet.
+	NextBlock uint64
+	// Gauges are registered gauges the wallet deposited to before NextBlock.
+	Gauges []string
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type LiquidityPoolPositionsProvider interface {
 	// GetName returns driver information.
@@ -9,14 +12,77 @@ type LiquidityPoolPositionsProvider interface {
 	GetPositionsWithLiquidity(ctx context.Context, wallet string) ([]LiquidityPoolPosition, error)
 }
 
+type PriceProvider interface {
+	// GetName returns driver information.
+	GetName() string
+	// GetPricesUSD returns USD prices of the chain tokens, tokens with unknown price are omitted.
+	GetPricesUSD(ctx context.Context, chain Chain, tokens []Token) (Prices, error)
+}
+
 type Notifier interface {
 	// NotifyLiquidityPoolPositions notify subject the positions status and info about.
 	NotifyLiquidityPoolPositions(ctx context.Context, subject Subject, positions ...LiquidityPoolPosition) error
+	// NotifyPositionsEvents notify subject about changes of the positions.
+	NotifyPositionsEvents(ctx context.Context, subject Subject, events ...PositionEvent) error
+}
+
+// ChannelNotifier delivers notifications to the single channel of the kind it is registered for.
+type ChannelNotifier interface {
+	// NotifyLiquidityPoolPositions notify channel the positions status and info about.
+	NotifyLiquidityPoolPositions(
+		ctx context.Context,
+		channel NotificationChannel,
+		subject Subject,
+		positions ...LiquidityPoolPosition,
+	) error
+	// NotifyPositionsEvents notify channel about changes of the positions.
+	NotifyPositionsEvents(ctx context.Context, channel NotificationChannel, subject Subject, events ...PositionEvent) error
+}
+
+// DashboardPublisher keeps the single message with the subject positions up to date.
+type DashboardPublisher interface {
+	// PublishDashboard edits the subject dashboard or sends and pins the new one if there is none.
+	PublishDashboard(ctx context.Context, subject Subject, positions ...LiquidityPoolPosition) error
 }
 
 type SubjectsRepository interface {
 	// Add subject and override if already exists.
 	Add(ctx context.Context, subject Subject) error
+	// Get returns the subject or ErrSubjectNotFound.
+	Get(ctx context.Context, telegramUserID int64) (Subject, error)
 	// GetAll returns all stored subjects.
 	GetAll(ctx context.Context) ([]Subject, error)
 }
+
+type PositionsHistoryRepository interface {
+	// Add snapshots of the positions.
+	Add(ctx context.Context, snapshots []PositionSnapshot) error
+	// GetTimeline returns position snapshots checked within [from, to).
+	GetTimeline(ctx context.Context, key PositionKey, from, to time.Time) (PositionTimeline, error)
+}
+
+type OutboxRepository interface {
+	// Add saves the position snapshots and enqueues the messages about them in one transaction.
+	Add(ctx context.Context, snapshots []PositionSnapshot, messages []OutboxMessage) error
+	// Claim returns up to limit pending messages due now and postpones them by the lease,
+	// so a message is delivered again if the claimer crashes.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	// Update saves the delivery state of the message.
+	Update(ctx context.Context, message OutboxMessage) error
+	// GetByStatus returns up to limit latest messages with the status.
+	GetByStatus(ctx context.Context, status OutboxStatus, limit int) ([]OutboxMessage, error)
+}
+
+type DashboardsRepository interface {
+	// Add dashboard and override if already exists.
+	Add(ctx context.Context, dashboard Dashboard) error
+	// Get returns the subject dashboard or ErrDashboardNotFound.
+	Get(ctx context.Context, telegramUserID int64) (Dashboard, error)
+}
+
+type GaugesScansRepository interface {
+	// Add scan and override if already exists.
+	Add(ctx context.Context, scan GaugesScan) error
+	// Get returns the wallet scan on the chain or ErrGaugesScanNotFound.
+	Get(ctx context.Context, chain Chain, wallet string) (GaugesScan, error)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending OutboxStatus = "pending"
+	OutboxStatusSent    OutboxStatus = "sent"
+	// OutboxStatusDead marks messages which ran out of delivery attempts.
+	OutboxStatusDead OutboxStatus = "dead"
+)
+
+// OutboxMessage is a notification stored for the durable delivery. It carries either events
+// or a full positions report.
+type OutboxMessage struct {
+	ID int64
+	// TelegramUserID identifies the subject, its channels are loaded on delivery, so targets and secrets
+	// are not stored with the message.
+	TelegramUserID int64
+	// Deliveries are the subject channels the message is still due to.
+	Deliveries []OutboxDelivery
+	Events     []PositionEvent
+	Positions  []LiquidityPoolPosition
+	Status     OutboxStatus
+	// Attempts counts failed deliveries.
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	NextAttemptAt time.Time
+}
+
+// OutboxDelivery is the delivery state of the message to the single subject channel.
+type OutboxDelivery struct {
+	// Channel is the index of the channel in the subject channels.
+	Channel int
+	// SentParts counts the parts of the split notification already delivered, retries skip them.
+	SentParts int
+}
+
+// NewOutboxMessage makes the pending message due immediately to every subject channel.
+func NewOutboxMessage(subject Subject, events []PositionEvent, positions []LiquidityPoolPosition) OutboxMessage {
+	now := time.Now()
+
+	deliveries := make([]OutboxDelivery, 0, len(subject.GetChannels()))
+	for index := range subject.GetChannels() {
+		deliveries = append(deliveries, OutboxDelivery{Channel: index})
+	}
+
+	return OutboxMessage{
+		TelegramUserID: subject.TelegramUserID,
+		Deliveries:     deliveries,
+		Events:         events,
+		Positions:      positions,
+		Status:         OutboxStatusPending,
+		CreatedAt:      now,
+		NextAttemptAt:  now,
+	}
+}
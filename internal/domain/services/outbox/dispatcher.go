@@ -0,0 +1,200 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/tickers"
+)
+
+type DispatcherConfig struct {
+	Outbox   domain.OutboxRepository
+	Subjects domain.SubjectsRepository
+	Notifier domain.Notifier
+	// PollInterval is how often due messages are claimed.
+	PollInterval time.Duration
+	BatchSize    int
+	// MaxAttempts is the number of failed deliveries after which the message is dead-lettered.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, every next delay is doubled.
+	InitialBackoff time.Duration
+	// Lease postpones claimed messages in case of crash, must be longer than delivery of the batch.
+	Lease  time.Duration
+	Logger *slog.Logger
+}
+
+// Dispatcher delivers enqueued notifications retrying failed channels with exponential backoff.
+type Dispatcher struct {
+	outbox         domain.OutboxRepository
+	subjects       domain.SubjectsRepository
+	notifier       domain.Notifier
+	pollInterval   time.Duration
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	lease          time.Duration
+	logger         *slog.Logger
+}
+
+func NewDispatcher(config DispatcherConfig) *Dispatcher {
+	return &Dispatcher{
+		outbox:         config.Outbox,
+		subjects:       config.Subjects,
+		notifier:       config.Notifier,
+		pollInterval:   config.PollInterval,
+		batchSize:      config.BatchSize,
+		maxAttempts:    config.MaxAttempts,
+		initialBackoff: config.InitialBackoff,
+		lease:          config.Lease,
+		logger:         config.Logger,
+	}
+}
+
+// Dispatch delivers one batch of due messages and saves their delivery state. A failed save does not stop
+// the others, the message is delivered again once its lease expires.
+func (dispatcher *Dispatcher) Dispatch(ctx context.Context) error {
+	messages, err := dispatcher.outbox.Claim(ctx, dispatcher.batchSize, dispatcher.lease)
+	if err != nil {
+		return fmt.Errorf("OutboxRepository.Claim: %w", err)
+	}
+
+	var errs []error
+	for _, message := range messages {
+		err = dispatcher.outbox.Update(ctx, dispatcher.deliver(ctx, message))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("OutboxRepository.Update: message %d: %w", message.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run blocks until ctx is done.
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	ticker := tickers.NewTickerChanWithInitial(dispatcher.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := dispatcher.Dispatch(ctx)
+			if err != nil {
+				dispatcher.logger.Error("Dispatch", slog.String("err", err.Error()))
+			}
+		}
+	}
+}
+
+// deliver sends the message to the channels it is due to and returns its new state, only failed channels
+// are kept for the retry with their delivered parts, so nothing is notified twice.
+func (dispatcher *Dispatcher) deliver(ctx context.Context, message domain.OutboxMessage) domain.OutboxMessage {
+	subject, err := dispatcher.subjects.Get(ctx, message.TelegramUserID)
+	if err != nil {
+		return dispatcher.fail(message, fmt.Errorf("SubjectsRepository.Get: %w", err))
+	}
+
+	message.Deliveries, err = dispatcher.sendAll(ctx, subject, message)
+	if err != nil {
+		return dispatcher.fail(message, err)
+	}
+
+	message.Status = domain.OutboxStatusSent
+
+	return message
+}
+
+// fail counts the failed attempt and postpones the message, it is dead-lettered once attempts run out.
+func (dispatcher *Dispatcher) fail(message domain.OutboxMessage, err error) domain.OutboxMessage {
+	message.Attempts++
+	message.LastError = err.Error()
+
+	logger := dispatcher.logger.With(
+		slog.Int64("message", message.ID),
+		slog.Int("attempts", message.Attempts),
+		slog.String("err", message.LastError),
+	)
+
+	if message.Attempts >= dispatcher.maxAttempts {
+		message.Status = domain.OutboxStatusDead
+		logger.Error("notification is dead-lettered")
+
+		return message
+	}
+
+	message.NextAttemptAt = time.Now().Add(dispatcher.initialBackoff << (message.Attempts - 1))
+	logger.Warn("notification delivery is postponed", slog.Time("next_attempt_at", message.NextAttemptAt))
+
+	return message
+}
+
+// send delivers the message to the single channel skipping the parts sent by the previous attempts,
+// the delivery is returned with the parts sent so far on failure.
+func (dispatcher *Dispatcher) send(
+	ctx context.Context,
+	subject domain.Subject,
+	message domain.OutboxMessage,
+	delivery domain.OutboxDelivery,
+) (domain.OutboxDelivery, error) {
+	channels := subject.GetChannels()
+	subject.Channels = []domain.NotificationChannel{channels[delivery.Channel]}
+
+	ctx = domain.WithSentParts(ctx, delivery.SentParts)
+
+	var err error
+	if len(message.Events) > 0 {
+		err = dispatcher.notifier.NotifyPositionsEvents(ctx, subject, message.Events...)
+	} else {
+		err = dispatcher.notifier.NotifyLiquidityPoolPositions(ctx, subject, message.Positions...)
+	}
+
+	var partialErr *domain.PartialDeliveryError
+	if errors.As(err, &partialErr) {
+		delivery.SentParts = partialErr.SentParts
+	}
+
+	return delivery, reindex(err, delivery.Channel)
+}
+
+// sendAll delivers the message to every due channel one by one and returns the failed deliveries.
+// Channels removed from the subject since the message is enqueued are skipped.
+func (dispatcher *Dispatcher) sendAll(
+	ctx context.Context,
+	subject domain.Subject,
+	message domain.OutboxMessage,
+) ([]domain.OutboxDelivery, error) {
+	var (
+		failed []domain.OutboxDelivery
+		errs   []error
+	)
+
+	for _, delivery := range message.Deliveries {
+		if delivery.Channel < 0 || delivery.Channel >= len(subject.GetChannels()) {
+			continue
+		}
+
+		attempted, err := dispatcher.send(ctx, subject, message, delivery)
+		if err != nil {
+			failed = append(failed, attempted)
+			errs = append(errs, err)
+		}
+	}
+
+	return failed, errors.Join(errs...)
+}
+
+// reindex points the channel error at the channel of the subject, the notifier is given the single channel
+// so it tells the first one.
+func reindex(err error, index int) error {
+	var channelErr *domain.ChannelError
+	if !errors.As(err, &channelErr) {
+		return err
+	}
+
+	return &domain.ChannelError{Kind: channelErr.Kind, Index: index, Err: channelErr.Err}
+}
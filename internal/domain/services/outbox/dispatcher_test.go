@@ -0,0 +1,323 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/outbox"
+	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+const maxAttempts = 3
+
+type dispatcherSuite struct {
+	suite.Suite
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(dispatcherSuite))
+}
+
+func (s *dispatcherSuite) TestDispatch_Delivered_MarkedSent() {
+	subject, message := s.makeEventsMessage()
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, withChannels(subject, subject.GetChannels()...), message.Events[0]).
+		Return(nil).
+		Once()
+
+	sent := message
+	sent.Deliveries = nil
+	sent.Status = domain.OutboxStatusSent
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, sent).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, s.newSubjects(subject), notifier).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_Report_PositionsDelivered() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	position := generators.NewPositionGenerator().Slim().Result()
+	message := domain.NewOutboxMessage(subject, nil, []domain.LiquidityPoolPosition{position})
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, withChannels(subject, subject.GetChannels()...), position).
+		Return(nil).
+		Once()
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.Status == domain.OutboxStatusSent
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, s.newSubjects(subject), notifier).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_ChannelFailed_RetriedOnlyFailedChannel() {
+	subject, _ := s.makeEventsMessage()
+	telegram := domain.NewTelegramChannel(subject.TelegramUserID)
+	slack := domain.NotificationChannel{Kind: domain.ChannelSlack, Target: "https://slack"}
+	subject.Channels = []domain.NotificationChannel{telegram, slack}
+
+	message := s.makeSubjectEventsMessage(subject)
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, withChannels(subject, telegram), message.Events[0]).
+		Return(nil).
+		Once()
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, withChannels(subject, slack), message.Events[0]).
+		Return(errors.Join(&domain.ChannelError{Kind: domain.ChannelSlack, Index: 0, Err: errors.New("timeout")})).
+		Once()
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.Status == domain.OutboxStatusPending &&
+				actual.Attempts == 1 &&
+				actual.LastError == "slack channel #1: timeout" &&
+				actual.NextAttemptAt.After(time.Now()) &&
+				s.Equal([]domain.OutboxDelivery{{Channel: 1}}, actual.Deliveries)
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, s.newSubjects(subject), notifier).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_PartiallyDelivered_SentPartsKept() {
+	subject, message := s.makeEventsMessage()
+	message.Deliveries = []domain.OutboxDelivery{{Channel: 0, SentParts: 1}}
+
+	resumed := func(ctx context.Context) bool {
+		return domain.GetSentParts(ctx) == 1
+	}
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.MatchedBy(resumed), withChannels(subject, subject.GetChannels()...), message.Events[0]).
+		Return(errors.Join(&domain.ChannelError{
+			Kind:  domain.ChannelTelegram,
+			Index: 0,
+			Err:   &domain.PartialDeliveryError{SentParts: 2, Err: errors.New("timeout")},
+		})).
+		Once()
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.Status == domain.OutboxStatusPending &&
+				s.Equal([]domain.OutboxDelivery{{Channel: 0, SentParts: 2}}, actual.Deliveries)
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, s.newSubjects(subject), notifier).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_ChannelRemoved_Skipped() {
+	subject, message := s.makeEventsMessage()
+	message.Deliveries = []domain.OutboxDelivery{{Channel: len(subject.GetChannels())}}
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.Status == domain.OutboxStatusSent
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, s.newSubjects(subject), mocks.NewNotifier(s.T())).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_SubjectNotLoaded_Postponed() {
+	_, message := s.makeEventsMessage()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, message.TelegramUserID).
+		Return(domain.Subject{}, errors.New("connection refused")).
+		Once()
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.Status == domain.OutboxStatusPending &&
+				actual.Attempts == 1 &&
+				s.Equal(message.Deliveries, actual.Deliveries)
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, subjects, mocks.NewNotifier(s.T())).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_AttemptsExhausted_DeadLettered() {
+	subject, message := s.makeEventsMessage()
+	message.Attempts = maxAttempts - 1
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, withChannels(subject, subject.GetChannels()...), message.Events[0]).
+		Return(errors.New("telegram is down")).
+		Once()
+
+	repository := s.newRepository(message)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.Status == domain.OutboxStatusDead && actual.Attempts == maxAttempts
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, s.newSubjects(subject), notifier).Dispatch(context.Background())
+
+	s.Require().NoError(err)
+}
+
+func (s *dispatcherSuite) TestDispatch_UpdateFailed_OthersSaved() {
+	subject, first := s.makeEventsMessage()
+	second := s.makeSubjectEventsMessage(subject)
+	second.ID = 2
+
+	errUpdate := errors.New("connection reset")
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, withChannels(subject, subject.GetChannels()...), mock.Anything).
+		Return(nil).
+		Twice()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Twice()
+
+	repository := s.newRepository(first, second)
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.ID == first.ID
+		})).
+		Return(errUpdate).
+		Once()
+	repository.EXPECT().
+		Update(mock.Anything, mock.MatchedBy(func(actual domain.OutboxMessage) bool {
+			return actual.ID == second.ID && actual.Status == domain.OutboxStatusSent
+		})).
+		Return(nil).
+		Once()
+
+	err := newDispatcher(repository, subjects, notifier).Dispatch(context.Background())
+
+	s.Require().ErrorIs(err, errUpdate)
+}
+
+func (s *dispatcherSuite) TestDispatch_ClaimFailed_Error() {
+	errClaim := errors.New("connection refused")
+
+	repository := mocks.NewOutboxRepository(s.T())
+	repository.EXPECT().
+		Claim(mock.Anything, 10, time.Minute).
+		Return(nil, errClaim).
+		Once()
+
+	dispatcher := newDispatcher(repository, mocks.NewSubjectsRepository(s.T()), mocks.NewNotifier(s.T()))
+	err := dispatcher.Dispatch(context.Background())
+
+	s.Require().ErrorIs(err, errClaim)
+}
+
+func (s *dispatcherSuite) makeEventsMessage() (domain.Subject, domain.OutboxMessage) {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	return subject, s.makeSubjectEventsMessage(subject)
+}
+
+func (s *dispatcherSuite) makeSubjectEventsMessage(subject domain.Subject) domain.OutboxMessage {
+	position := generators.NewPositionGenerator().Slim().Result()
+
+	message := domain.NewOutboxMessage(subject, []domain.PositionEvent{{
+		Kind:     domain.PositionEventLeftRange,
+		Position: position,
+	}}, nil)
+	message.ID = 1
+
+	return message
+}
+
+func (s *dispatcherSuite) newSubjects(subject domain.Subject) *mocks.SubjectsRepository {
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+
+	return subjects
+}
+
+func (s *dispatcherSuite) newRepository(claimed ...domain.OutboxMessage) *mocks.OutboxRepository {
+	repository := mocks.NewOutboxRepository(s.T())
+	repository.EXPECT().
+		Claim(mock.Anything, 10, time.Minute).
+		Return(claimed, nil).
+		Once()
+
+	return repository
+}
+
+// withChannels returns the subject as the notifier is given it to deliver to the channels.
+func withChannels(subject domain.Subject, channels ...domain.NotificationChannel) domain.Subject {
+	subject.Channels = channels
+	return subject
+}
+
+func newDispatcher(
+	repository domain.OutboxRepository,
+	subjects domain.SubjectsRepository,
+	notifier domain.Notifier,
+) *outbox.Dispatcher {
+	return outbox.NewDispatcher(outbox.DispatcherConfig{
+		Outbox:         repository,
+		Subjects:       subjects,
+		Notifier:       notifier,
+		PollInterval:   time.Hour,
+		BatchSize:      10,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Second,
+		Lease:          time.Minute,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+}
@@ -0,0 +1,75 @@
+package pnl
+
+import (
+	"time"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+const (
+	percents    = 100
+	hoursInYear = 365 * 24
+)
+
+// Report compares the position with holding the deposited tokens, all values are in USD at current prices.
+type Report struct {
+	// PositionUSD is value of the liquidity, withdrawn tokens and all earned fees.
+	PositionUSD float64
+	// HODLUSD is value of the deposited tokens if they were held instead.
+	HODLUSD float64
+	// FeesUSD is value of collected and uncollected fees.
+	FeesUSD float64
+	// ImpermanentLossPercent is change of the principal versus HODL without fees, it is negative on loss.
+	ImpermanentLossPercent float64
+	// FeeAPRPercent is annualized fees return on the deposited value.
+	FeeAPRPercent float64
+}
+
+// GetPnLUSD returns profit of providing liquidity versus holding, it is negative when holding is better.
+func (report Report) GetPnLUSD() float64 {
+	return report.PositionUSD - report.HODLUSD
+}
+
+// Calculate makes the report of the position at the moment, false when deposits or prices are unknown.
+func Calculate(position domain.LiquidityPoolPosition, at time.Time) (Report, bool) {
+	deposits := position.Deposits
+	if deposits == nil {
+		return Report{}, false
+	}
+
+	value, ok := position.GetValueUSD()
+	if !ok {
+		return Report{}, false
+	}
+
+	price0, price1 := position.Token0.PriceUSD, position.Token1.PriceUSD
+
+	hodl := deposits.Deposited0*price0 + deposits.Deposited1*price1
+	if hodl <= 0 {
+		return Report{}, false
+	}
+
+	principal := value.GetTotalUSD() + deposits.Withdrawn0*price0 + deposits.Withdrawn1*price1
+	fees := value.FeesUSD + deposits.CollectedFees0*price0 + deposits.CollectedFees1*price1
+
+	report := Report{
+		PositionUSD:            principal + fees,
+		HODLUSD:                hodl,
+		FeesUSD:                fees,
+		ImpermanentLossPercent: (principal/hodl - 1) * percents,
+		FeeAPRPercent:          getAPR(fees, hodl, at.Sub(deposits.OpenedAt)),
+	}
+
+	return report, true
+}
+
+// getAPR annualizes the return, zero for positions without known age.
+func getAPR(income, capital float64, age time.Duration) float64 {
+	if age <= 0 {
+		return 0
+	}
+
+	years := age.Hours() / hoursInYear
+
+	return income / capital / years * percents
+}
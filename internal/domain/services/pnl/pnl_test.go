@@ -0,0 +1,92 @@
+package pnl_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/pnl"
+)
+
+type pnlSuite struct {
+	suite.Suite
+}
+
+func TestPnL(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(pnlSuite))
+}
+
+func (s *pnlSuite) TestCalculate() {
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	position := makePosition()
+	position.Deposits.OpenedAt = now.Add(-365 * 24 * time.Hour / 2)
+
+	report, ok := pnl.Calculate(position, now)
+
+	s.Require().True(ok)
+	s.Require().InDelta(5000, report.HODLUSD, 1e-6)
+	s.Require().InDelta(4180, report.PositionUSD, 1e-6)
+	s.Require().InDelta(80, report.FeesUSD, 1e-6)
+	s.Require().InDelta(-820, report.GetPnLUSD(), 1e-6)
+	s.Require().InDelta(-18, report.ImpermanentLossPercent, 1e-6)
+	s.Require().InDelta(3.2, report.FeeAPRPercent, 1e-6)
+}
+
+func (s *pnlSuite) TestCalculate_Unknown() {
+	type TestCase struct {
+		name   string
+		modify func(position *domain.LiquidityPoolPosition)
+	}
+
+	testCases := []TestCase{
+		{
+			name: "No deposits",
+			modify: func(position *domain.LiquidityPoolPosition) {
+				position.Deposits = nil
+			},
+		},
+		{
+			name: "No price",
+			modify: func(position *domain.LiquidityPoolPosition) {
+				position.Token1.PriceUSD = 0
+			},
+		},
+		{
+			name: "Nothing deposited",
+			modify: func(position *domain.LiquidityPoolPosition) {
+				position.Deposits = &domain.PositionDeposits{}
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			position := makePosition()
+			testCase.modify(&position)
+
+			_, ok := pnl.Calculate(position, time.Now())
+
+			s.Require().False(ok)
+		})
+	}
+}
+
+// makePosition makes the fully withdrawn position with 0.01 WETH of uncollected fees.
+func makePosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		Token0:           domain.Token{Name: "WETH", Decimals: 18, PriceUSD: 3000},
+		Token1:           domain.Token{Name: "USDC", Decimals: 6, PriceUSD: 1},
+		UncollectedFees0: big.NewInt(1e16),
+		Deposits: &domain.PositionDeposits{
+			Deposited0:     1,
+			Deposited1:     2000,
+			Withdrawn0:     0.5,
+			Withdrawn1:     2600,
+			CollectedFees0: 0.01,
+			CollectedFees1: 20,
+		},
+	}
+}
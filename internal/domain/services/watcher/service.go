@@ -1,10 +1,17 @@
 package watcher
 
 import (
+	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/iter"
+
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
 	"github.com/DanilaKorobkov/defi-monitoring/pkg/tickers"
 )
@@ -12,13 +19,26 @@ import (
 type ServiceConfig struct {
 	LiquidityPoolPositions domain.LiquidityPoolPositionsProvider
 	Notifier               domain.Notifier
-	CheckInterval          time.Duration
-	Logger                 *slog.Logger
+	PositionsHistory       domain.PositionsHistoryRepository
+	// Outbox is optional, without it snapshots are saved and notifications are sent right away,
+	// so failed notifications are lost.
+	Outbox domain.OutboxRepository
+	// Prices are optional, positions are not valued without them.
+	Prices domain.PriceProvider
+	// Dashboard is optional, subjects with the live dashboard get usual reports without it.
+	Dashboard domain.DashboardPublisher
+	// CheckInterval is used for subjects without own check interval.
+	CheckInterval time.Duration
+	Logger        *slog.Logger
 }
 
 type Service struct {
 	liquidityPoolPositions domain.LiquidityPoolPositionsProvider
 	notifier               domain.Notifier
+	positionsHistory       domain.PositionsHistoryRepository
+	outbox                 domain.OutboxRepository
+	prices                 domain.PriceProvider
+	dashboard              domain.DashboardPublisher
 	checkInterval          time.Duration
 	logger                 *slog.Logger
 }
@@ -27,44 +47,281 @@ func NewService(config ServiceConfig) *Service {
 	return &Service{
 		liquidityPoolPositions: config.LiquidityPoolPositions,
 		notifier:               config.Notifier,
+		positionsHistory:       config.PositionsHistory,
+		outbox:                 config.Outbox,
+		prices:                 config.Prices,
+		dashboard:              config.Dashboard,
 		checkInterval:          config.CheckInterval,
 		logger:                 config.Logger,
 	}
 }
 
+// ReportPositions sends the subject positions on demand, ErrNoPositions if there is nothing to report.
+// Positions of available wallets are sent even if others fail, ErrWalletsUnavailable lists the failed ones.
+func (service *Service) ReportPositions(ctx context.Context, subject domain.Subject) error {
+	positions, unavailableWallets := service.getPositions(ctx, subject)
+
+	var unavailableErr error
+	if len(unavailableWallets) > 0 {
+		unavailableErr = fmt.Errorf("%w: %s", domain.ErrWalletsUnavailable, strings.Join(unavailableWallets, ", "))
+	}
+
+	if len(positions) == 0 {
+		return cmp.Or(unavailableErr, domain.ErrNoPositions)
+	}
+
+	positions = service.applyPrices(ctx, positions)
+
+	err := service.notifier.NotifyLiquidityPoolPositions(ctx, subject, positions...)
+	if err != nil {
+		return fmt.Errorf("NotifyLiquidityPoolPositions: %w", err)
+	}
+
+	return unavailableErr
+}
+
 func (service *Service) StartWatching(ctx context.Context, subject domain.Subject) {
-	ticker := tickers.NewTickerChanWithInitial(service.checkInterval)
+	ticker := tickers.NewTickerChanWithInitial(service.getCheckInterval(subject))
+	defer ticker.Stop()
+
+	state := &watchState{
+		subject: subject,
+		tracker: NewPositionsTracker(PositionsTrackerConfig{
+			EdgeWarningPercent: subject.EdgeWarningPercent,
+			FeesThresholds:     subject.FeesThresholds,
+		}),
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			service.checkPositions(context.WithoutCancel(ctx), subject)
+			service.checkPositions(context.WithoutCancel(ctx), state)
 		}
 	}
 }
 
-func (service *Service) checkPositions(ctx context.Context, subject domain.Subject) {
-	logger := service.logger.With(slog.String("wallet", subject.Wallets[0]))
-
-	positions, err := service.liquidityPoolPositions.GetPositionsWithLiquidity(
-		ctx,
-		subject.Wallets[0],
-	)
-	if err != nil {
-		logger.Error("GetPositionsWithLiquidity", slog.String("err", err.Error()))
-		return
+// applyPrices sets USD prices of the position tokens, positions are left unpriced on failure.
+func (service *Service) applyPrices(
+	ctx context.Context,
+	positions []domain.LiquidityPoolPosition,
+) []domain.LiquidityPoolPosition {
+	if service.prices == nil || len(positions) == 0 {
+		return positions
 	}
 
+	prices := service.getPrices(ctx, positions)
+
+	return lo.Map(positions, func(position domain.LiquidityPoolPosition, _ int) domain.LiquidityPoolPosition {
+		position.Token0.PriceUSD, _ = prices[position.Chain].Get(position.Token0)
+		position.Token1.PriceUSD, _ = prices[position.Chain].Get(position.Token1)
+		return position
+	})
+}
+
+func (service *Service) checkPositions(ctx context.Context, state *watchState) {
+	logger := service.logger.With(slog.Int64("subject", state.subject.TelegramUserID))
+
+	positions, unavailableWallets := service.getPositions(ctx, state.subject)
+	positions = service.applyPrices(ctx, positions)
+
+	events := state.tracker.Track(positions, unavailableWallets...)
 	if len(positions) == 0 {
 		logger.Info("no positions found")
+	}
+
+	report, events := service.makeReport(ctx, logger, state, positions, events)
+
+	if service.outbox != nil {
+		service.enqueue(ctx, logger, state.subject, positions, events, report)
+		return
+	}
+
+	service.saveSnapshots(ctx, positions)
+	service.notify(ctx, logger, state.subject, events, report)
+}
+
+// enqueue saves the snapshots and the notifications about them atomically for the durable delivery.
+func (service *Service) enqueue(
+	ctx context.Context,
+	logger *slog.Logger,
+	subject domain.Subject,
+	positions []domain.LiquidityPoolPosition,
+	events []domain.PositionEvent,
+	report []domain.LiquidityPoolPosition,
+) {
+	var messages []domain.OutboxMessage
+	if len(events) > 0 {
+		messages = append(messages, domain.NewOutboxMessage(subject, events, nil))
+	}
+	if len(report) > 0 {
+		messages = append(messages, domain.NewOutboxMessage(subject, nil, report))
+	}
+
+	if len(positions) == 0 && len(messages) == 0 {
 		return
 	}
 
-	err = service.notifier.NotifyLiquidityPoolPositions(ctx, subject, positions...)
+	err := service.outbox.Add(ctx, makeSnapshots(positions), messages)
 	if err != nil {
-		logger.Error("NotifyLiquidityPoolPositions", slog.String("err", err.Error()))
+		logger.Error("OutboxRepository.Add", slog.String("err", err.Error()))
+	}
+}
+
+func (service *Service) getCheckInterval(subject domain.Subject) time.Duration {
+	if subject.CheckInterval > 0 {
+		return subject.CheckInterval
+	}
+	return service.checkInterval
+}
+
+// getPositions fetches all subject wallets concurrently, failed wallets are returned as unavailable.
+func (service *Service) getPositions(
+	ctx context.Context,
+	subject domain.Subject,
+) ([]domain.LiquidityPoolPosition, []string) {
+	var (
+		mu                 sync.Mutex
+		unavailableWallets []string
+	)
+
+	perWallet := iter.Map(subject.Wallets, func(wallet *string) []domain.LiquidityPoolPosition {
+		positions, err := service.liquidityPoolPositions.GetPositionsWithLiquidity(ctx, *wallet)
+		if err != nil {
+			service.logger.Error(
+				"GetPositionsWithLiquidity",
+				slog.String("wallet", *wallet),
+				slog.String("err", err.Error()),
+			)
+
+			mu.Lock()
+			unavailableWallets = append(unavailableWallets, *wallet)
+			mu.Unlock()
+
+			return nil
+		}
+		return positions
+	})
+
+	return lo.Flatten(perWallet), unavailableWallets
+}
+
+// getPrices prices tokens of the positions within their chains, chains failed to price are omitted.
+func (service *Service) getPrices(
+	ctx context.Context,
+	positions []domain.LiquidityPoolPosition,
+) map[domain.Chain]domain.Prices {
+	byChain := lo.GroupBy(positions, func(position domain.LiquidityPoolPosition) domain.Chain {
+		return position.Chain
+	})
+
+	prices := make(map[domain.Chain]domain.Prices, len(byChain))
+
+	for chain, chainPositions := range byChain {
+		tokens := lo.FlatMap(chainPositions, func(position domain.LiquidityPoolPosition, _ int) []domain.Token {
+			return []domain.Token{position.Token0, position.Token1}
+		})
+
+		chainPrices, err := service.prices.GetPricesUSD(ctx, chain, tokens)
+		if err != nil {
+			service.logger.Error("GetPricesUSD", slog.String("chain", string(chain)), slog.String("err", err.Error()))
+			continue
+		}
+
+		prices[chain] = chainPrices
+	}
+
+	return prices
+}
+
+// makeReport returns the positions for the full report and the events to notify about. The live dashboard
+// replaces the report and shows all changes except range ones, which are still notified with new messages.
+// The dashboard is published right away even with the outbox since the next check fixes it anyway.
+func (service *Service) makeReport(
+	ctx context.Context,
+	logger *slog.Logger,
+	state *watchState,
+	positions []domain.LiquidityPoolPosition,
+	events []domain.PositionEvent,
+) ([]domain.LiquidityPoolPosition, []domain.PositionEvent) {
+	if state.subject.LiveDashboard && service.dashboard != nil {
+		err := service.dashboard.PublishDashboard(ctx, state.subject, positions...)
+		if err != nil {
+			logger.Error("PublishDashboard", slog.String("err", err.Error()))
+		}
+
+		return nil, lo.Filter(events, func(event domain.PositionEvent, _ int) bool {
+			return event.Kind.IsRangeChange()
+		})
+	}
+
+	if len(positions) == 0 || !state.isReportDue() {
+		return nil, events
+	}
+
+	state.lastReportAt = time.Now()
+
+	return positions, events
+}
+
+func (service *Service) notify(
+	ctx context.Context,
+	logger *slog.Logger,
+	subject domain.Subject,
+	events []domain.PositionEvent,
+	report []domain.LiquidityPoolPosition,
+) {
+	if len(events) > 0 {
+		err := service.notifier.NotifyPositionsEvents(ctx, subject, events...)
+		if err != nil {
+			logger.Error("NotifyPositionsEvents", slog.String("err", err.Error()))
+		}
+	}
+
+	if len(report) > 0 {
+		err := service.notifier.NotifyLiquidityPoolPositions(ctx, subject, report...)
+		if err != nil {
+			logger.Error("NotifyLiquidityPoolPositions", slog.String("err", err.Error()))
+		}
+	}
+}
+
+func (service *Service) saveSnapshots(ctx context.Context, positions []domain.LiquidityPoolPosition) {
+	if len(positions) == 0 {
 		return
 	}
+
+	err := service.positionsHistory.Add(ctx, makeSnapshots(positions))
+	if err != nil {
+		service.logger.Error("PositionsHistoryRepository.Add", slog.String("err", err.Error()))
+	}
+}
+
+type watchState struct {
+	subject      domain.Subject
+	tracker      *PositionsTracker
+	lastReportAt time.Time
+}
+
+// isReportDue reports whether the full report should be sent: once on start and then as a digest.
+func (state *watchState) isReportDue() bool {
+	if state.lastReportAt.IsZero() {
+		return true
+	}
+	if state.subject.DigestInterval <= 0 {
+		return false
+	}
+	return time.Since(state.lastReportAt) >= state.subject.DigestInterval
+}
+
+func makeSnapshots(positions []domain.LiquidityPoolPosition) []domain.PositionSnapshot {
+	checkedAt := time.Now()
+
+	return lo.Map(positions, func(position domain.LiquidityPoolPosition, _ int) domain.PositionSnapshot {
+		return domain.PositionSnapshot{
+			Position:  position,
+			CheckedAt: checkedAt,
+		}
+	})
 }
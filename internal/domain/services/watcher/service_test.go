@@ -0,0 +1,394 @@
+package watcher_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/watcher"
+	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type serviceSuite struct {
+	suite.Suite
+}
+
+func TestService(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(serviceSuite))
+}
+
+func (s *serviceSuite) TestReportPositions_NoPositions_ErrNoPositions() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		Result()
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return(nil, nil).
+		Once()
+
+	service := newService(s.T(), positions, mocks.NewNotifier(s.T()))
+	err := service.ReportPositions(ctx, subject)
+
+	s.Require().ErrorIs(err, domain.ErrNoPositions)
+}
+
+func (s *serviceSuite) TestReportPositions_AllWalletsFailed_ErrWalletsUnavailable() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		Result()
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return(nil, errors.New("node is down")).
+		Once()
+
+	service := newService(s.T(), positions, mocks.NewNotifier(s.T()))
+	err := service.ReportPositions(ctx, subject)
+
+	s.Require().ErrorIs(err, domain.ErrWalletsUnavailable)
+	s.Require().NotErrorIs(err, domain.ErrNoPositions)
+}
+
+func (s *serviceSuite) TestReportPositions_WalletFailed_OthersReportedAndErrWalletsUnavailable() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1", "0x2"}).
+		Result()
+
+	position := makeInRangePosition("1", "0x1")
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{position}, nil).
+		Once()
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x2").
+		Return(nil, errors.New("node is down")).
+		Once()
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, subject, position).
+		Return(nil).
+		Once()
+
+	service := newService(s.T(), positions, notifier)
+	err := service.ReportPositions(ctx, subject)
+
+	s.Require().ErrorIs(err, domain.ErrWalletsUnavailable)
+	s.Require().ErrorContains(err, "0x2")
+}
+
+func (s *serviceSuite) TestReportPositions_Success() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		Result()
+
+	position := makeInRangePosition("1", "0x1")
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{position}, nil).
+		Once()
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, subject, position).
+		Return(nil).
+		Once()
+
+	service := newService(s.T(), positions, notifier)
+	err := service.ReportPositions(ctx, subject)
+
+	s.Require().NoError(err)
+}
+
+func (s *serviceSuite) TestStartWatching_WalletFailed_NotifyOthersGroupedByWallet() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1", "0x2", "0x3"}).
+		Result()
+
+	first := domain.LiquidityPoolPosition{Wallet: "0x1", PositionLink: "first"}
+	third := domain.LiquidityPoolPosition{Wallet: "0x3", PositionLink: "third"}
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{first}, nil).
+		Once()
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x2").
+		Return(nil, errors.New("unavailable")).
+		Once()
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x3").
+		Return([]domain.LiquidityPoolPosition{third}, nil).
+		Once()
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, subject, first, third).
+		RunAndReturn(func(context.Context, domain.Subject, ...domain.LiquidityPoolPosition) error {
+			cancel()
+			return nil
+		}).
+		Once()
+
+	service := newService(s.T(), positions, notifier)
+	service.StartWatching(ctx, subject)
+}
+
+func (s *serviceSuite) TestStartWatching_LeftRange_NotifyEvent() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		WithCheckInterval(time.Millisecond).
+		Result()
+
+	inRange := makeInRangePosition("1", "0x1")
+	outOfRange := makeOutOfRangePosition("1", "0x1")
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{inRange}, nil).
+		Once()
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{outOfRange}, nil)
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, subject, inRange).
+		Return(nil).
+		Once()
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, subject, domain.PositionEvent{
+			Kind:     domain.PositionEventLeftRange,
+			Position: outOfRange,
+		}).
+		RunAndReturn(func(context.Context, domain.Subject, ...domain.PositionEvent) error {
+			cancel()
+			return nil
+		}).
+		Once()
+
+	service := newService(s.T(), positions, notifier)
+	service.StartWatching(ctx, subject)
+}
+
+func (s *serviceSuite) TestStartWatching_Prices_NotifyPricedPositions() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		Result()
+
+	position := makeInRangePosition("1", "0x1")
+	position.Token0.Address = "0xAAAA"
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{position}, nil).
+		Once()
+
+	prices := mocks.NewPriceProvider(s.T())
+	prices.EXPECT().
+		GetPricesUSD(mock.Anything, position.Chain, []domain.Token{position.Token0, position.Token1}).
+		Return(domain.Prices{"0xaaaa": 2500, "USDC": 1}, nil).
+		Once()
+
+	priced := position
+	priced.Token0.PriceUSD = 2500
+	priced.Token1.PriceUSD = 1
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, subject, priced).
+		RunAndReturn(func(context.Context, domain.Subject, ...domain.LiquidityPoolPosition) error {
+			cancel()
+			return nil
+		}).
+		Once()
+
+	service := newServiceWithPrices(s.T(), positions, notifier, prices)
+	service.StartWatching(ctx, subject)
+}
+
+func (s *serviceSuite) TestStartWatching_Outbox_ReportEnqueuedWithSnapshots() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		Result()
+
+	position := makeInRangePosition("1", "0x1")
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{position}, nil).
+		Once()
+
+	outbox := mocks.NewOutboxRepository(s.T())
+	outbox.EXPECT().
+		Add(
+			mock.Anything,
+			mock.MatchedBy(func(snapshots []domain.PositionSnapshot) bool {
+				return len(snapshots) == 1 && snapshots[0].Position.ID == position.ID
+			}),
+			mock.MatchedBy(func(messages []domain.OutboxMessage) bool {
+				return len(messages) == 1 &&
+					messages[0].TelegramUserID == subject.TelegramUserID &&
+					messages[0].Status == domain.OutboxStatusPending &&
+					len(messages[0].Positions) == 1 && messages[0].Events == nil
+			}),
+		).
+		RunAndReturn(func(context.Context, []domain.PositionSnapshot, []domain.OutboxMessage) error {
+			cancel()
+			return nil
+		}).
+		Once()
+
+	service := watcher.NewService(watcher.ServiceConfig{
+		LiquidityPoolPositions: positions,
+		Notifier:               mocks.NewNotifier(s.T()),
+		PositionsHistory:       mocks.NewPositionsHistoryRepository(s.T()),
+		Outbox:                 outbox,
+		CheckInterval:          time.Hour,
+		Logger:                 slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	service.StartWatching(ctx, subject)
+}
+
+func (s *serviceSuite) TestStartWatching_LiveDashboard_PublishedAndOnlyRangeChangesNotified() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1"}).
+		WithCheckInterval(time.Millisecond).
+		Result()
+	subject.LiveDashboard = true
+
+	inRange := makeInRangePosition("1", "0x1")
+	outOfRange := makeOutOfRangePosition("1", "0x1")
+	appeared := makeInRangePosition("2", "0x1")
+
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{inRange}, nil).
+		Once()
+	positions.EXPECT().
+		GetPositionsWithLiquidity(mock.Anything, "0x1").
+		Return([]domain.LiquidityPoolPosition{outOfRange, appeared}, nil)
+
+	dashboard := mocks.NewDashboardPublisher(s.T())
+	dashboard.EXPECT().
+		PublishDashboard(mock.Anything, subject, inRange).
+		Return(nil).
+		Once()
+	dashboard.EXPECT().
+		PublishDashboard(mock.Anything, subject, outOfRange, appeared).
+		Return(nil)
+
+	notifier := mocks.NewNotifier(s.T())
+	notifier.EXPECT().
+		NotifyPositionsEvents(mock.Anything, subject, domain.PositionEvent{
+			Kind:     domain.PositionEventLeftRange,
+			Position: outOfRange,
+		}).
+		RunAndReturn(func(context.Context, domain.Subject, ...domain.PositionEvent) error {
+			cancel()
+			return nil
+		}).
+		Once()
+
+	history := mocks.NewPositionsHistoryRepository(s.T())
+	history.EXPECT().
+		Add(mock.Anything, mock.Anything).
+		Return(nil)
+
+	service := watcher.NewService(watcher.ServiceConfig{
+		LiquidityPoolPositions: positions,
+		Notifier:               notifier,
+		PositionsHistory:       history,
+		Dashboard:              dashboard,
+		CheckInterval:          time.Hour,
+		Logger:                 slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	service.StartWatching(ctx, subject)
+}
+
+func newService(
+	t *testing.T,
+	positions domain.LiquidityPoolPositionsProvider,
+	notifier domain.Notifier,
+) *watcher.Service {
+	t.Helper()
+	return newServiceWithPrices(t, positions, notifier, nil)
+}
+
+func newServiceWithPrices(
+	t *testing.T,
+	positions domain.LiquidityPoolPositionsProvider,
+	notifier domain.Notifier,
+	prices domain.PriceProvider,
+) *watcher.Service {
+	t.Helper()
+
+	history := mocks.NewPositionsHistoryRepository(t)
+	history.EXPECT().
+		Add(mock.Anything, mock.Anything).
+		Return(nil).
+		Maybe()
+
+	config := watcher.ServiceConfig{
+		LiquidityPoolPositions: positions,
+		Notifier:               notifier,
+		PositionsHistory:       history,
+		Prices:                 prices,
+		CheckInterval:          time.Hour,
+		Logger:                 slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	return watcher.NewService(config)
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/sourcegraph/conc"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/tickers"
+)
+
+type SupervisorConfig struct {
+	Subjects        domain.SubjectsRepository
+	Watcher         *Service
+	RefreshInterval time.Duration
+	Logger          *slog.Logger
+}
+
+// Supervisor keeps one watch loop per stored subject with wallets and syncs them with the repository.
+type Supervisor struct {
+	subjects        domain.SubjectsRepository
+	watcher         *Service
+	refreshInterval time.Duration
+	logger          *slog.Logger
+	watching        map[int64]watchingSubject
+}
+
+func NewSupervisor(config SupervisorConfig) *Supervisor {
+	return &Supervisor{
+		subjects:        config.Subjects,
+		watcher:         config.Watcher,
+		refreshInterval: config.RefreshInterval,
+		logger:          config.Logger,
+		watching:        make(map[int64]watchingSubject),
+	}
+}
+
+// Run blocks until ctx is done and all started watch loops are finished.
+func (supervisor *Supervisor) Run(ctx context.Context) {
+	ticker := tickers.NewTickerChanWithInitial(supervisor.refreshInterval)
+	defer ticker.Stop()
+
+	var wg conc.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			supervisor.stopAll()
+			return
+		case <-ticker.C:
+			supervisor.refresh(ctx, &wg)
+		}
+	}
+}
+
+func (supervisor *Supervisor) refresh(ctx context.Context, wg *conc.WaitGroup) {
+	subjects, err := supervisor.subjects.GetAll(ctx)
+	if err != nil {
+		supervisor.logger.Error("SubjectsRepository.GetAll", slog.String("err", err.Error()))
+		return
+	}
+
+	// Subjects without wallets have nothing to watch, e.g. the last wallet is removed.
+	actual := make(map[int64]domain.Subject, len(subjects))
+	for _, subject := range subjects {
+		if len(subject.Wallets) > 0 {
+			actual[subject.TelegramUserID] = subject
+		}
+	}
+
+	for id, watching := range supervisor.watching {
+		if _, ok := actual[id]; !ok {
+			supervisor.stop(id, watching)
+		}
+	}
+
+	for id, subject := range actual {
+		watching, ok := supervisor.watching[id]
+		if ok && reflect.DeepEqual(watching.subject, subject) {
+			continue
+		}
+
+		if ok {
+			supervisor.stop(id, watching)
+		}
+
+		supervisor.start(ctx, wg, subject)
+	}
+}
+
+func (supervisor *Supervisor) start(ctx context.Context, wg *conc.WaitGroup, subject domain.Subject) {
+	subjectCtx, cancel := context.WithCancel(ctx)
+	supervisor.watching[subject.TelegramUserID] = watchingSubject{
+		subject: subject,
+		cancel:  cancel,
+	}
+
+	supervisor.logger.Info("start watching", slog.Int64("subject", subject.TelegramUserID))
+
+	wg.Go(func() {
+		supervisor.watcher.StartWatching(subjectCtx, subject)
+	})
+}
+
+func (supervisor *Supervisor) stop(id int64, watching watchingSubject) {
+	supervisor.logger.Info("stop watching", slog.Int64("subject", id))
+
+	watching.cancel()
+	delete(supervisor.watching, id)
+}
+
+func (supervisor *Supervisor) stopAll() {
+	for id, watching := range supervisor.watching {
+		supervisor.stop(id, watching)
+	}
+}
+
+type watchingSubject struct {
+	subject domain.Subject
+	cancel  context.CancelFunc
+}
@@ -0,0 +1,179 @@
+package watcher_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/watcher"
+	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+const (
+	refreshInterval = 10 * time.Millisecond
+	waitTimeout     = time.Second
+)
+
+type supervisorSuite struct {
+	suite.Suite
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(supervisorSuite))
+}
+
+func (s *supervisorSuite) TestRun_SubjectsChanged_WatchActual() {
+	type TestCase struct {
+		name        string
+		makeUpdated func(initial domain.Subject) domain.Subject
+	}
+
+	testCases := []TestCase{
+		{
+			name: "Added",
+			makeUpdated: func(domain.Subject) domain.Subject {
+				return generators.NewSubjectGenerator().Slim().Result()
+			},
+		},
+		{
+			name: "Changed",
+			makeUpdated: func(initial domain.Subject) domain.Subject {
+				return generators.NewSubjectGenerator().
+					Slim().
+					WithTelegramUserID(initial.TelegramUserID).
+					Result()
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			initial := generators.NewSubjectGenerator().Slim().Result()
+			updated := testCase.makeUpdated(initial)
+
+			var calls atomic.Int32
+			subjects := mocks.NewSubjectsRepository(s.T())
+			subjects.EXPECT().
+				GetAll(mock.Anything).
+				RunAndReturn(func(context.Context) ([]domain.Subject, error) {
+					if calls.Add(1) == 1 {
+						return []domain.Subject{initial}, nil
+					}
+					return []domain.Subject{updated}, nil
+				})
+
+			initialChecked := make(chan struct{}, 1)
+			updatedChecked := make(chan struct{}, 1)
+			positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+			expectCheck(positions, initial, initialChecked)
+			expectCheck(positions, updated, updatedChecked)
+
+			supervisor := newSupervisor(s.T(), subjects, positions)
+
+			finished := make(chan struct{})
+			go func() {
+				supervisor.Run(ctx)
+				close(finished)
+			}()
+
+			s.requireSignal(initialChecked)
+			s.requireSignal(updatedChecked)
+
+			cancel()
+			s.requireSignal(finished)
+		})
+	}
+}
+
+func (s *supervisorSuite) TestRun_SubjectWithoutWallets_NotWatched() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watched := generators.NewSubjectGenerator().Slim().Result()
+	empty := generators.NewSubjectGenerator().
+		Slim().
+		WithTelegramUserID(watched.TelegramUserID + 1).
+		WithWallets([]string{}).
+		Result()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		GetAll(mock.Anything).
+		Return([]domain.Subject{empty, watched}, nil)
+
+	watchedChecked := make(chan struct{}, 1)
+	positions := mocks.NewLiquidityPoolPositionsProvider(s.T())
+	expectCheck(positions, watched, watchedChecked)
+
+	var logs bytes.Buffer
+	supervisor := watcher.NewSupervisor(watcher.SupervisorConfig{
+		Subjects:        subjects,
+		Watcher:         newService(s.T(), positions, mocks.NewNotifier(s.T())),
+		RefreshInterval: refreshInterval,
+		Logger:          slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+
+	finished := make(chan struct{})
+	go func() {
+		supervisor.Run(ctx)
+		close(finished)
+	}()
+
+	s.requireSignal(watchedChecked)
+
+	cancel()
+	s.requireSignal(finished)
+
+	s.Require().Contains(logs.String(), fmt.Sprintf("start watching\" subject=%d\n", watched.TelegramUserID))
+	s.Require().NotContains(logs.String(), fmt.Sprintf("subject=%d\n", empty.TelegramUserID))
+}
+
+func (s *supervisorSuite) requireSignal(signal <-chan struct{}) {
+	select {
+	case <-signal:
+	case <-time.After(waitTimeout):
+		s.FailNow("signal timeout")
+	}
+}
+
+func expectCheck(positions *mocks.LiquidityPoolPositionsProvider, subject domain.Subject, checked chan<- struct{}) {
+	for _, wallet := range subject.Wallets {
+		positions.EXPECT().
+			GetPositionsWithLiquidity(mock.Anything, wallet).
+			RunAndReturn(func(context.Context, string) ([]domain.LiquidityPoolPosition, error) {
+				select {
+				case checked <- struct{}{}:
+				default:
+				}
+				return nil, nil
+			})
+	}
+}
+
+func newSupervisor(
+	t *testing.T,
+	subjects domain.SubjectsRepository,
+	positions domain.LiquidityPoolPositionsProvider,
+) *watcher.Supervisor {
+	t.Helper()
+
+	config := watcher.SupervisorConfig{
+		Subjects:        subjects,
+		Watcher:         newService(t, positions, mocks.NewNotifier(t)),
+		RefreshInterval: refreshInterval,
+		Logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	return watcher.NewSupervisor(config)
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"slices"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+type PositionsTrackerConfig struct {
+	EdgeWarningPercent float64
+	FeesThresholds     map[string]float64
+}
+
+// PositionsTracker remembers positions seen on the previous check and reports what has changed since.
+type PositionsTracker struct {
+	edgeWarningPercent float64
+	feesThresholds     map[string]float64
+	initialized        bool
+	previous           []domain.LiquidityPoolPosition
+}
+
+func NewPositionsTracker(config PositionsTrackerConfig) *PositionsTracker {
+	return &PositionsTracker{
+		edgeWarningPercent: config.EdgeWarningPercent,
+		feesThresholds:     config.FeesThresholds,
+	}
+}
+
+// Track returns events of the positions compared with the previous call.
+// The first call only remembers the positions. Positions of unavailable wallets keep their previous state.
+func (tracker *PositionsTracker) Track(
+	positions []domain.LiquidityPoolPosition,
+	unavailableWallets ...string,
+) []domain.PositionEvent {
+	carried := lo.Filter(tracker.previous, func(position domain.LiquidityPoolPosition, _ int) bool {
+		return lo.Contains(unavailableWallets, position.Wallet)
+	})
+	current := slices.Concat(carried, positions)
+
+	var events []domain.PositionEvent
+	if tracker.initialized {
+		events = tracker.makeEvents(current)
+	}
+
+	tracker.previous = current
+	tracker.initialized = true
+
+	return events
+}
+
+func (tracker *PositionsTracker) makeEvents(current []domain.LiquidityPoolPosition) []domain.PositionEvent {
+	previous := tracker.previous
+	previousByKey := lo.KeyBy(previous, domain.LiquidityPoolPosition.GetKey)
+	currentByKey := lo.KeyBy(current, domain.LiquidityPoolPosition.GetKey)
+
+	var events []domain.PositionEvent
+
+	for _, position := range current {
+		last, ok := previousByKey[position.GetKey()]
+		if !ok {
+			events = append(events, newEvent(domain.PositionEventAppeared, position))
+			continue
+		}
+
+		if kind, changed := tracker.getTransition(last, position); changed {
+			events = append(events, newEvent(kind, position))
+		}
+
+		if tracker.isFeesThresholdReached(last, position) {
+			events = append(events, newEvent(domain.PositionEventFeesExceeded, position))
+		}
+	}
+
+	for _, position := range previous {
+		if _, ok := currentByKey[position.GetKey()]; !ok {
+			events = append(events, newEvent(domain.PositionEventDisappeared, position))
+		}
+	}
+
+	return events
+}
+
+// getTransition returns event of the position status change. Leaving the edge zone back to the range
+// is not an event, so the next approach is reported again.
+func (tracker *PositionsTracker) getTransition(
+	last, position domain.LiquidityPoolPosition,
+) (domain.PositionEventKind, bool) {
+	lastStatus := last.GetStatus(tracker.edgeWarningPercent)
+	status := position.GetStatus(tracker.edgeWarningPercent)
+
+	switch {
+	case lastStatus == status:
+		return "", false
+	case status == domain.PositionStatusOutOfRange:
+		return domain.PositionEventLeftRange, true
+	case lastStatus == domain.PositionStatusOutOfRange:
+		return domain.PositionEventReturnedToRange, true
+	case status == domain.PositionStatusNearEdge:
+		return domain.PositionEventApproachingEdge, true
+	default:
+		return "", false
+	}
+}
+
+// isFeesThresholdReached reports whether fees of any token reached its threshold since the last check.
+// After the fees are collected the threshold is reported again.
+func (tracker *PositionsTracker) isFeesThresholdReached(last, position domain.LiquidityPoolPosition) bool {
+	if len(tracker.feesThresholds) == 0 {
+		return false
+	}
+
+	exceeding := position.GetFeesExceeding(tracker.feesThresholds)
+	lastExceeding := last.GetFeesExceeding(tracker.feesThresholds)
+
+	return len(lo.Without(exceeding, lastExceeding...)) > 0
+}
+
+func newEvent(kind domain.PositionEventKind, position domain.LiquidityPoolPosition) domain.PositionEvent {
+	return domain.PositionEvent{
+		Kind:     kind,
+		Position: position,
+	}
+}
@@ -0,0 +1,210 @@
+package watcher_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/watcher"
+)
+
+const (
+	edgeWarningPercent = 10
+	usdcFeesThreshold  = 10
+)
+
+type trackerSuite struct {
+	suite.Suite
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(trackerSuite))
+}
+
+func (s *trackerSuite) TestTrack_FirstCall_NoEvents() {
+	tracker := newTracker()
+
+	events := tracker.Track([]domain.LiquidityPoolPosition{makeInRangePosition("1", "0x1")})
+
+	s.Require().Empty(events)
+}
+
+func (s *trackerSuite) TestTrack_Changed_Events() {
+	type TestCase struct {
+		name           string
+		previous       []domain.LiquidityPoolPosition
+		current        []domain.LiquidityPoolPosition
+		expectedEvents []domain.PositionEvent
+	}
+
+	inRange := makeInRangePosition("1", "0x1")
+	outOfRange := makeOutOfRangePosition("1", "0x1")
+	nearEdge := makeNearEdgePosition("1", "0x1")
+	another := makeInRangePosition("2", "0x1")
+
+	testCases := []TestCase{
+		{
+			name:     "Nothing changed",
+			previous: []domain.LiquidityPoolPosition{inRange},
+			current:  []domain.LiquidityPoolPosition{inRange},
+		},
+		{
+			name:     "Left range",
+			previous: []domain.LiquidityPoolPosition{inRange},
+			current:  []domain.LiquidityPoolPosition{outOfRange},
+			expectedEvents: []domain.PositionEvent{
+				{Kind: domain.PositionEventLeftRange, Position: outOfRange},
+			},
+		},
+		{
+			name:     "Returned to range",
+			previous: []domain.LiquidityPoolPosition{outOfRange},
+			current:  []domain.LiquidityPoolPosition{inRange},
+			expectedEvents: []domain.PositionEvent{
+				{Kind: domain.PositionEventReturnedToRange, Position: inRange},
+			},
+		},
+		{
+			name:     "Approaching edge",
+			previous: []domain.LiquidityPoolPosition{inRange},
+			current:  []domain.LiquidityPoolPosition{nearEdge},
+			expectedEvents: []domain.PositionEvent{
+				{Kind: domain.PositionEventApproachingEdge, Position: nearEdge},
+			},
+		},
+		{
+			name:     "Still near edge",
+			previous: []domain.LiquidityPoolPosition{nearEdge},
+			current:  []domain.LiquidityPoolPosition{nearEdge},
+		},
+		{
+			name:     "Moved away from edge",
+			previous: []domain.LiquidityPoolPosition{nearEdge},
+			current:  []domain.LiquidityPoolPosition{inRange},
+		},
+		{
+			name:     "Returned to range near edge",
+			previous: []domain.LiquidityPoolPosition{outOfRange},
+			current:  []domain.LiquidityPoolPosition{nearEdge},
+			expectedEvents: []domain.PositionEvent{
+				{Kind: domain.PositionEventReturnedToRange, Position: nearEdge},
+			},
+		},
+		{
+			name:     "Left range from edge",
+			previous: []domain.LiquidityPoolPosition{nearEdge},
+			current:  []domain.LiquidityPoolPosition{outOfRange},
+			expectedEvents: []domain.PositionEvent{
+				{Kind: domain.PositionEventLeftRange, Position: outOfRange},
+			},
+		},
+		{
+			name:     "Appeared and disappeared",
+			previous: []domain.LiquidityPoolPosition{inRange},
+			current:  []domain.LiquidityPoolPosition{another},
+			expectedEvents: []domain.PositionEvent{
+				{Kind: domain.PositionEventAppeared, Position: another},
+				{Kind: domain.PositionEventDisappeared, Position: inRange},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			tracker := newTracker()
+			tracker.Track(testCase.previous)
+
+			events := tracker.Track(testCase.current)
+
+			s.Require().Equal(testCase.expectedEvents, events)
+		})
+	}
+}
+
+func (s *trackerSuite) TestTrack_FeesChanged_Events() {
+	type TestCase struct {
+		name           string
+		previousFees   int64
+		currentFees    int64
+		expectedEvents bool
+	}
+
+	testCases := []TestCase{
+		{name: "Below threshold", previousFees: 1_000_000, currentFees: 9_000_000},
+		{name: "Reached threshold", previousFees: 9_000_000, currentFees: 10_000_000, expectedEvents: true},
+		{name: "Still above threshold", previousFees: 10_000_000, currentFees: 11_000_000},
+		{name: "Collected", previousFees: 11_000_000, currentFees: 0},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			previous := makeInRangePosition("1", "0x1")
+			previous.UncollectedFees1 = big.NewInt(testCase.previousFees)
+			current := makeInRangePosition("1", "0x1")
+			current.UncollectedFees1 = big.NewInt(testCase.currentFees)
+
+			tracker := newTracker()
+			tracker.Track([]domain.LiquidityPoolPosition{previous})
+
+			events := tracker.Track([]domain.LiquidityPoolPosition{current})
+
+			if !testCase.expectedEvents {
+				s.Require().Empty(events)
+				return
+			}
+			s.Require().Equal([]domain.PositionEvent{
+				{Kind: domain.PositionEventFeesExceeded, Position: current},
+			}, events)
+		})
+	}
+}
+
+func (s *trackerSuite) TestTrack_WalletUnavailable_KeepPreviousState() {
+	tracker := newTracker()
+	tracker.Track([]domain.LiquidityPoolPosition{
+		makeInRangePosition("1", "0x1"),
+		makeInRangePosition("2", "0x2"),
+	})
+
+	events := tracker.Track([]domain.LiquidityPoolPosition{makeInRangePosition("2", "0x2")}, "0x1")
+	s.Require().Empty(events)
+
+	events = tracker.Track([]domain.LiquidityPoolPosition{makeInRangePosition("2", "0x2")})
+	s.Require().Equal([]domain.PositionEvent{
+		{Kind: domain.PositionEventDisappeared, Position: makeInRangePosition("1", "0x1")},
+	}, events)
+}
+
+func newTracker() *watcher.PositionsTracker {
+	return watcher.NewPositionsTracker(watcher.PositionsTrackerConfig{
+		EdgeWarningPercent: edgeWarningPercent,
+		FeesThresholds:     map[string]float64{"USDC": usdcFeesThreshold},
+	})
+}
+
+func makeInRangePosition(id, wallet string) domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		ID:          id,
+		Wallet:      wallet,
+		Chain:       domain.ChainBase,
+		Dex:         domain.DexUniswapV3,
+		Token0:      domain.Token{Name: "WETH", Decimals: 18},
+		Token1:      domain.Token{Name: "USDC", Decimals: 6},
+		TickLower:   -10,
+		CurrentTick: 0,
+		TickUpper:   10,
+	}
+}
+
+func makeNearEdgePosition(id, wallet string) domain.LiquidityPoolPosition {
+	position := makeInRangePosition(id, wallet)
+	position.CurrentTick = position.TickUpper - 1
+	return position
+}
+
+func makeOutOfRangePosition(id, wallet string) domain.LiquidityPoolPosition {
+	position := makeInRangePosition(id, wallet)
+	position.CurrentTick = position.TickUpper + 1
+	return position
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"math/big"
+)
+
+const (
+	// MinTick and MaxTick are the bounds of ticks supported by Uniswap V3 pools.
+	MinTick = -887272
+	MaxTick = 887272
+
+	q96Bits  = 96
+	q128Bits = 128
+	q32Bits  = 32
+)
+
+// GetSqrtRatioAtTick calculates sqrt(1.0001^tick) * 2^96 exactly as Uniswap V3 TickMath does.
+func GetSqrtRatioAtTick(tick int) *big.Int {
+	if tick < MinTick || tick > MaxTick {
+		message := "GetSqrtRatioAtTick: tick out of bounds"
+		panic(message)
+	}
+
+	absTick := tick
+	if absTick < 0 {
+		absTick = -absTick
+	}
+
+	ratio := new(big.Int).Lsh(big.NewInt(1), q128Bits)
+	for bit, factor := range sqrtRatioFactors() {
+		if absTick&(1<<bit) != 0 {
+			ratio.Mul(ratio, factor)
+			ratio.Rsh(ratio, q128Bits)
+		}
+	}
+
+	if tick > 0 {
+		maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)) //nolint:mnd // Bits.
+		ratio.Div(maxUint256, ratio)
+	}
+
+	// Round up to guarantee that getTickAtSqrtRatio(getSqrtRatioAtTick(tick)) == tick.
+	remainder := new(big.Int).And(ratio, big.NewInt(1<<q32Bits-1))
+	ratio.Rsh(ratio, q32Bits)
+	if remainder.Sign() != 0 {
+		ratio.Add(ratio, big.NewInt(1))
+	}
+
+	return ratio
+}
+
+// GetAmountsForLiquidity calculates token amounts held by liquidity between sqrtRatioA and sqrtRatioB
+// at the current sqrtRatio, rounding down as Uniswap V3 LiquidityAmounts does.
+func GetAmountsForLiquidity(sqrtRatio, sqrtRatioA, sqrtRatioB, liquidity *big.Int) (amount0, amount1 *big.Int) {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	switch {
+	case sqrtRatio.Cmp(sqrtRatioA) <= 0:
+		return getAmount0ForLiquidity(sqrtRatioA, sqrtRatioB, liquidity), new(big.Int)
+	case sqrtRatio.Cmp(sqrtRatioB) < 0:
+		return getAmount0ForLiquidity(sqrtRatio, sqrtRatioB, liquidity),
+			getAmount1ForLiquidity(sqrtRatioA, sqrtRatio, liquidity)
+	default:
+		return new(big.Int), getAmount1ForLiquidity(sqrtRatioA, sqrtRatioB, liquidity)
+	}
+}
+
+// FeeGrowth is the fee accounting state of the position, pool and its range bound ticks
+// needed to calculate uncollected fees. Growth values are Q128.128 per unit of liquidity.
+type FeeGrowth struct {
+	Global0X128       *big.Int
+	Global1X128       *big.Int
+	OutsideLower0X128 *big.Int
+	OutsideLower1X128 *big.Int
+	OutsideUpper0X128 *big.Int
+	OutsideUpper1X128 *big.Int
+	InsideLast0X128   *big.Int
+	InsideLast1X128   *big.Int
+	TokensOwed0       *big.Int
+	TokensOwed1       *big.Int
+}
+
+// GetUncollectedFees calculates fees the position would receive on collect, the same way
+// as NonfungiblePositionManager does. Fee growth values overflow by design, so math is modulo 2^256.
+func GetUncollectedFees(
+	tickCurrent, tickLower, tickUpper int,
+	liquidity *big.Int,
+	growth FeeGrowth,
+) (fees0, fees1 *big.Int) {
+	inside0 := getFeeGrowthInside(
+		tickCurrent, tickLower, tickUpper, growth.Global0X128, growth.OutsideLower0X128, growth.OutsideUpper0X128,
+	)
+	inside1 := getFeeGrowthInside(
+		tickCurrent, tickLower, tickUpper, growth.Global1X128, growth.OutsideLower1X128, growth.OutsideUpper1X128,
+	)
+
+	fees0 = getFeesForLiquidity(inside0, growth.InsideLast0X128, liquidity)
+	fees1 = getFeesForLiquidity(inside1, growth.InsideLast1X128, liquidity)
+
+	return fees0.Add(fees0, orZero(growth.TokensOwed0)), fees1.Add(fees1, orZero(growth.TokensOwed1))
+}
+
+// getFeeGrowthInside calculates fee growth inside the range from the growth outside of its bound ticks.
+func getFeeGrowthInside(tickCurrent, tickLower, tickUpper int, global, outsideLower, outsideUpper *big.Int) *big.Int {
+	below := orZero(outsideLower)
+	if tickCurrent < tickLower {
+		below = subMod256(global, below)
+	}
+
+	above := orZero(outsideUpper)
+	if tickCurrent >= tickUpper {
+		above = subMod256(global, above)
+	}
+
+	return subMod256(subMod256(global, below), above)
+}
+
+// getFeesForLiquidity calculates (feeGrowthInside - feeGrowthInsideLast) * liquidity / 2^128.
+func getFeesForLiquidity(inside, insideLast, liquidity *big.Int) *big.Int {
+	fees := subMod256(inside, insideLast)
+	fees.Mul(fees, orZero(liquidity))
+	return fees.Rsh(fees, q128Bits)
+}
+
+func subMod256(a, b *big.Int) *big.Int {
+	result := new(big.Int).Sub(orZero(a), orZero(b))
+	return result.Mod(result, new(big.Int).Lsh(big.NewInt(1), 256)) //nolint:mnd // Bits.
+}
+
+func orZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value
+}
+
+// getAmount0ForLiquidity calculates liquidity * 2^96 * (sqrtRatioB - sqrtRatioA) / sqrtRatioB / sqrtRatioA.
+func getAmount0ForLiquidity(sqrtRatioA, sqrtRatioB, liquidity *big.Int) *big.Int {
+	amount := new(big.Int).Lsh(liquidity, q96Bits)
+	amount.Mul(amount, new(big.Int).Sub(sqrtRatioB, sqrtRatioA))
+	amount.Div(amount, sqrtRatioB)
+	return amount.Div(amount, sqrtRatioA)
+}
+
+// getAmount1ForLiquidity calculates liquidity * (sqrtRatioB - sqrtRatioA) / 2^96.
+func getAmount1ForLiquidity(sqrtRatioA, sqrtRatioB, liquidity *big.Int) *big.Int {
+	amount := new(big.Int).Mul(liquidity, new(big.Int).Sub(sqrtRatioB, sqrtRatioA))
+	return amount.Rsh(amount, q96Bits)
+}
+
+// sqrtRatioFactors returns 2^128 / sqrt(1.0001^(2^i)) for every bit of the tick.
+func sqrtRatioFactors() []*big.Int {
+	hexFactors := []string{
+		"fffcb933bd6fad37aa2d162d1a594001",
+		"fff97272373d413259a46990580e213a",
+		"fff2e50f5f656932ef12357cf3c7fdcc",
+		"ffe5caca7e10e4e61c3624eaa0941cd0",
+		"ffcb9843d60f6159c9db58835c926644",
+		"ff973b41fa98c081472e6896dfb254c0",
+		"ff2ea16466c96a3843ec78b326b52861",
+		"fe5dee046a99a2a811c461f1969c3053",
+		"fcbe86c7900a88aedcffc83b479aa3a4",
+		"f987a7253ac413176f2b074cf7815e54",
+		"f3392b0822b70005940c7a398e4b70f3",
+		"e7159475a2c29b7443b29c7fa6e889d9",
+		"d097f3bdfd2022b8845ad8f792aa5825",
+		"a9f746462d870fdf8a65dc1f90e061e5",
+		"70d869a156d2a1b890bb3df62baf32f7",
+		"31be135f97d08fd981231505542fcfa6",
+		"9aa508b5b7a84e1c677de54f3e99bc9",
+		"5d6af8dedb81196699c329225ee604",
+		"2216e584f5fa1ea926041bedfe98",
+		"48a170391f7dc42444e8fa2",
+	}
+
+	factors := make([]*big.Int, 0, len(hexFactors))
+	for _, hexFactor := range hexFactors {
+		factor, ok := new(big.Int).SetString(hexFactor, 16) //nolint:mnd // Hex.
+		if !ok {
+			message := "sqrtRatioFactors: invalid factor " + hexFactor
+			panic(message)
+		}
+		factors = append(factors, factor)
+	}
+
+	return factors
+}
@@ -0,0 +1,133 @@
+package domain_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+type uniswapV3MathSuite struct {
+	suite.Suite
+}
+
+func TestUniswapV3Math(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(uniswapV3MathSuite))
+}
+
+func (s *uniswapV3MathSuite) TestGetSqrtRatioAtTick() {
+	type TestCase struct {
+		name     string
+		tick     int
+		expected string
+	}
+
+	testCases := []TestCase{
+		{name: "Min tick", tick: domain.MinTick, expected: "4295128739"},
+		{name: "Negative tick", tick: -60, expected: "78990846045029531151608375686"},
+		{name: "Zero tick", tick: 0, expected: "79228162514264337593543950336"},
+		{name: "Positive tick", tick: 60, expected: "79466191966197645195421774833"},
+		{name: "Max tick", tick: domain.MaxTick, expected: "1461446703485210103287273052203988822378723970342"},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			s.Require().Equal(testCase.expected, domain.GetSqrtRatioAtTick(testCase.tick).String())
+		})
+	}
+}
+
+func (s *uniswapV3MathSuite) TestGetAmountsForLiquidity() {
+	type TestCase struct {
+		name            string
+		tick            int
+		expectedAmount0 string
+		expectedAmount1 string
+	}
+
+	testCases := []TestCase{
+		{name: "Below range", tick: -120, expectedAmount0: "5999709018652706", expectedAmount1: "0"},
+		{name: "In range", tick: 0, expectedAmount0: "2995354955910780", expectedAmount1: "2995354955910780"},
+		{name: "Above range", tick: 120, expectedAmount0: "0", expectedAmount1: "5999709018652706"},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			amount0, amount1 := domain.GetAmountsForLiquidity(
+				domain.GetSqrtRatioAtTick(testCase.tick),
+				domain.GetSqrtRatioAtTick(-60),
+				domain.GetSqrtRatioAtTick(60),
+				big.NewInt(1_000_000_000_000_000_000),
+			)
+
+			s.Require().Equal(testCase.expectedAmount0, amount0.String())
+			s.Require().Equal(testCase.expectedAmount1, amount1.String())
+		})
+	}
+}
+
+func (s *uniswapV3MathSuite) TestGetUncollectedFees() {
+	type TestCase struct {
+		name          string
+		tickCurrent   int
+		growth        domain.FeeGrowth
+		expectedFees0 string
+		expectedFees1 string
+	}
+
+	testCases := []TestCase{
+		{
+			name:        "In range",
+			tickCurrent: 0,
+			growth: domain.FeeGrowth{
+				Global0X128:       q128(10),
+				Global1X128:       q128(20),
+				OutsideLower0X128: q128(2),
+				OutsideLower1X128: q128(4),
+				OutsideUpper0X128: q128(3),
+				OutsideUpper1X128: q128(6),
+				InsideLast0X128:   q128(1),
+				InsideLast1X128:   q128(2),
+				TokensOwed0:       big.NewInt(7),
+			},
+			expectedFees0: "4007",
+			expectedFees1: "8000",
+		},
+		{
+			name:        "Below range",
+			tickCurrent: -120,
+			growth: domain.FeeGrowth{
+				Global0X128:       q128(10),
+				OutsideLower0X128: q128(4),
+				OutsideUpper0X128: q128(1),
+				InsideLast0X128:   q128(2),
+			},
+			expectedFees0: "1000",
+			expectedFees1: "0",
+		},
+		{
+			name:        "Growth overflow",
+			tickCurrent: 0,
+			growth: domain.FeeGrowth{
+				Global0X128:       q128(1),
+				OutsideLower0X128: q128(3),
+				InsideLast0X128:   new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), q128(4)),
+			},
+			expectedFees0: "2000",
+			expectedFees1: "0",
+		},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			fees0, fees1 := domain.GetUncollectedFees(testCase.tickCurrent, -60, 60, big.NewInt(1000), testCase.growth)
+
+			s.Require().Equal(testCase.expectedFees0, fees0.String())
+			s.Require().Equal(testCase.expectedFees1, fees1.String())
+		})
+	}
+}
+
+func q128(value int64) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(value), 128)
+}
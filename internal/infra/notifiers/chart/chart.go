@@ -0,0 +1,232 @@
+// Package chart draws the position range with the price as PNG image, so it is seen at a glance.
+// Only the standard image packages are used, so the image has no text and is meant to be captioned.
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"slices"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+const (
+	width   = 640
+	height  = 240
+	padding = 16
+
+	lineWidth    = 2
+	dashLength   = 8
+	markerRadius = 6
+	// marginRatio is a share of the prices span added above and below, so the range is not at the image edges.
+	marginRatio = 0.15
+)
+
+// RenderRange draws the position range as a band, the price history of the timeline as a line across it
+// and the current price as a marker at the right edge. The history is not drawn if there are less than
+// two snapshots.
+func RenderRange(position domain.LiquidityPoolPosition, timeline domain.PositionTimeline) ([]byte, error) {
+	colors := newPalette(position.IsInRange())
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colors.background), image.Point{}, draw.Src)
+
+	area := newPlotArea(img.Bounds().Inset(padding), position, timeline)
+
+	area.drawRange(img, position, colors)
+	area.drawHistory(img, timeline, colors.history)
+	area.drawMarker(img, position.GetCurrentPrice(), colors.marker)
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, img)
+	if err != nil {
+		return nil, fmt.Errorf("png.Encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type palette struct {
+	background color.RGBA
+	band       color.RGBA
+	bound      color.RGBA
+	history    color.RGBA
+	marker     color.RGBA
+}
+
+// newPalette returns green colors for the position in range and red ones otherwise.
+//
+//nolint:mnd // RGBA components of the colors.
+func newPalette(inRange bool) palette {
+	colors := palette{
+		background: color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
+		band:       color.RGBA{R: 0xDC, G: 0xF2, B: 0xE0, A: 0xFF},
+		bound:      color.RGBA{R: 0x2E, G: 0x9E, B: 0x4F, A: 0xFF},
+		history:    color.RGBA{R: 0x3B, G: 0x6E, B: 0xD8, A: 0xFF},
+		marker:     color.RGBA{R: 0x2E, G: 0x9E, B: 0x4F, A: 0xFF},
+	}
+
+	if !inRange {
+		colors.band = color.RGBA{R: 0xFB, G: 0xE0, B: 0xE0, A: 0xFF}
+		colors.bound = color.RGBA{R: 0xC6, G: 0x3B, B: 0x3B, A: 0xFF}
+		colors.marker = color.RGBA{R: 0xC6, G: 0x3B, B: 0x3B, A: 0xFF}
+	}
+
+	return colors
+}
+
+// plotArea maps prices to the vertical axis and check time of the snapshots to the horizontal one.
+type plotArea struct {
+	bounds   image.Rectangle
+	minPrice float64
+	maxPrice float64
+	from     time.Time
+	to       time.Time
+}
+
+func newPlotArea(
+	bounds image.Rectangle,
+	position domain.LiquidityPoolPosition,
+	timeline domain.PositionTimeline,
+) plotArea {
+	prices := append(
+		getPrices(timeline),
+		position.GetLowerPrice(),
+		position.GetUpperPrice(),
+		position.GetCurrentPrice(),
+	)
+
+	minPrice, maxPrice := slices.Min(prices), slices.Max(prices)
+
+	margin := (maxPrice - minPrice) * marginRatio
+	if margin == 0 {
+		margin = 1
+	}
+
+	area := plotArea{
+		bounds:   bounds,
+		minPrice: minPrice - margin,
+		maxPrice: maxPrice + margin,
+	}
+	if len(timeline) > 0 {
+		area.from = timeline[0].CheckedAt
+		area.to = timeline[len(timeline)-1].CheckedAt
+	}
+
+	return area
+}
+
+// drawHistory draws the price line of the snapshots spread by check time.
+func (area plotArea) drawHistory(img *image.RGBA, timeline domain.PositionTimeline, c color.RGBA) {
+	if len(timeline) <= 1 || !area.to.After(area.from) {
+		return
+	}
+
+	for i := 1; i < len(timeline); i++ {
+		drawLine(img, area.getPoint(timeline[i-1]), area.getPoint(timeline[i]), c)
+	}
+}
+
+// drawMarker draws the dashed line of the price with the dot at the right edge.
+func (area plotArea) drawMarker(img *image.RGBA, price float64, c color.RGBA) {
+	y := area.getY(price)
+
+	for x := area.bounds.Min.X; x < area.bounds.Max.X; x += dashLength + dashLength {
+		dash := image.Rect(x, y, min(x+dashLength, area.bounds.Max.X), y+1)
+		draw.Draw(img, dash, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+
+	drawCircle(img, image.Pt(area.bounds.Max.X-markerRadius, y), markerRadius, c)
+}
+
+// drawRange fills the band between the range bounds and draws the bounds.
+func (area plotArea) drawRange(img *image.RGBA, position domain.LiquidityPoolPosition, colors palette) {
+	upper := area.getY(position.GetUpperPrice())
+	lower := area.getY(position.GetLowerPrice())
+
+	band := image.Rect(area.bounds.Min.X, upper, area.bounds.Max.X, lower)
+	draw.Draw(img, band, image.NewUniform(colors.band), image.Point{}, draw.Src)
+
+	for _, y := range []int{upper, lower} {
+		bound := image.Rect(area.bounds.Min.X, y, area.bounds.Max.X, y+lineWidth)
+		draw.Draw(img, bound, image.NewUniform(colors.bound), image.Point{}, draw.Src)
+	}
+}
+
+func (area plotArea) getPoint(snapshot domain.PositionSnapshot) image.Point {
+	share := float64(snapshot.CheckedAt.Sub(area.from)) / float64(area.to.Sub(area.from))
+	x := area.bounds.Min.X + int(share*float64(area.bounds.Dx()))
+
+	return image.Pt(x, area.getY(snapshot.Position.GetCurrentPrice()))
+}
+
+// getY returns the row of the price, higher prices are at the top.
+func (area plotArea) getY(price float64) int {
+	share := (area.maxPrice - price) / (area.maxPrice - area.minPrice)
+
+	return area.bounds.Min.Y + int(share*float64(area.bounds.Dy()))
+}
+
+func getPrices(timeline domain.PositionTimeline) []float64 {
+	return lo.Map(timeline, func(snapshot domain.PositionSnapshot, _ int) float64 {
+		return snapshot.Position.GetCurrentPrice()
+	})
+}
+
+// drawLine draws the line of the points by Bresenham's algorithm.
+func drawLine(img *image.RGBA, from, to image.Point, c color.RGBA) {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	stepX, stepY := getStep(from.X, to.X), getStep(from.Y, to.Y)
+	delta := dx + dy
+
+	for point := from; ; {
+		dot := image.Rectangle{Min: point, Max: point.Add(image.Pt(lineWidth, lineWidth))}
+		draw.Draw(img, dot, image.NewUniform(c), image.Point{}, draw.Src)
+
+		if point == to {
+			return
+		}
+
+		doubled := delta + delta
+		if doubled >= dy {
+			delta += dy
+			point.X += stepX
+		}
+		if doubled <= dx {
+			delta += dx
+			point.Y += stepY
+		}
+	}
+}
+
+func drawCircle(img *image.RGBA, center image.Point, radius int, c color.RGBA) {
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				img.SetRGBA(center.X+x, center.Y+y, c)
+			}
+		}
+	}
+}
+
+func getStep(from, to int) int {
+	if from < to {
+		return 1
+	}
+	return -1
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
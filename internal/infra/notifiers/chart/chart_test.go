@@ -0,0 +1,95 @@
+package chart_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chart"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type chartSuite struct {
+	suite.Suite
+}
+
+func TestChart(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(chartSuite))
+}
+
+func (s *chartSuite) TestRenderRange_Success() {
+	type TestCase struct {
+		name            string
+		currentTick     int
+		withHistory     bool
+		expectedColor   color.RGBA
+		unexpectedColor color.RGBA
+	}
+
+	green := color.RGBA{R: 0x2E, G: 0x9E, B: 0x4F, A: 0xFF}
+	red := color.RGBA{R: 0xC6, G: 0x3B, B: 0x3B, A: 0xFF}
+	blue := color.RGBA{R: 0x3B, G: 0x6E, B: 0xD8, A: 0xFF}
+
+	testCases := []TestCase{
+		{name: "In range", currentTick: 500, expectedColor: green, unexpectedColor: red},
+		{name: "Out of range", currentTick: 1500, expectedColor: red, unexpectedColor: green},
+		{name: "Without history", currentTick: 500, expectedColor: green, unexpectedColor: blue},
+		{name: "With history", currentTick: 500, withHistory: true, expectedColor: blue, unexpectedColor: red},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			position := generators.NewPositionGenerator().Slim().Result()
+			position.TickLower = 0
+			position.TickUpper = 1000
+			position.CurrentTick = testCase.currentTick
+
+			var timeline domain.PositionTimeline
+			if testCase.withHistory {
+				timeline = makeTimeline(position, 200, 800, testCase.currentTick)
+			}
+
+			data, err := chart.RenderRange(position, timeline)
+			s.Require().NoError(err)
+
+			img, err := png.Decode(bytes.NewReader(data))
+			s.Require().NoError(err)
+			s.Require().Equal(image.Rect(0, 0, 640, 240), img.Bounds())
+			s.Require().True(hasColor(img, testCase.expectedColor))
+			s.Require().False(hasColor(img, testCase.unexpectedColor))
+		})
+	}
+}
+
+func makeTimeline(position domain.LiquidityPoolPosition, ticks ...int) domain.PositionTimeline {
+	checkedAt := time.Now().Add(-time.Hour)
+
+	timeline := make(domain.PositionTimeline, 0, len(ticks))
+	for i, tick := range ticks {
+		snapshot := domain.PositionSnapshot{
+			Position:  position,
+			CheckedAt: checkedAt.Add(time.Duration(i) * time.Minute),
+		}
+		snapshot.Position.CurrentTick = tick
+		timeline = append(timeline, snapshot)
+	}
+
+	return timeline
+}
+
+func hasColor(img image.Image, expected color.RGBA) bool {
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Package chatwebhook posts JSON messages to incoming webhooks of chat services like Discord and Slack.
+//
+// Any 2xx response is a success. Messages rejected with 429 Too Many Requests are resent after the delay
+// the service asks for with the retry_after body field or the Retry-After header, other failures are
+// returned immediately.
+package chatwebhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// defaultRetryAfter is used when the rejected response does not tell the delay.
+const defaultRetryAfter = time.Second
+
+var ErrUnexpectedStatus = errors.New("unexpected http status")
+
+type PosterConfig struct {
+	HTTPClient *http.Client
+	// MaxRetries limits resending of a message rejected with 429 Too Many Requests.
+	MaxRetries int
+}
+
+type Poster struct {
+	config PosterConfig
+}
+
+func NewPoster(config PosterConfig) *Poster {
+	return &Poster{
+		config: config,
+	}
+}
+
+// Post sends the message as JSON waiting for the rate limits of the webhook.
+func (p *Poster) Post(ctx context.Context, url string, message any) error {
+	body, err := jsoniter.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("jsoniter.Marshal: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := p.post(ctx, url, body)
+		if err == nil {
+			return nil
+		}
+
+		if retryAfter == 0 || attempt >= p.config.MaxRetries {
+			return err
+		}
+
+		err = sleep(ctx, retryAfter)
+		if err != nil {
+			return fmt.Errorf("sleep: %w", err)
+		}
+	}
+}
+
+// post delivers the body once, retryAfter is not zero if the webhook is rate limited.
+func (p *Poster) post(ctx context.Context, url string, body []byte) (time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := p.config.HTTPClient.Do(request)
+	if err != nil {
+		return 0, fmt.Errorf("http.Do: %w", err)
+	}
+	defer response.Body.Close() //nolint:errcheck // Nothing to do with the error.
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return 0, nil
+	}
+
+	reason, _ := io.ReadAll(response.Body)
+	err = fmt.Errorf("%w: %s: %s", ErrUnexpectedStatus, response.Status, reason)
+
+	if response.StatusCode != http.StatusTooManyRequests {
+		return 0, err
+	}
+
+	return getRetryAfter(response.Header, reason), err
+}
+
+// getRetryAfter reads the delay from the Discord body field in fractional seconds, then from the header.
+func getRetryAfter(header http.Header, body []byte) time.Duration {
+	if seconds := jsoniter.Get(body, "retry_after").ToFloat64(); seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return defaultRetryAfter
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
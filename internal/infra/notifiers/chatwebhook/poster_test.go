@@ -0,0 +1,145 @@
+package chatwebhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+)
+
+type posterSuite struct {
+	suite.Suite
+}
+
+func TestPoster(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(posterSuite))
+}
+
+func (s *posterSuite) TestPost_Success_JSONPosted() {
+	stub := newWebhookStub(response{status: http.StatusNoContent})
+	defer stub.Close()
+
+	err := s.newPoster(stub, 0).Post(context.Background(), stub.URL, map[string]string{"text": "hello"})
+	s.Require().NoError(err)
+
+	s.Require().Equal([]string{`{"text":"hello"}`}, stub.bodies)
+	s.Require().Equal("application/json", stub.contentType)
+}
+
+func (s *posterSuite) TestPost_TooManyRequests_ResentAfterBodyDelay() {
+	stub := newWebhookStub(
+		response{status: http.StatusTooManyRequests, body: `{"retry_after": 0.05}`},
+		response{status: http.StatusOK},
+	)
+	defer stub.Close()
+
+	start := time.Now()
+	err := s.newPoster(stub, 1).Post(context.Background(), stub.URL, map[string]string{})
+	s.Require().NoError(err)
+
+	s.Require().Len(stub.bodies, 2)
+	s.Require().GreaterOrEqual(time.Since(start), 50*time.Millisecond)
+}
+
+func (s *posterSuite) TestPost_TooManyRequests_ResentAfterHeaderDelay() {
+	stub := newWebhookStub(
+		response{status: http.StatusTooManyRequests, retryAfter: "1"},
+		response{status: http.StatusOK},
+	)
+	defer stub.Close()
+
+	start := time.Now()
+	err := s.newPoster(stub, 1).Post(context.Background(), stub.URL, map[string]string{})
+	s.Require().NoError(err)
+
+	s.Require().Len(stub.bodies, 2)
+	s.Require().GreaterOrEqual(time.Since(start), time.Second)
+}
+
+func (s *posterSuite) TestPost_TooManyRequestsExhausted_Error() {
+	stub := newWebhookStub(response{status: http.StatusTooManyRequests, body: `{"retry_after": 0.01}`})
+	defer stub.Close()
+
+	err := s.newPoster(stub, 2).Post(context.Background(), stub.URL, map[string]string{})
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+
+	s.Require().Len(stub.bodies, 3)
+}
+
+func (s *posterSuite) TestPost_Rejected_NotResent() {
+	stub := newWebhookStub(response{status: http.StatusBadRequest, body: "invalid payload"})
+	defer stub.Close()
+
+	err := s.newPoster(stub, 3).Post(context.Background(), stub.URL, map[string]string{})
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+	s.Require().ErrorContains(err, "invalid payload")
+
+	s.Require().Len(stub.bodies, 1)
+}
+
+func (s *posterSuite) TestPost_ContextCanceled_NotResent() {
+	stub := newWebhookStub(response{status: http.StatusTooManyRequests, retryAfter: "60"})
+	defer stub.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := s.newPoster(stub, 1).Post(ctx, stub.URL, map[string]string{})
+	s.Require().ErrorIs(err, context.DeadlineExceeded)
+
+	s.Require().Len(stub.bodies, 1)
+}
+
+func (s *posterSuite) newPoster(stub *webhookStub, maxRetries int) *chatwebhook.Poster {
+	return chatwebhook.NewPoster(chatwebhook.PosterConfig{
+		HTTPClient: stub.Client(),
+		MaxRetries: maxRetries,
+	})
+}
+
+type response struct {
+	status     int
+	body       string
+	retryAfter string
+}
+
+// webhookStub records bodies of the posted messages and answers with the responses in order,
+// the last one is repeated.
+type webhookStub struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	responses   []response
+	bodies      []string
+	contentType string
+}
+
+func newWebhookStub(responses ...response) *webhookStub {
+	stub := &webhookStub{responses: responses}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+
+		stub.bodies = append(stub.bodies, string(body))
+		stub.contentType = request.Header.Get("Content-Type")
+
+		answer := stub.responses[min(len(stub.bodies), len(stub.responses))-1]
+		if answer.retryAfter != "" {
+			writer.Header().Set("Retry-After", answer.retryAfter)
+		}
+		writer.WriteHeader(answer.status)
+		_, _ = writer.Write([]byte(answer.body))
+	}))
+
+	return stub
+}
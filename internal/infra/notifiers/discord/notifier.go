@@ -0,0 +1,190 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/render"
+)
+
+const (
+	// maxEmbedsPerMessage is the Discord limit of embeds in one webhook message.
+	maxEmbedsPerMessage = 10
+	// maxEmbedsLength is the Discord limit of characters in all embeds of one webhook message.
+	maxEmbedsLength = 6000
+
+	colorInRange    = 0x2ecc71
+	colorOutOfRange = 0xe74c3c
+)
+
+// Notifier posts positions as rich embeds to the Discord webhook of the channel.
+type Notifier struct {
+	poster *chatwebhook.Poster
+}
+
+func NewNotifier(poster *chatwebhook.Poster) *Notifier {
+	return &Notifier{
+		poster: poster,
+	}
+}
+
+func (n *Notifier) NotifyLiquidityPoolPositions(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	_ domain.Subject,
+	positions ...domain.LiquidityPoolPosition,
+) error {
+	embeds := lo.Map(positions, func(position domain.LiquidityPoolPosition, _ int) embed {
+		return makeEmbed(string(position.Dex)+" "+position.Token0.Name+"/"+position.Token1.Name, position)
+	})
+
+	return n.send(ctx, channel.Target, embeds)
+}
+
+func (n *Notifier) NotifyPositionsEvents(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	_ domain.Subject,
+	events ...domain.PositionEvent,
+) error {
+	embeds := lo.Map(events, func(event domain.PositionEvent, _ int) embed {
+		return makeEmbed(render.GetEventTitle(event.Kind), event.Position)
+	})
+
+	return n.send(ctx, channel.Target, embeds)
+}
+
+// send splits embeds into messages skipping the ones sent before, the failure tells how many are
+// delivered, so the retry continues from the failed one. Channels without webhook are skipped.
+func (n *Notifier) send(ctx context.Context, url string, embeds []embed) error {
+	if url == "" || len(embeds) == 0 {
+		return nil
+	}
+
+	chunks := splitEmbeds(embeds)
+	for index := domain.GetSentParts(ctx); index < len(chunks); index++ {
+		err := n.poster.Post(ctx, url, webhookMessage{Embeds: chunks[index]})
+		if err != nil {
+			return &domain.PartialDeliveryError{SentParts: index, Err: fmt.Errorf("post: %w", err)}
+		}
+	}
+
+	return nil
+}
+
+// splitEmbeds groups embeds into messages within the limits of both the embeds number and their length.
+func splitEmbeds(embeds []embed) [][]embed {
+	var chunks [][]embed
+	var chunk []embed
+	length := 0
+
+	for _, item := range embeds {
+		itemLength := item.getLength()
+		if len(chunk) == maxEmbedsPerMessage || (len(chunk) > 0 && length+itemLength > maxEmbedsLength) {
+			chunks = append(chunks, chunk)
+			chunk, length = nil, 0
+		}
+
+		chunk = append(chunk, item)
+		length += itemLength
+	}
+
+	return append(chunks, chunk)
+}
+
+func makeEmbed(title string, position domain.LiquidityPoolPosition) embed {
+	color := colorOutOfRange
+	status := "❌ Out of range"
+	if position.IsInRange() {
+		color = colorInRange
+		status = "✅ In range"
+	}
+
+	return embed{
+		Title:       title,
+		URL:         position.PositionLink,
+		Description: fmt.Sprintf("Wallet `%s`", position.Wallet),
+		Color:       color,
+		Fields:      append([]embedField{{Name: "Status", Value: status, Inline: true}}, makeFields(position)...),
+	}
+}
+
+func makeFields(position domain.LiquidityPoolPosition) []embedField {
+	token0, token1 := position.Token0.Name, position.Token1.Name
+	percent0, percent1 := position.GetTokensPercentage()
+	amount0, amount1 := position.GetTokenAmounts()
+	fees0, fees1 := position.GetUncollectedFees()
+
+	fields := []embedField{
+		{Name: "Chain", Value: string(position.Chain), Inline: true},
+		{Name: "Dex", Value: string(position.Dex), Inline: true},
+		{Name: "Proportion", Value: fmt.Sprintf("%s %.2f%% : %s %.2f%%", token0, percent0, token1, percent1)},
+		{Name: "Amounts", Value: fmt.Sprintf("%.4f %s : %.4f %s", amount0, token0, amount1, token1)},
+		{Name: "Uncollected fees", Value: fmt.Sprintf("%.4f %s : %.4f %s", fees0, token0, fees1, token1)},
+	}
+	fields = append(fields, makeEmissionsFields(position)...)
+
+	if value, ok := position.GetValueUSD(); ok {
+		fields = append(fields, embedField{
+			Name:  "Value",
+			Value: fmt.Sprintf("$%.2f, fees $%.2f", value.GetTotalUSD(), value.FeesUSD),
+		})
+	}
+
+	return append(fields, makePriceFields(position)...)
+}
+
+// makeEmissionsFields returns nothing unless rewards of the staked position are known.
+func makeEmissionsFields(position domain.LiquidityPoolPosition) []embedField {
+	if position.Emissions == nil {
+		return nil
+	}
+
+	return []embedField{{
+		Name:  "Staked, pending emissions",
+		Value: fmt.Sprintf("%.4f %s", position.Emissions.GetPending(), position.Emissions.Token.Name),
+	}}
+}
+
+func makePriceFields(position domain.LiquidityPoolPosition) []embedField {
+	token0, token1 := position.Token0.Name, position.Token1.Name
+
+	return []embedField{
+		{Name: "Range low price", Value: render.FormatPrice(position.GetLowerPrice(), token0, token1), Inline: true},
+		{Name: "Range up price", Value: render.FormatPrice(position.GetUpperPrice(), token0, token1), Inline: true},
+		{Name: "Current price", Value: render.FormatPrice(position.GetCurrentPrice(), token0, token1), Inline: true},
+	}
+}
+
+type webhookMessage struct {
+	Embeds []embed `json:"embeds"`
+}
+
+type embed struct {
+	Title       string       `json:"title"`
+	URL         string       `json:"url,omitempty"`
+	Description string       `json:"description"`
+	Color       int          `json:"color"`
+	Fields      []embedField `json:"fields"`
+}
+
+// getLength counts the characters Discord limits: the title, the description and the fields.
+func (e embed) getLength() int {
+	length := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	for _, field := range e.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+
+	return length
+}
+
+type embedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
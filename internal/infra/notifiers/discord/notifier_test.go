@@ -0,0 +1,282 @@
+package discord_test
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/discord"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type notifierSuite struct {
+	suite.Suite
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(notifierSuite))
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_InRange_GreenEmbed() {
+	webhook := newWebhookStub(http.StatusNoContent)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+	s.Require().NoError(err)
+
+	s.Require().Len(webhook.bodies, 1)
+	s.Require().JSONEq(inRangePositionJSON, webhook.bodies[0])
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ManyPositions_SplitByLimit() {
+	webhook := newWebhookStub(http.StatusNoContent)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(11, func() domain.LiquidityPoolPosition {
+		return makePosition()
+	})
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, positions...)
+	s.Require().NoError(err)
+
+	s.Require().Len(webhook.bodies, 2)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_LongEmbeds_SplitByLength() {
+	webhook := newWebhookStub(http.StatusNoContent)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(10, func() domain.LiquidityPoolPosition {
+		position := makePosition()
+		position.Token0.Name = strings.Repeat("W", 100)
+		position.Token1.Name = strings.Repeat("U", 100)
+
+		return position
+	})
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, positions...)
+	s.Require().NoError(err)
+
+	s.Require().Greater(len(webhook.bodies), 1)
+
+	embeds := 0
+	for _, body := range webhook.bodies {
+		count, length := s.measureEmbeds(body)
+		s.Require().LessOrEqual(length, 6000)
+
+		embeds += count
+	}
+	s.Require().Equal(10, embeds)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_PartFailed_SentPartsReturned() {
+	webhook := newWebhookStub(http.StatusNoContent, http.StatusBadRequest)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(11, func() domain.LiquidityPoolPosition {
+		return makePosition()
+	})
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, positions...)
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+
+	var partialErr *domain.PartialDeliveryError
+	s.Require().ErrorAs(err, &partialErr)
+	s.Require().Equal(1, partialErr.SentParts)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_SentParts_Skipped() {
+	webhook := newWebhookStub(http.StatusNoContent)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(11, func() domain.LiquidityPoolPosition {
+		return makePosition()
+	})
+
+	retryCtx := domain.WithSentParts(context.Background(), 1)
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(retryCtx, channel, subject, positions...)
+	s.Require().NoError(err)
+
+	s.Require().Len(webhook.bodies, 1)
+	s.Require().Equal(1, strings.Count(webhook.bodies[0], `"title":`))
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_NoWebhook_Skipped() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord}
+
+	notifier := newNotifier(http.DefaultClient)
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_LeftRange_RedEmbed() {
+	webhook := newWebhookStub(http.StatusOK)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	position := makePosition()
+	position.CurrentTick = position.TickUpper + 1
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, domain.PositionEvent{
+		Kind:     domain.PositionEventLeftRange,
+		Position: position,
+	})
+	s.Require().NoError(err)
+
+	s.Require().Len(webhook.bodies, 1)
+	s.Require().Contains(webhook.bodies[0], `"title":"❌ Position left range"`)
+	s.Require().Contains(webhook.bodies[0], `"color":`+strconv.Itoa(0xe74c3c))
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_Rejected_Error() {
+	webhook := newWebhookStub(http.StatusBadRequest)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelDiscord, Target: webhook.URL}
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, domain.PositionEvent{
+		Kind:     domain.PositionEventAppeared,
+		Position: makePosition(),
+	})
+
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+}
+
+// measureEmbeds returns the number of embeds in the posted message and their length as Discord counts it.
+func (s *notifierSuite) measureEmbeds(body string) (int, int) {
+	var message struct {
+		Embeds []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Fields      []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"embeds"`
+	}
+	s.Require().NoError(jsoniter.UnmarshalFromString(body, &message))
+
+	length := 0
+	for _, item := range message.Embeds {
+		length += utf8.RuneCountInString(item.Title + item.Description)
+		for _, field := range item.Fields {
+			length += utf8.RuneCountInString(field.Name + field.Value)
+		}
+	}
+
+	return len(message.Embeds), length
+}
+
+func newNotifier(httpClient *http.Client) *discord.Notifier {
+	return discord.NewNotifier(chatwebhook.NewPoster(chatwebhook.PosterConfig{HTTPClient: httpClient}))
+}
+
+// webhookStub records bodies of the posted messages and answers with the statuses in order,
+// the last one is repeated.
+type webhookStub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func newWebhookStub(statuses ...int) *webhookStub {
+	stub := &webhookStub{statuses: statuses}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+
+		stub.bodies = append(stub.bodies, string(body))
+		writer.WriteHeader(stub.statuses[min(len(stub.bodies), len(stub.statuses))-1])
+	}))
+
+	return stub
+}
+
+func makePosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		Wallet:       "0x1111111111111111111111111111111111111111",
+		Chain:        domain.ChainBase,
+		Dex:          domain.DexUniswapV3,
+		PositionLink: "https://google.com",
+		Token0: domain.Token{
+			Name:     "WETH",
+			Decimals: 18,
+		},
+		Token1: domain.Token{
+			Name:     "USDC",
+			Decimals: 6,
+		},
+		TickLower:   -192660,
+		CurrentTick: -191000,
+		TickUpper:   -190940,
+		Liquidity:   big.NewInt(1_000_000_000_000_000),
+
+		UncollectedFees0: big.NewInt(1_000_000_000_000_000),
+		UncollectedFees1: big.NewInt(3_500_000),
+	}
+}
+
+const inRangePositionJSON = `{
+	"embeds": [{
+		"title": "Uniswap V3 WETH/USDC",
+		"url": "https://google.com",
+		"description": "Wallet ` + "`0x1111111111111111111111111111111111111111`" + `",
+		"color": 3066993,
+		"fields": [
+			{"name": "Status", "value": "✅ In range", "inline": true},
+			{"name": "Chain", "value": "Base", "inline": true},
+			{"name": "Dex", "value": "Uniswap V3", "inline": true},
+			{"name": "Proportion", "value": "WETH 3.62% : USDC 96.38%"},
+			{"name": "Amounts", "value": "0.0420 WETH : 5673.5353 USDC"},
+			{"name": "Uncollected fees", "value": "0.0010 WETH : 3.5000 USDC"},
+			{"name": "Range low price", "value": "1 WETH = 4298.34 USDC", "inline": true},
+			{"name": "Range up price", "value": "1 WETH = 5105.00 USDC", "inline": true},
+			{"name": "Current price", "value": "1 WETH = 5074.46 USDC", "inline": true}
+		]
+	}]
+}`
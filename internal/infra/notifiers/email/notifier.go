@@ -0,0 +1,295 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/render"
+)
+
+const (
+	positionsSubject = "DeFi positions report"
+	eventsSubject    = "DeFi positions changed"
+
+	defaultTimeout = 10 * time.Second
+)
+
+var ErrStartTLSNotSupported = errors.New("smtp server does not support STARTTLS")
+
+//go:embed templates
+var templates embed.FS
+
+type NotifierConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// StartTLS requires upgrading the connection to TLS before authentication.
+	StartTLS bool
+	// Timeout bounds the whole SMTP session, so a hung server does not stall the delivery.
+	// It is 10 seconds when not set.
+	Timeout time.Duration
+}
+
+// Notifier sends positions as multipart HTML and plain text email to the channel address.
+type Notifier struct {
+	config NotifierConfig
+}
+
+func NewNotifier(config NotifierConfig) *Notifier {
+	return &Notifier{
+		config: config,
+	}
+}
+
+func (n *Notifier) NotifyLiquidityPoolPositions(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	subject domain.Subject,
+	positions ...domain.LiquidityPoolPosition,
+) error {
+	if channel.Target == "" || len(positions) == 0 {
+		return nil
+	}
+
+	return n.notify(ctx, channel.Target, positionsSubject, "positions", render.MakePositions(subject, positions))
+}
+
+func (n *Notifier) NotifyPositionsEvents(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	subject domain.Subject,
+	events ...domain.PositionEvent,
+) error {
+	if channel.Target == "" || len(events) == 0 {
+		return nil
+	}
+
+	return n.notify(ctx, channel.Target, eventsSubject, "events", render.MakeEvents(subject, events))
+}
+
+func (n *Notifier) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+
+	deadline := n.getDeadline(ctx)
+	dialer := net.Dialer{Deadline: deadline}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("net.Dial: %w", err)
+	}
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("conn.SetDeadline: %w", err)
+	}
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("smtp.NewClient: %w", err)
+	}
+
+	return client, nil
+}
+
+// getDeadline returns the end of the SMTP session, the context deadline is kept if it is earlier.
+func (n *Notifier) getDeadline(ctx context.Context) time.Time {
+	timeout := n.config.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+
+	return deadline
+}
+
+func (n *Notifier) notify(ctx context.Context, to, subjectLine, templateName string, data any) error {
+	plain, html, err := renderBodies(templateName, data)
+	if err != nil {
+		return fmt.Errorf("renderBodies: %w", err)
+	}
+
+	message, err := makeMessage(n.config.From, to, subjectLine, plain, html)
+	if err != nil {
+		return fmt.Errorf("makeMessage: %w", err)
+	}
+
+	return n.send(ctx, to, message)
+}
+
+func (n *Notifier) send(ctx context.Context, to string, message []byte) error {
+	client, err := n.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer client.Close() //nolint:errcheck // Nothing to do with the error.
+
+	err = n.startSession(client)
+	if err != nil {
+		return fmt.Errorf("startSession: %w", err)
+	}
+
+	err = writeMessage(client, n.config.From, to, message)
+	if err != nil {
+		return fmt.Errorf("writeMessage: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// startSession upgrades the connection and authenticates when configured.
+func (n *Notifier) startSession(client *smtp.Client) error {
+	if n.config.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return ErrStartTLSNotSupported
+		}
+
+		err := client.StartTLS(&tls.Config{ServerName: n.config.Host, MinVersion: tls.VersionTLS12})
+		if err != nil {
+			return fmt.Errorf("StartTLS: %w", err)
+		}
+	}
+
+	if n.config.Username == "" {
+		return nil
+	}
+
+	err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host))
+	if err != nil {
+		return fmt.Errorf("Auth: %w", err)
+	}
+
+	return nil
+}
+
+func writeMessage(client *smtp.Client, from, to string, message []byte) error {
+	err := client.Mail(from)
+	if err != nil {
+		return fmt.Errorf("Mail: %w", err)
+	}
+
+	err = client.Rcpt(to)
+	if err != nil {
+		return fmt.Errorf("Rcpt: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("Data: %w", err)
+	}
+
+	_, err = writer.Write(message)
+	if err != nil {
+		return fmt.Errorf("Write: %w", err)
+	}
+
+	return writer.Close()
+}
+
+// renderBodies renders plain text and HTML versions of the same data.
+func renderBodies(name string, data any) (plain, html string, err error) {
+	plainTemplate, err := texttemplate.ParseFS(templates, "templates/position.txt", "templates/"+name+".txt")
+	if err != nil {
+		return "", "", fmt.Errorf("texttemplate.ParseFS: %w", err)
+	}
+
+	htmlTemplate, err := htmltemplate.ParseFS(templates, "templates/position.html", "templates/"+name+".html")
+	if err != nil {
+		return "", "", fmt.Errorf("htmltemplate.ParseFS: %w", err)
+	}
+
+	var plainBuf, htmlBuf bytes.Buffer
+
+	err = plainTemplate.ExecuteTemplate(&plainBuf, name+".txt", data)
+	if err != nil {
+		return "", "", fmt.Errorf("texttemplate.Execute: %w", err)
+	}
+
+	err = htmlTemplate.ExecuteTemplate(&htmlBuf, name+".html", data)
+	if err != nil {
+		return "", "", fmt.Errorf("htmltemplate.Execute: %w", err)
+	}
+
+	return strings.TrimSpace(plainBuf.String()), htmlBuf.String(), nil
+}
+
+// makeMessage makes multipart/alternative message, clients show the last part they support, so HTML goes last.
+func makeMessage(from, to, subjectLine, plain, html string) ([]byte, error) {
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	err := writePart(writer, "text/plain; charset=UTF-8", plain)
+	if err != nil {
+		return nil, fmt.Errorf("writePart: %w", err)
+	}
+
+	err = writePart(writer, "text/html; charset=UTF-8", html)
+	if err != nil {
+		return nil, fmt.Errorf("writePart: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("multipart.Close: %w", err)
+	}
+
+	headers := makeHeaders(from, to, subjectLine, writer.Boundary())
+
+	return append([]byte(headers), body.Bytes()...), nil
+}
+
+func makeHeaders(from, to, subjectLine, boundary string) string {
+	var headers strings.Builder
+
+	fmt.Fprintf(&headers, "From: %s\r\n", from)
+	fmt.Fprintf(&headers, "To: %s\r\n", to)
+	fmt.Fprintf(&headers, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subjectLine))
+	fmt.Fprintf(&headers, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&headers, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&headers, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+
+	return headers.String()
+}
+
+func writePart(writer *multipart.Writer, contentType, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("CreatePart: %w", err)
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+
+	_, err = encoder.Write([]byte(content))
+	if err != nil {
+		return fmt.Errorf("quotedprintable.Write: %w", err)
+	}
+
+	return encoder.Close()
+}
@@ -0,0 +1,296 @@
+package email_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/email"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type notifierSuite struct {
+	suite.Suite
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(notifierSuite))
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_Success_MultipartEmail() {
+	stub := s.newSMTPStub()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelEmail, Target: "finance@example.com"}
+
+	notifier := email.NewNotifier(stub.makeConfig())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+	s.Require().NoError(err)
+
+	<-stub.done
+	s.Require().Equal("\x00user\x00secret", stub.credentials)
+	s.Require().Equal("MAIL FROM:<monitoring@example.com>", stub.mailFrom)
+	s.Require().Equal("RCPT TO:<finance@example.com>", stub.rcptTo)
+
+	message, err := mail.ReadMessage(bytes.NewReader(stub.data))
+	s.Require().NoError(err)
+	s.Require().Equal("finance@example.com", message.Header.Get("To"))
+	s.Require().Equal("DeFi positions report", message.Header.Get("Subject"))
+
+	parts := s.readParts(message)
+	s.Require().Equal(strings.TrimSpace(positionsPlainText), parts["text/plain"])
+	s.Require().Contains(parts["text/html"], "<h3>Wallet 0x1111111111111111111111111111111111111111</h3>")
+	s.Require().Contains(parts["text/html"], `<td><a href="https://google.com">link</a></td>`)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_Success_TitleInBothParts() {
+	stub := s.newSMTPStub()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelEmail, Target: "finance@example.com"}
+
+	notifier := email.NewNotifier(stub.makeConfig())
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, domain.PositionEvent{
+		Kind:     domain.PositionEventAppeared,
+		Position: makePosition(),
+	})
+	s.Require().NoError(err)
+
+	<-stub.done
+	message, err := mail.ReadMessage(bytes.NewReader(stub.data))
+	s.Require().NoError(err)
+
+	parts := s.readParts(message)
+	s.Require().True(strings.HasPrefix(parts["text/plain"], "🆕 New position\nWallet: 0x1111"))
+	s.Require().Contains(parts["text/html"], "<h3>🆕 New position</h3>")
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_NoEmail_Skipped() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelEmail}
+
+	notifier := email.NewNotifier(email.NotifierConfig{Host: "unreachable"})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_StartTLSNotSupported_Error() {
+	stub := s.newSMTPStub()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelEmail, Target: "finance@example.com"}
+
+	config := stub.makeConfig()
+	config.StartTLS = true
+
+	notifier := email.NewNotifier(config)
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().ErrorIs(err, email.ErrStartTLSNotSupported)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ServerHangs_TimeoutError() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	// The server accepts the connection but never greets.
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			s.T().Cleanup(func() {
+				_ = conn.Close()
+			})
+		}
+	}()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelEmail, Target: "finance@example.com"}
+	address := listener.Addr().(*net.TCPAddr)
+
+	notifier := email.NewNotifier(email.NotifierConfig{
+		Host:    address.IP.String(),
+		Port:    address.Port,
+		Timeout: 100 * time.Millisecond,
+	})
+
+	start := time.Now()
+	err = notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	var netErr net.Error
+	s.Require().ErrorAs(err, &netErr)
+	s.Require().True(netErr.Timeout())
+	s.Require().Less(time.Since(start), time.Second)
+}
+
+// readParts decodes the multipart body parts keyed by media type.
+func (s *notifierSuite) readParts(message *mail.Message) map[string]string {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	s.Require().NoError(err)
+	s.Require().Equal("multipart/alternative", mediaType)
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts
+		}
+		s.Require().NoError(err)
+		s.Require().Equal("quoted-printable", part.Header.Get("Content-Transfer-Encoding"))
+
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		s.Require().NoError(err)
+
+		partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		s.Require().NoError(err)
+		parts[partType] = string(content)
+	}
+}
+
+// smtpStub is an in-process SMTP server accepting the single session.
+type smtpStub struct {
+	listener    net.Listener
+	done        chan struct{}
+	credentials string
+	mailFrom    string
+	rcptTo      string
+	data        []byte
+}
+
+func (s *notifierSuite) newSMTPStub() *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	stub := &smtpStub{
+		listener: listener,
+		done:     make(chan struct{}),
+	}
+
+	go func() {
+		defer close(stub.done)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		stub.serve(textproto.NewConn(conn))
+	}()
+
+	return stub
+}
+
+func (stub *smtpStub) makeConfig() email.NotifierConfig {
+	address := stub.listener.Addr().(*net.TCPAddr)
+
+	return email.NotifierConfig{
+		Host:     address.IP.String(),
+		Port:     address.Port,
+		Username: "user",
+		Password: "secret",
+		From:     "monitoring@example.com",
+	}
+}
+
+func (stub *smtpStub) serve(conn *textproto.Conn) {
+	defer conn.Close()
+
+	_ = conn.PrintfLine("220 stub ESMTP")
+
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			_ = conn.PrintfLine("250-stub")
+			_ = conn.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(argument, "PLAIN "))
+			stub.credentials = string(credentials)
+			_ = conn.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL":
+			stub.mailFrom = line
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			stub.rcptTo = line
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 Go ahead")
+			stub.data, _ = conn.ReadDotBytes()
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("502 " + strconv.Quote(command) + " not implemented")
+		}
+	}
+}
+
+func makePosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		Wallet:       "0x1111111111111111111111111111111111111111",
+		Chain:        domain.ChainBase,
+		Dex:          domain.DexUniswapV3,
+		PositionLink: "https://google.com",
+		Token0: domain.Token{
+			Name:     "WETH",
+			Decimals: 18,
+		},
+		Token1: domain.Token{
+			Name:     "USDC",
+			Decimals: 6,
+		},
+		TickLower:   -192660,
+		CurrentTick: -191000,
+		TickUpper:   -190940,
+		Liquidity:   big.NewInt(1_000_000_000_000_000),
+
+		UncollectedFees0: big.NewInt(1_000_000_000_000_000),
+		UncollectedFees1: big.NewInt(3_500_000),
+	}
+}
+
+const positionsPlainText = `
+Statuses: ✅
+
+Wallet: 0x1111111111111111111111111111111111111111
+
+Status: ✅
+Chain: Base
+Dex: Uniswap V3
+Position: https://google.com
+Proportion: WETH (3,62%) : USDC (96,38%)
+Amounts: 0,0420 WETH : 5673,5353 USDC
+Uncollected fees: 0,0010 WETH : 3,5000 USDC
+Range low price: 1 WETH = 4298,34 USDC
+Range up price: 1 WETH = 5105,00 USDC
+Current price: 1 WETH = 5074,46 USDC
+`
@@ -0,0 +1,82 @@
+package notifiers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/pool"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+var ErrUnsupportedChannel = errors.New("channel kind is not supported")
+
+// FanOut routes notifications to the subject channels accepting their severity. Channels are notified
+// concurrently and a failure of one does not stop the others.
+type FanOut struct {
+	impls map[domain.ChannelKind]domain.ChannelNotifier
+}
+
+func NewFanOut(impls map[domain.ChannelKind]domain.ChannelNotifier) *FanOut {
+	return &FanOut{
+		impls: impls,
+	}
+}
+
+// NotifyLiquidityPoolPositions sends the positions report as an informational notification.
+func (f *FanOut) NotifyLiquidityPoolPositions(
+	ctx context.Context,
+	subject domain.Subject,
+	positions ...domain.LiquidityPoolPosition,
+) error {
+	return f.dispatch(subject, func(impl domain.ChannelNotifier, channel domain.NotificationChannel) error {
+		if !channel.Accepts(domain.SeverityInfo) {
+			return nil
+		}
+
+		return impl.NotifyLiquidityPoolPositions(ctx, channel, subject, positions...)
+	})
+}
+
+// NotifyPositionsEvents sends every channel only the events it accepts.
+func (f *FanOut) NotifyPositionsEvents(
+	ctx context.Context,
+	subject domain.Subject,
+	events ...domain.PositionEvent,
+) error {
+	return f.dispatch(subject, func(impl domain.ChannelNotifier, channel domain.NotificationChannel) error {
+		accepted := lo.Filter(events, func(event domain.PositionEvent, _ int) bool {
+			return channel.Accepts(event.Kind.GetSeverity())
+		})
+		if len(accepted) == 0 {
+			return nil
+		}
+
+		return impl.NotifyPositionsEvents(ctx, channel, subject, accepted...)
+	})
+}
+
+func (f *FanOut) dispatch(
+	subject domain.Subject,
+	notify func(impl domain.ChannelNotifier, channel domain.NotificationChannel) error,
+) error {
+	p := pool.New().WithErrors()
+	for index, channel := range subject.GetChannels() {
+		p.Go(func() error {
+			impl, ok := f.impls[channel.Kind]
+			if !ok {
+				return &domain.ChannelError{Kind: channel.Kind, Index: index, Err: ErrUnsupportedChannel}
+			}
+
+			err := notify(impl, channel)
+			if err != nil {
+				return &domain.ChannelError{Kind: channel.Kind, Index: index, Err: err}
+			}
+
+			return nil
+		})
+	}
+
+	return p.Wait()
+}
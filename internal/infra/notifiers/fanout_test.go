@@ -0,0 +1,140 @@
+package notifiers_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers"
+	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type fanOutSuite struct {
+	suite.Suite
+}
+
+func TestFanOut(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(fanOutSuite))
+}
+
+func (s *fanOutSuite) TestNotifyLiquidityPoolPositions_NoChannels_TelegramChat() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	position := makePosition()
+
+	telegram := mocks.NewChannelNotifier(s.T())
+	telegram.EXPECT().
+		NotifyLiquidityPoolPositions(mock.Anything, domain.NewTelegramChannel(subject.TelegramUserID), subject, position).
+		Return(nil).
+		Once()
+
+	fanOut := notifiers.NewFanOut(map[domain.ChannelKind]domain.ChannelNotifier{
+		domain.ChannelTelegram: telegram,
+	})
+	err := fanOut.NotifyLiquidityPoolPositions(context.Background(), subject, position)
+
+	s.Require().NoError(err)
+}
+
+func (s *fanOutSuite) TestNotifyLiquidityPoolPositions_CriticalOnlyChannel_Skipped() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	subject.Channels = []domain.NotificationChannel{
+		{Kind: domain.ChannelSlack, Target: "https://slack", MinSeverity: domain.SeverityCritical},
+	}
+
+	fanOut := notifiers.NewFanOut(map[domain.ChannelKind]domain.ChannelNotifier{
+		domain.ChannelSlack: mocks.NewChannelNotifier(s.T()),
+	})
+	err := fanOut.NotifyLiquidityPoolPositions(context.Background(), subject, makePosition())
+
+	s.Require().NoError(err)
+}
+
+func (s *fanOutSuite) TestNotifyPositionsEvents_SeverityFilters_OnlyAcceptedEvents() {
+	critical := domain.NotificationChannel{
+		Kind:        domain.ChannelDiscord,
+		Target:      "https://discord",
+		MinSeverity: domain.SeverityCritical,
+	}
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	subject.Channels = []domain.NotificationChannel{domain.NewTelegramChannel(subject.TelegramUserID), critical}
+
+	appeared := domain.PositionEvent{Kind: domain.PositionEventAppeared, Position: makePosition()}
+	leftRange := domain.PositionEvent{Kind: domain.PositionEventLeftRange, Position: makePosition()}
+
+	telegram := mocks.NewChannelNotifier(s.T())
+	telegram.EXPECT().
+		NotifyPositionsEvents(mock.Anything, subject.Channels[0], subject, appeared, leftRange).
+		Return(nil).
+		Once()
+
+	discord := mocks.NewChannelNotifier(s.T())
+	discord.EXPECT().
+		NotifyPositionsEvents(mock.Anything, critical, subject, leftRange).
+		Return(nil).
+		Once()
+
+	fanOut := notifiers.NewFanOut(map[domain.ChannelKind]domain.ChannelNotifier{
+		domain.ChannelTelegram: telegram,
+		domain.ChannelDiscord:  discord,
+	})
+	err := fanOut.NotifyPositionsEvents(context.Background(), subject, appeared, leftRange)
+
+	s.Require().NoError(err)
+}
+
+func (s *fanOutSuite) TestNotifyPositionsEvents_ChannelsFail_OthersNotified() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	subject.Channels = []domain.NotificationChannel{
+		domain.NewTelegramChannel(subject.TelegramUserID),
+		{Kind: domain.ChannelSlack, Target: "https://slack"},
+		{Kind: domain.ChannelEmail, Target: "finance@example.com"},
+	}
+	event := domain.PositionEvent{Kind: domain.PositionEventLeftRange, Position: makePosition()}
+	errSlack := errors.New("slack is down")
+
+	telegram := mocks.NewChannelNotifier(s.T())
+	telegram.EXPECT().
+		NotifyPositionsEvents(mock.Anything, subject.Channels[0], subject, event).
+		Return(nil).
+		Once()
+
+	slack := mocks.NewChannelNotifier(s.T())
+	slack.EXPECT().
+		NotifyPositionsEvents(mock.Anything, subject.Channels[1], subject, event).
+		Return(errSlack).
+		Once()
+
+	fanOut := notifiers.NewFanOut(map[domain.ChannelKind]domain.ChannelNotifier{
+		domain.ChannelTelegram: telegram,
+		domain.ChannelSlack:    slack,
+	})
+	err := fanOut.NotifyPositionsEvents(context.Background(), subject, event)
+
+	s.Require().ErrorIs(err, errSlack)
+	s.Require().ErrorIs(err, notifiers.ErrUnsupportedChannel)
+
+	var channelErr *domain.ChannelError
+	s.Require().ErrorAs(err, &channelErr)
+	s.Require().Contains([]domain.ChannelKind{domain.ChannelSlack, domain.ChannelEmail}, channelErr.Kind)
+}
+
+func makePosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		Wallet:      "0x1111111111111111111111111111111111111111",
+		Chain:       domain.ChainBase,
+		Dex:         domain.DexUniswapV3,
+		Token0:      domain.Token{Name: "WETH", Decimals: 18},
+		Token1:      domain.Token{Name: "USDC", Decimals: 6},
+		TickLower:   -192660,
+		CurrentTick: -191000,
+		TickUpper:   -190940,
+		Liquidity:   big.NewInt(1_000_000_000_000_000),
+	}
+}
@@ -0,0 +1,282 @@
+// Package render prepares positions data for notifier templates, so every channel shows the same numbers.
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/pnl"
+)
+
+type Positions struct {
+	Statuses   string
+	TotalValue *TotalValue
+	Wallets    []Wallet
+}
+
+type TotalValue struct {
+	Total string
+	Fees  string
+}
+
+type Wallet struct {
+	Wallet    string
+	Positions []Position
+}
+
+type Events struct {
+	Events []Event
+}
+
+type Event struct {
+	Title    string
+	Position Position
+}
+
+type Position struct {
+	Wallet        string
+	Status        string
+	Chain         string
+	Dex           string
+	PositionLink  string
+	NFTLink       string
+	PoolLink      string
+	Token0        string
+	Token0Percent string
+	Token0Amount  string
+	Token1        string
+	Token1Percent string
+	Token1Amount  string
+	Token0Fees    string
+	Token1Fees    string
+	Emissions     *Emissions
+	Value         *Value
+	PnL           *PnL
+	LowPrice      string
+	UpPrice       string
+	CurrentPrice  string
+}
+
+type Value struct {
+	Total     string
+	Token0    string
+	Token0USD string
+	Token1    string
+	Token1USD string
+	Fees      string
+}
+
+// Emissions are pending gauge rewards of the staked position.
+type Emissions struct {
+	Amount string
+	Token  string
+}
+
+type PnL struct {
+	PnL             string
+	HODL            string
+	ImpermanentLoss string
+	FeeAPR          string
+}
+
+// MakePositions prepares the full report of the positions grouped by wallet.
+func MakePositions(subject domain.Subject, positions []domain.LiquidityPoolPosition) Positions {
+	statuses := convertToAnotherSlice(positions, func(position domain.LiquidityPoolPosition) string {
+		return GetStatus(position, subject.EdgeWarningPercent)
+	})
+
+	return Positions{
+		Statuses:   strings.Join(statuses, " "),
+		TotalValue: makeTotalValue(positions),
+		Wallets:    makeWallets(positions, subject.EdgeWarningPercent),
+	}
+}
+
+// MakeEvents prepares the events report keeping the events order.
+func MakeEvents(subject domain.Subject, events []domain.PositionEvent) Events {
+	return Events{
+		Events: convertToAnotherSlice(events, func(event domain.PositionEvent) Event {
+			return Event{
+				Title:    GetEventTitle(event.Kind),
+				Position: MakePosition(event.Position, subject.EdgeWarningPercent),
+			}
+		}),
+	}
+}
+
+func MakePosition(position domain.LiquidityPoolPosition, edgeWarningPercent float64) Position {
+	token0, token1 := position.GetTokensPercentage()
+	amount0, amount1 := position.GetTokenAmounts()
+	fees0, fees1 := position.GetUncollectedFees()
+
+	return Position{
+		Wallet:        position.Wallet,
+		Status:        GetStatus(position, edgeWarningPercent),
+		Chain:         string(position.Chain),
+		Dex:           string(position.Dex),
+		PositionLink:  position.PositionLink,
+		NFTLink:       position.NFTLink,
+		PoolLink:      position.PoolLink,
+		Token0:        position.Token0.Name,
+		Token0Percent: formatAndEscape(token0),
+		Token0Amount:  formatAmountAndEscape(amount0),
+		Token1:        position.Token1.Name,
+		Token1Percent: formatAndEscape(token1),
+		Token1Amount:  formatAmountAndEscape(amount1),
+		Token0Fees:    formatAmountAndEscape(fees0),
+		Token1Fees:    formatAmountAndEscape(fees1),
+		Emissions:     makeEmissions(position),
+		Value:         makeValue(position),
+		PnL:           makePnL(position),
+		LowPrice:      formatAndEscape(position.GetLowerPrice()),
+		UpPrice:       formatAndEscape(position.GetUpperPrice()),
+		CurrentPrice:  formatAndEscape(position.GetCurrentPrice()),
+	}
+}
+
+func GetEventTitle(kind domain.PositionEventKind) string {
+	titles := map[domain.PositionEventKind]string{
+		domain.PositionEventAppeared:        "🆕 New position",
+		domain.PositionEventDisappeared:     "🗑 Position closed",
+		domain.PositionEventLeftRange:       "❌ Position left range",
+		domain.PositionEventReturnedToRange: "✅ Position returned to range",
+		domain.PositionEventApproachingEdge: "⚠️ Position approaching range edge",
+		domain.PositionEventFeesExceeded:    "💰 Fees ready to collect",
+	}
+
+	title, ok := titles[kind]
+	if !ok {
+		return string(kind)
+	}
+
+	return title
+}
+
+func GetStatus(position domain.LiquidityPoolPosition, edgeWarningPercent float64) string {
+	switch position.GetStatus(edgeWarningPercent) {
+	case domain.PositionStatusInRange:
+		return "✅"
+	case domain.PositionStatusNearEdge:
+		return "⚠️"
+	default:
+		return "❌"
+	}
+}
+
+// FormatPrice formats the price of token0 in token1 for channels which show plain numbers.
+func FormatPrice(price float64, token0, token1 string) string {
+	return fmt.Sprintf("1 %s = %.2f %s", token0, price, token1)
+}
+
+// makeWallets groups positions by wallet keeping the order wallets first appear in.
+func makeWallets(positions []domain.LiquidityPoolPosition, edgeWarningPercent float64) []Wallet {
+	byWallet := lo.GroupBy(positions, func(position domain.LiquidityPoolPosition) string {
+		return position.Wallet
+	})
+	wallets := lo.Uniq(convertToAnotherSlice(positions, func(position domain.LiquidityPoolPosition) string {
+		return position.Wallet
+	}))
+
+	makePosition := func(position domain.LiquidityPoolPosition) Position {
+		return MakePosition(position, edgeWarningPercent)
+	}
+
+	return convertToAnotherSlice(wallets, func(wallet string) Wallet {
+		return Wallet{
+			Wallet:    wallet,
+			Positions: convertToAnotherSlice(byWallet[wallet], makePosition),
+		}
+	})
+}
+
+// makeEmissions returns nil unless rewards of the staked position are known.
+func makeEmissions(position domain.LiquidityPoolPosition) *Emissions {
+	if position.Emissions == nil {
+		return nil
+	}
+
+	return &Emissions{
+		Amount: formatAmountAndEscape(position.Emissions.GetPending()),
+		Token:  position.Emissions.Token.Name,
+	}
+}
+
+// makeTotalValue sums value of the priced positions, nil if none of them is priced.
+func makeTotalValue(positions []domain.LiquidityPoolPosition) *TotalValue {
+	var (
+		total, fees float64
+		priced      bool
+	)
+
+	for _, position := range positions {
+		value, ok := position.GetValueUSD()
+		if !ok {
+			continue
+		}
+
+		total += value.GetTotalUSD()
+		fees += value.FeesUSD
+		priced = true
+	}
+
+	if !priced {
+		return nil
+	}
+
+	return &TotalValue{
+		Total: formatAndEscape(total),
+		Fees:  formatAndEscape(fees),
+	}
+}
+
+func makeValue(position domain.LiquidityPoolPosition) *Value {
+	value, ok := position.GetValueUSD()
+	if !ok {
+		return nil
+	}
+
+	return &Value{
+		Total:     formatAndEscape(value.GetTotalUSD()),
+		Token0:    position.Token0.Name,
+		Token0USD: formatAndEscape(value.Token0USD),
+		Token1:    position.Token1.Name,
+		Token1USD: formatAndEscape(value.Token1USD),
+		Fees:      formatAndEscape(value.FeesUSD),
+	}
+}
+
+// makePnL compares the position with holding at the moment, nil if it can not be calculated.
+func makePnL(position domain.LiquidityPoolPosition) *PnL {
+	report, ok := pnl.Calculate(position, time.Now())
+	if !ok {
+		return nil
+	}
+
+	return &PnL{
+		PnL:             formatAndEscape(report.GetPnLUSD()),
+		HODL:            formatAndEscape(report.HODLUSD),
+		ImpermanentLoss: formatAndEscape(report.ImpermanentLossPercent),
+		FeeAPR:          formatAndEscape(report.FeeAPRPercent),
+	}
+}
+
+func formatAndEscape(value float64) string {
+	cut := fmt.Sprintf("%.2f", value)
+	return strings.Replace(cut, ".", ",", 1)
+}
+
+// formatAmountAndEscape keeps more digits than formatAndEscape since token amounts are often fractional.
+func formatAmountAndEscape(value float64) string {
+	cut := fmt.Sprintf("%.4f", value)
+	return strings.Replace(cut, ".", ",", 1)
+}
+
+func convertToAnotherSlice[T any, R any](items []T, cast func(T) R) []R {
+	return lo.Map(items, func(item T, _ int) R {
+		return cast(item)
+	})
+}
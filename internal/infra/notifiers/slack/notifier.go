@@ -0,0 +1,183 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/render"
+)
+
+// maxBlocksPerMessage is the Slack limit of blocks in one message.
+const maxBlocksPerMessage = 50
+
+// Notifier posts positions as Block Kit sections to the Slack incoming webhook of the channel.
+type Notifier struct {
+	poster *chatwebhook.Poster
+}
+
+func NewNotifier(poster *chatwebhook.Poster) *Notifier {
+	return &Notifier{
+		poster: poster,
+	}
+}
+
+func (n *Notifier) NotifyLiquidityPoolPositions(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	_ domain.Subject,
+	positions ...domain.LiquidityPoolPosition,
+) error {
+	groups := lo.Map(positions, func(position domain.LiquidityPoolPosition, _ int) []block {
+		title := fmt.Sprintf("%s %s/%s", position.Dex, position.Token0.Name, position.Token1.Name)
+		return makePositionBlocks(title, position)
+	})
+
+	return n.send(ctx, channel.Target, "Liquidity pool positions", groups)
+}
+
+func (n *Notifier) NotifyPositionsEvents(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	_ domain.Subject,
+	events ...domain.PositionEvent,
+) error {
+	groups := lo.Map(events, func(event domain.PositionEvent, _ int) []block {
+		return makePositionBlocks(render.GetEventTitle(event.Kind), event.Position)
+	})
+
+	return n.send(ctx, channel.Target, "Liquidity pool positions changed", groups)
+}
+
+// send batches blocks into messages keeping blocks of one position together and skipping the batches
+// sent before, the failure tells how many are delivered, so the retry continues from the failed one.
+// Channels without webhook are skipped.
+func (n *Notifier) send(ctx context.Context, url, fallback string, groups [][]block) error {
+	if url == "" || len(groups) == 0 {
+		return nil
+	}
+
+	batches := batchBlocks(groups, maxBlocksPerMessage)
+	for index := domain.GetSentParts(ctx); index < len(batches); index++ {
+		err := n.poster.Post(ctx, url, webhookMessage{Text: fallback, Blocks: batches[index]})
+		if err != nil {
+			return &domain.PartialDeliveryError{SentParts: index, Err: fmt.Errorf("post: %w", err)}
+		}
+	}
+
+	return nil
+}
+
+// batchBlocks packs groups of blocks into batches of at most limit blocks without splitting a group.
+func batchBlocks(groups [][]block, limit int) [][]block {
+	var (
+		batches [][]block
+		current []block
+	)
+
+	for _, group := range groups {
+		if len(current)+len(group) > limit && len(current) > 0 {
+			batches = append(batches, current)
+			current = nil
+		}
+		current = append(current, group...)
+	}
+
+	return append(batches, current)
+}
+
+// makePositionBlocks renders the position as a titled section with the link button and a section of fields.
+func makePositionBlocks(title string, position domain.LiquidityPoolPosition) []block {
+	status := "❌ Out of range"
+	if position.IsInRange() {
+		status = "✅ In range"
+	}
+
+	header := block{
+		Type: "section",
+		Text: lo.ToPtr(markdown(fmt.Sprintf("*%s*\n%s\nWallet `%s`", title, status, position.Wallet))),
+	}
+	if position.PositionLink != "" {
+		header.Accessory = &button{
+			Type: "button",
+			Text: text{Type: "plain_text", Text: "Open position"},
+			URL:  position.PositionLink,
+		}
+	}
+
+	return []block{
+		header,
+		{Type: "section", Fields: makeFields(position)},
+		{Type: "divider"},
+	}
+}
+
+func makeFields(position domain.LiquidityPoolPosition) []text {
+	token0, token1 := position.Token0.Name, position.Token1.Name
+	percent0, percent1 := position.GetTokensPercentage()
+	amount0, amount1 := position.GetTokenAmounts()
+	fees0, fees1 := position.GetUncollectedFees()
+
+	fields := []text{
+		markdown("*Chain*\n" + string(position.Chain)),
+		markdown("*Dex*\n" + string(position.Dex)),
+		markdown(fmt.Sprintf("*Proportion*\n%s %.2f%% : %s %.2f%%", token0, percent0, token1, percent1)),
+		markdown(fmt.Sprintf("*Amounts*\n%.4f %s : %.4f %s", amount0, token0, amount1, token1)),
+		markdown(fmt.Sprintf("*Uncollected fees*\n%.4f %s : %.4f %s", fees0, token0, fees1, token1)),
+	}
+	fields = append(fields, makeEmissionsFields(position)...)
+
+	if value, ok := position.GetValueUSD(); ok {
+		fields = append(fields, markdown(fmt.Sprintf("*Value*\n$%.2f, fees $%.2f", value.GetTotalUSD(), value.FeesUSD)))
+	}
+
+	return append(fields,
+		markdown("*Range low price*\n"+render.FormatPrice(position.GetLowerPrice(), token0, token1)),
+		markdown("*Range up price*\n"+render.FormatPrice(position.GetUpperPrice(), token0, token1)),
+		markdown("*Current price*\n"+render.FormatPrice(position.GetCurrentPrice(), token0, token1)),
+	)
+}
+
+// makeEmissionsFields returns nothing unless rewards of the staked position are known.
+func makeEmissionsFields(position domain.LiquidityPoolPosition) []text {
+	if position.Emissions == nil {
+		return nil
+	}
+
+	emissions := position.Emissions
+
+	return []text{
+		markdown(fmt.Sprintf("*Staked, pending emissions*\n%.4f %s", emissions.GetPending(), emissions.Token.Name)),
+	}
+}
+
+func markdown(value string) text {
+	return text{Type: "mrkdwn", Text: value}
+}
+
+type webhookMessage struct {
+	// Text is shown in notifications where blocks can not be rendered.
+	Text   string  `json:"text"`
+	Blocks []block `json:"blocks"`
+}
+
+type block struct {
+	Type      string  `json:"type"`
+	Text      *text   `json:"text,omitempty"`
+	Fields    []text  `json:"fields,omitempty"`
+	Accessory *button `json:"accessory,omitempty"`
+}
+
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type button struct {
+	Type string `json:"type"`
+	Text text   `json:"text"`
+	URL  string `json:"url"`
+}
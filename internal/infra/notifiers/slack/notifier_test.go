@@ -0,0 +1,225 @@
+package slack_test
+
+import (
+	"context"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/slack"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type notifierSuite struct {
+	suite.Suite
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(notifierSuite))
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_InRange_BlocksWithButton() {
+	webhook := newWebhookStub(http.StatusOK)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelSlack, Target: webhook.URL}
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+	s.Require().NoError(err)
+
+	s.Require().Len(webhook.bodies, 1)
+	s.Require().JSONEq(inRangePositionJSON, webhook.bodies[0])
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ManyPositions_BatchedByLimit() {
+	webhook := newWebhookStub(http.StatusOK)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelSlack, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(20, func() domain.LiquidityPoolPosition {
+		return makePosition()
+	})
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, positions...)
+	s.Require().NoError(err)
+
+	// 3 blocks per position, so 16 positions fit the limit of 50 blocks.
+	s.Require().Equal([]int{48, 12}, webhook.countBlocks())
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_BatchFailed_SentPartsReturned() {
+	webhook := newWebhookStub(http.StatusOK, http.StatusInternalServerError)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelSlack, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(20, func() domain.LiquidityPoolPosition {
+		return makePosition()
+	})
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, positions...)
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+
+	var partialErr *domain.PartialDeliveryError
+	s.Require().ErrorAs(err, &partialErr)
+	s.Require().Equal(1, partialErr.SentParts)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_SentParts_Skipped() {
+	webhook := newWebhookStub(http.StatusOK)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelSlack, Target: webhook.URL}
+
+	positions := generators.GeneratePlenty(20, func() domain.LiquidityPoolPosition {
+		return makePosition()
+	})
+
+	retryCtx := domain.WithSentParts(context.Background(), 1)
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyLiquidityPoolPositions(retryCtx, channel, subject, positions...)
+	s.Require().NoError(err)
+
+	s.Require().Equal([]int{12}, webhook.countBlocks())
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_NoWebhook_Skipped() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelSlack}
+
+	notifier := newNotifier(http.DefaultClient)
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, domain.PositionEvent{
+		Kind:     domain.PositionEventAppeared,
+		Position: makePosition(),
+	})
+
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_Rejected_Error() {
+	webhook := newWebhookStub(http.StatusNotFound)
+	defer webhook.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelSlack, Target: webhook.URL}
+
+	notifier := newNotifier(webhook.Client())
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, domain.PositionEvent{
+		Kind:     domain.PositionEventLeftRange,
+		Position: makePosition(),
+	})
+
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+}
+
+func newNotifier(httpClient *http.Client) *slack.Notifier {
+	return slack.NewNotifier(chatwebhook.NewPoster(chatwebhook.PosterConfig{HTTPClient: httpClient}))
+}
+
+// webhookStub records bodies of the posted messages and answers with the statuses in order,
+// the last one is repeated.
+type webhookStub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func newWebhookStub(statuses ...int) *webhookStub {
+	stub := &webhookStub{statuses: statuses}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+
+		stub.bodies = append(stub.bodies, string(body))
+		writer.WriteHeader(stub.statuses[min(len(stub.bodies), len(stub.statuses))-1])
+	}))
+
+	return stub
+}
+
+func (stub *webhookStub) countBlocks() []int {
+	counts := make([]int, 0, len(stub.bodies))
+	for _, body := range stub.bodies {
+		counts = append(counts, jsoniter.Get([]byte(body), "blocks").Size())
+	}
+
+	return counts
+}
+
+func makePosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		Wallet:       "0x1111111111111111111111111111111111111111",
+		Chain:        domain.ChainBase,
+		Dex:          domain.DexUniswapV3,
+		PositionLink: "https://google.com",
+		Token0: domain.Token{
+			Name:     "WETH",
+			Decimals: 18,
+		},
+		Token1: domain.Token{
+			Name:     "USDC",
+			Decimals: 6,
+		},
+		TickLower:   -192660,
+		CurrentTick: -191000,
+		TickUpper:   -190940,
+		Liquidity:   big.NewInt(1_000_000_000_000_000),
+
+		UncollectedFees0: big.NewInt(1_000_000_000_000_000),
+		UncollectedFees1: big.NewInt(3_500_000),
+	}
+}
+
+const inRangePositionJSON = `{
+	"text": "Liquidity pool positions",
+	"blocks": [
+		{
+			"type": "section",
+			"text": {
+				"type": "mrkdwn",
+				"text": "*Uniswap V3 WETH/USDC*\n✅ In range\nWallet ` + "`0x1111111111111111111111111111111111111111`" + `"
+			},
+			"accessory": {
+				"type": "button",
+				"text": {"type": "plain_text", "text": "Open position"},
+				"url": "https://google.com"
+			}
+		},
+		{
+			"type": "section",
+			"fields": [
+				{"type": "mrkdwn", "text": "*Chain*\nBase"},
+				{"type": "mrkdwn", "text": "*Dex*\nUniswap V3"},
+				{"type": "mrkdwn", "text": "*Proportion*\nWETH 3.62% : USDC 96.38%"},
+				{"type": "mrkdwn", "text": "*Amounts*\n0.0420 WETH : 5673.5353 USDC"},
+				{"type": "mrkdwn", "text": "*Uncollected fees*\n0.0010 WETH : 3.5000 USDC"},
+				{"type": "mrkdwn", "text": "*Range low price*\n1 WETH = 4298.34 USDC"},
+				{"type": "mrkdwn", "text": "*Range up price*\n1 WETH = 5105.00 USDC"},
+				{"type": "mrkdwn", "text": "*Current price*\n1 WETH = 5074.46 USDC"}
+			]
+		},
+		{"type": "divider"}
+	]
+}`
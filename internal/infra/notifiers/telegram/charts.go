@@ -0,0 +1,28 @@
+package telegram
+
+import (
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+const (
+	chartFileName = "position.png"
+	// chartHistoryPeriod is how long ago the price history of the chart starts.
+	chartHistoryPeriod = 24 * time.Hour
+	// maxAlbumSize is the Telegram limit of photos in the media group.
+	maxAlbumSize = 10
+)
+
+// getEventsPositions returns positions of the events, a position is charted once even if it has several events.
+func getEventsPositions(events []domain.PositionEvent) []domain.LiquidityPoolPosition {
+	positions := lo.Map(events, func(event domain.PositionEvent, _ int) domain.LiquidityPoolPosition {
+		return event.Position
+	})
+
+	return lo.UniqBy(positions, func(position domain.LiquidityPoolPosition) domain.PositionKey {
+		return position.GetKey()
+	})
+}
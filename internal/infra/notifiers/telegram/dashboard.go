@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/render"
+)
+
+const (
+	dashboardTimeLayout = "2006-01-02 15:04 UTC"
+	noPositionsText     = "No positions with liquidity found"
+
+	// omittedFormat tells positions which do not fit the dashboard even as a summary.
+	omittedFormat = "\n\n<i>%d more positions do not fit, send /positions for the full report</i>"
+	// omittedLength reserves the dashboard room for the omitted positions line.
+	omittedLength = 100
+
+	// Descriptions of Telegram errors on editing the message.
+	descriptionMessageNotModified = "message is not modified"
+	descriptionMessageNotFound    = "message to edit not found"
+)
+
+// makeDashboardText renders the positions report if it fits the single message, otherwise the line
+// per position summary. Positions which do not fit even the summary are counted, so none is dropped silently.
+func makeDashboardText(
+	subject domain.Subject,
+	positions []domain.LiquidityPoolPosition,
+	updatedAt time.Time,
+) (string, error) {
+	header := "<b>Updated:</b> " + updatedAt.UTC().Format(dashboardTimeLayout)
+	if len(positions) == 0 {
+		return header + "\n" + noPositionsText, nil
+	}
+
+	data := render.MakePositions(subject, positions)
+	limit := maxMessageLength - utf8.RuneCountInString(header) - 1
+
+	messages, err := splitPositions(dexLPTemplate, data, limit)
+	if err != nil {
+		return "", fmt.Errorf("splitPositions: %w", err)
+	}
+
+	if len(messages) == 1 {
+		return header + "\n" + strings.TrimSpace(messages[0]), nil
+	}
+
+	summary, err := makeDashboardSummary(data, limit)
+	if err != nil {
+		return "", fmt.Errorf("makeDashboardSummary: %w", err)
+	}
+
+	return header + "\n" + summary, nil
+}
+
+// makeDashboardSummary renders the line per position within the limit and counts the positions left out.
+func makeDashboardSummary(data render.Positions, limit int) (string, error) {
+	items := getWalletPositions(data)
+
+	message, end, err := renderChunk(dashboardSummaryTemplate, items, 0, limit-omittedLength, makePositionsChunk(data))
+	if err != nil {
+		return "", fmt.Errorf("renderChunk: %w", err)
+	}
+
+	message = strings.TrimSpace(message)
+	if end < len(items) {
+		message += fmt.Sprintf(omittedFormat, len(items)-end)
+	}
+
+	return message, nil
+}
+
+// hasErrorDescription reports whether Telegram rejected the request with the description.
+func hasErrorDescription(err error, description string) bool {
+	var tgErr *tgbotapi.Error
+
+	return errors.As(err, &tgErr) && strings.Contains(tgErr.Message, description)
+}
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// limiter spaces out messages to stay within Telegram limits for all chats together and for a single chat.
+type limiter struct {
+	globalInterval time.Duration
+	chatInterval   time.Duration
+
+	mu         sync.Mutex
+	nextGlobal time.Time
+	nextByChat map[int64]time.Time
+}
+
+func newLimiter(globalInterval, chatInterval time.Duration) *limiter {
+	return &limiter{
+		globalInterval: globalInterval,
+		chatInterval:   chatInterval,
+		nextByChat:     make(map[int64]time.Time),
+	}
+}
+
+// postpone holds all messages until the moment Telegram asks to wait for with retry_after of the 429 response.
+func (l *limiter) postpone(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nextGlobal = later(l.nextGlobal, until)
+}
+
+// prune forgets chats whose next slot has passed since they are not limited anymore,
+// so the map does not grow with every chat ever notified.
+func (l *limiter) prune(now time.Time) {
+	for chatID, next := range l.nextByChat {
+		if !next.After(now) {
+			delete(l.nextByChat, chatID)
+		}
+	}
+}
+
+// reserve books the earliest slot allowed for the chat and returns its time.
+func (l *limiter) reserve(chatID int64) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	slot := later(now, later(l.nextGlobal, l.nextByChat[chatID]))
+
+	l.nextGlobal = slot.Add(l.globalInterval)
+	l.nextByChat[chatID] = slot.Add(l.chatInterval)
+
+	return slot
+}
+
+// wait blocks until the message to the chat may be sent.
+func (l *limiter) wait(ctx context.Context, chatID int64) error {
+	delay := time.Until(l.reserve(chatID))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
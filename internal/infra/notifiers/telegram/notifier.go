@@ -3,9 +3,14 @@ package telegram
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/samber/lo"
 
@@ -14,137 +19,363 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chart"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/render"
 )
 
-//go:embed templates/dex_lp_position.html
-var dexLPTemplate string
+var (
+	//go:embed templates/dex_lp_position.html
+	dexLPTemplate string
+	//go:embed templates/dex_lp_position_events.html
+	dexLPEventsTemplate string
+	//go:embed templates/chart_caption.html
+	chartCaptionTemplate string
+	//go:embed templates/dashboard_summary.html
+	dashboardSummaryTemplate string
+)
 
 // TgBotApi - technical interface for unit tests&.
 //
 //nolint:revive // Is not important for technical interfaces
 type TgBotApi interface {
+	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
 	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	StopReceivingUpdates()
+}
+
+type NotifierConfig struct {
+	TelegramBot TgBotApi
+	// Dashboards are required for PublishDashboard only.
+	Dashboards domain.DashboardsRepository
+	// GlobalInterval is the minimal delay between messages to all chats, zero disables the limit.
+	GlobalInterval time.Duration
+	// ChatInterval is the minimal delay between messages to one chat, zero disables the limit.
+	ChatInterval time.Duration
+	// MaxRetries limits resending of a message rejected with 429 Too Many Requests.
+	MaxRetries int
+	// Charts enables range images of the positions sent after the text.
+	Charts bool
+	// History is optional, charts show the recent price of the positions with it.
+	History domain.PositionsHistoryRepository
+	// Logger reports failed charts, it is required if charts are enabled.
+	Logger *slog.Logger
 }
 
 type Notifier struct {
-	telegramBot TgBotApi
+	config  NotifierConfig
+	limiter *limiter
 }
 
-func NewNotifier(telegramBot TgBotApi) *Notifier {
+func NewNotifier(config NotifierConfig) *Notifier {
 	return &Notifier{
-		telegramBot: telegramBot,
+		config:  config,
+		limiter: newLimiter(config.GlobalInterval, config.ChatInterval),
 	}
 }
 
 func (n *Notifier) NotifyLiquidityPoolPositions(
-	_ context.Context,
+	ctx context.Context,
+	channel domain.NotificationChannel,
 	subject domain.Subject,
 	positions ...domain.LiquidityPoolPosition,
 ) error {
-	messageText, err := makeMessageText(positions)
+	chatID, err := strconv.ParseInt(channel.Target, 10, 64)
 	if err != nil {
-		return fmt.Errorf("makeMessageText: %w", err)
+		return fmt.Errorf("strconv.ParseInt: %w", err)
 	}
 
-	message := tgbotapi.MessageConfig{
-		BaseChat: tgbotapi.BaseChat{
-			ChatID: subject.TelegramUserID,
-		},
-		DisableWebPagePreview: true,
-		ParseMode:             tgbotapi.ModeHTML,
-		Text:                  strings.TrimSpace(messageText),
+	messages, err := splitPositions(dexLPTemplate, render.MakePositions(subject, positions), maxMessageLength)
+	if err != nil {
+		return fmt.Errorf("splitPositions: %w", err)
 	}
 
-	_, err = n.telegramBot.Send(message)
+	err = n.send(ctx, chatID, messages, domain.GetSentParts(ctx))
 	if err != nil {
-		return fmt.Errorf("telegram.Send: %w", err)
+		return fmt.Errorf("send: %w", err)
 	}
 
+	n.sendCharts(ctx, chatID, subject, positions)
+
 	return nil
 }
 
-func makeMessageText(positions []domain.LiquidityPoolPosition) (string, error) {
-	statuses := convertToAnotherSlice(positions, getStatus)
-	data := renderInfo{
-		Statuses:  strings.Join(statuses, " "),
-		Positions: convertToAnotherSlice(positions, makePositionRenderInfo),
+func (n *Notifier) NotifyPositionsEvents(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	subject domain.Subject,
+	events ...domain.PositionEvent,
+) error {
+	chatID, err := strconv.ParseInt(channel.Target, 10, 64)
+	if err != nil {
+		return fmt.Errorf("strconv.ParseInt: %w", err)
 	}
 
-	message, err := renderMessage(data)
+	messages, err := splitEvents(dexLPEventsTemplate, render.MakeEvents(subject, events), maxMessageLength)
 	if err != nil {
-		return "", fmt.Errorf("renderMessage: %w", err)
+		return fmt.Errorf("splitEvents: %w", err)
 	}
 
-	return message, nil
+	err = n.send(ctx, chatID, messages, domain.GetSentParts(ctx))
+	if err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	n.sendCharts(ctx, chatID, subject, getEventsPositions(events))
+
+	return nil
 }
 
-func getStatus(position domain.LiquidityPoolPosition) string {
-	if position.IsInRange() {
-		return "✅"
+// PublishDashboard edits the dashboard message in the subject chat, the new one is sent and pinned
+// if the subject has no dashboard yet or its message was deleted.
+func (n *Notifier) PublishDashboard(
+	ctx context.Context,
+	subject domain.Subject,
+	positions ...domain.LiquidityPoolPosition,
+) error {
+	text, err := makeDashboardText(subject, positions, time.Now())
+	if err != nil {
+		return fmt.Errorf("makeDashboardText: %w", err)
+	}
+
+	dashboard, err := n.config.Dashboards.Get(ctx, subject.TelegramUserID)
+	if errors.Is(err, domain.ErrDashboardNotFound) {
+		return n.sendDashboard(ctx, subject.TelegramUserID, text)
+	}
+	if err != nil {
+		return fmt.Errorf("DashboardsRepository.Get: %w", err)
+	}
+
+	err = n.editDashboard(ctx, dashboard, text)
+	if hasErrorDescription(err, descriptionMessageNotFound) {
+		return n.sendDashboard(ctx, subject.TelegramUserID, text)
 	}
-	return "❌"
+
+	return err
 }
 
-func makePositionRenderInfo(position domain.LiquidityPoolPosition) positionRenderInfo {
-	token0, token1 := position.GetTokensPercentage()
+// editDashboard replaces the dashboard text, the same text is not an error.
+func (n *Notifier) editDashboard(ctx context.Context, dashboard domain.Dashboard, text string) error {
+	edit := tgbotapi.NewEditMessageText(dashboard.TelegramUserID, dashboard.MessageID, text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.DisableWebPagePreview = true
 
-	return positionRenderInfo{
-		Status:        getStatus(position),
-		Chain:         string(position.Chain),
-		Dex:           string(position.Dex),
-		PositionLink:  position.PositionLink,
-		Token0:        position.Token0.Name,
-		Token0Percent: formatAndEscape(token0),
-		Token1:        position.Token1.Name,
-		Token1Percent: formatAndEscape(token1),
-		LowPrice:      formatAndEscape(position.GetLowerPrice()),
-		UpPrice:       formatAndEscape(position.GetUpperPrice()),
-		CurrentPrice:  formatAndEscape(position.GetCurrentPrice()),
+	_, err := n.sendMessage(ctx, dashboard.TelegramUserID, edit)
+	if err != nil && !hasErrorDescription(err, descriptionMessageNotModified) {
+		return fmt.Errorf("sendMessage: %w", err)
 	}
+
+	return nil
 }
 
-func renderMessage(data renderInfo) (string, error) {
-	tmpl, err := template.New("telegramMsg").Parse(dexLPTemplate)
+// getTimeline returns the position snapshots for the chart, the chart is drawn without history
+// if they are not available.
+func (n *Notifier) getTimeline(ctx context.Context, position domain.LiquidityPoolPosition) domain.PositionTimeline {
+	if n.config.History == nil {
+		return nil
+	}
+
+	to := time.Now()
+
+	timeline, err := n.config.History.GetTimeline(ctx, position.GetKey(), to.Add(-chartHistoryPeriod), to)
 	if err != nil {
-		return "", fmt.Errorf("template.New: %w", err)
+		return nil
 	}
 
-	var buf bytes.Buffer
+	return timeline
+}
 
-	err = tmpl.Execute(&buf, data)
+// makeCharts renders the range images of the positions captioned with their prices.
+func (n *Notifier) makeCharts(
+	ctx context.Context,
+	subject domain.Subject,
+	positions []domain.LiquidityPoolPosition,
+) ([]tgbotapi.InputMediaPhoto, error) {
+	photos := make([]tgbotapi.InputMediaPhoto, 0, len(positions))
+
+	for _, position := range positions {
+		image, err := chart.RenderRange(position, n.getTimeline(ctx, position))
+		if err != nil {
+			return nil, fmt.Errorf("chart.RenderRange: %w", err)
+		}
+
+		caption, err := renderMessage(chartCaptionTemplate, render.MakePosition(position, subject.EdgeWarningPercent))
+		if err != nil {
+			return nil, fmt.Errorf("renderMessage: %w", err)
+		}
+
+		photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{Name: chartFileName, Bytes: image})
+		photo.Caption = strings.TrimSpace(caption)
+		photo.ParseMode = tgbotapi.ModeHTML
+
+		photos = append(photos, photo)
+	}
+
+	return photos, nil
+}
+
+// retry waits for the rate limits and repeats the request after the delay Telegram asks for with 429.
+func (n *Notifier) retry(ctx context.Context, chatID int64, request func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := n.limiter.wait(ctx, chatID)
+		if err != nil {
+			return fmt.Errorf("limiter.wait: %w", err)
+		}
+
+		err = request()
+		if err == nil {
+			return nil
+		}
+
+		delay, ok := getRetryAfter(err)
+		if !ok || attempt >= n.config.MaxRetries {
+			return err
+		}
+
+		n.limiter.postpone(time.Now().Add(delay))
+	}
+}
+
+// send delivers the messages to the chat in order skipping the ones sent before, the failure tells
+// how many are delivered, so the retry continues from the failed one.
+func (n *Notifier) send(ctx context.Context, chatID int64, messages []string, sentParts int) error {
+	for index := sentParts; index < len(messages); index++ {
+		_, err := n.sendMessage(ctx, chatID, newMessage(chatID, messages[index]))
+		if err != nil {
+			return &domain.PartialDeliveryError{SentParts: index, Err: fmt.Errorf("sendMessage: %w", err)}
+		}
+	}
+
+	return nil
+}
+
+// sendAlbum sends the photos as the album, the single photo is sent as is since albums are of two photos at least.
+func (n *Notifier) sendAlbum(ctx context.Context, chatID int64, photos []tgbotapi.InputMediaPhoto) error {
+	if len(photos) == 1 {
+		photo := tgbotapi.NewPhoto(chatID, photos[0].Media)
+		photo.Caption = photos[0].Caption
+		photo.ParseMode = photos[0].ParseMode
+
+		_, err := n.sendMessage(ctx, chatID, photo)
+
+		return err
+	}
+
+	return n.retry(ctx, chatID, func() error {
+		_, err := n.config.TelegramBot.Request(tgbotapi.NewMediaGroup(chatID, lo.ToAnySlice(photos)))
+		if err != nil {
+			return fmt.Errorf("telegram.Request: %w", err)
+		}
+		return nil
+	})
+}
+
+// sendCharts sends the range images of the positions if charts are enabled, they are grouped into albums.
+// Failures are only logged since the text is already delivered and a retry would send it again.
+func (n *Notifier) sendCharts(
+	ctx context.Context,
+	chatID int64,
+	subject domain.Subject,
+	positions []domain.LiquidityPoolPosition,
+) {
+	if !n.config.Charts || len(positions) == 0 {
+		return
+	}
+
+	photos, err := n.makeCharts(ctx, subject, positions)
 	if err != nil {
-		return "", fmt.Errorf("template.New: %w", err)
+		n.config.Logger.Error("makeCharts", slog.Int64("chat", chatID), slog.String("err", err.Error()))
+		return
 	}
 
-	return buf.String(), nil
+	for _, album := range lo.Chunk(photos, maxAlbumSize) {
+		err = n.sendAlbum(ctx, chatID, album)
+		if err != nil {
+			n.config.Logger.Error("sendAlbum", slog.Int64("chat", chatID), slog.String("err", err.Error()))
+			return
+		}
+	}
 }
 
-func formatAndEscape(value float64) string {
-	cut := fmt.Sprintf("%.2f", value)
-	return strings.Replace(cut, ".", ",", 1)
+// sendDashboard sends and pins the new dashboard, it is saved before pinning, so it is edited next time
+// even if pinning fails.
+func (n *Notifier) sendDashboard(ctx context.Context, chatID int64, text string) error {
+	message, err := n.sendMessage(ctx, chatID, newMessage(chatID, text))
+	if err != nil {
+		return fmt.Errorf("sendMessage: %w", err)
+	}
+
+	err = n.config.Dashboards.Add(ctx, domain.Dashboard{TelegramUserID: chatID, MessageID: message.MessageID})
+	if err != nil {
+		return fmt.Errorf("DashboardsRepository.Add: %w", err)
+	}
+
+	_, err = n.config.TelegramBot.Request(tgbotapi.PinChatMessageConfig{
+		ChatID:              chatID,
+		MessageID:           message.MessageID,
+		DisableNotification: true,
+	})
+	if err != nil {
+		return fmt.Errorf("telegram.Request: %w", err)
+	}
+
+	return nil
 }
 
-func convertToAnotherSlice[T any, R any](items []T, cast func(T) R) []R {
-	return lo.Map(items, func(item T, _ int) R {
-		return cast(item)
+// sendMessage sends the message with retries.
+func (n *Notifier) sendMessage(
+	ctx context.Context,
+	chatID int64,
+	message tgbotapi.Chattable,
+) (tgbotapi.Message, error) {
+	var sent tgbotapi.Message
+
+	err := n.retry(ctx, chatID, func() error {
+		var err error
+
+		sent, err = n.config.TelegramBot.Send(message)
+		if err != nil {
+			return fmt.Errorf("telegram.Send: %w", err)
+		}
+		return nil
 	})
+
+	return sent, err
 }
 
-type renderInfo struct {
-	Statuses  string
-	Positions []positionRenderInfo
+// getRetryAfter returns the delay Telegram asks to wait for when the message is rejected by flood control.
+func getRetryAfter(err error) (time.Duration, bool) {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) || tgErr.Code != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	return time.Duration(tgErr.RetryAfter) * time.Second, true
+}
+
+func newMessage(chatID int64, text string) tgbotapi.MessageConfig {
+	return tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: chatID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(text),
+	}
 }
 
-type positionRenderInfo struct {
-	Status        string
-	Chain         string
-	Dex           string
-	PositionLink  string
-	Token0        string
-	Token0Percent string
-	Token1        string
-	Token1Percent string
-	LowPrice      string
-	UpPrice       string
-	CurrentPrice  string
+func renderMessage(text string, data any) (string, error) {
+	tmpl, err := template.New("telegramMsg").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("template.New: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("template.New: %w", err)
+	}
+
+	return buf.String(), nil
 }
@@ -2,15 +2,25 @@ package telegram_test
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
 	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/telegram"
+	domainmocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
 	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/infra/notifiers/telegram"
 	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
 )
@@ -61,6 +71,7 @@ func (s *notifierSuite) TestFake() {
 			ctx := context.Background()
 
 			subject := generators.NewSubjectGenerator().Slim().Result()
+			channel := domain.NewTelegramChannel(subject.TelegramUserID)
 			position := testCase.makePosition()
 
 			expectedMessage := tgbotapi.MessageConfig{
@@ -76,15 +87,641 @@ func (s *notifierSuite) TestFake() {
 				Send(expectedMessage).
 				Return(tgbotapi.Message{}, nil).
 				Once()
-			notifier := telegram.NewNotifier(tgBot)
-			err := notifier.NotifyLiquidityPoolPositions(ctx, subject, position)
+			notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+			err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, position)
 			s.Require().NoError(err)
 		})
 	}
 }
 
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_SeveralWallets_GroupedByWallet() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	first := makePosition()
+	second := makePosition()
+	second.Wallet = "0x2222222222222222222222222222222222222222"
+	second.CurrentTick = second.TickUpper + 1
+	third := makePosition()
+
+	expectedMessage := tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: subject.TelegramUserID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(severalWalletsText),
+	}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(expectedMessage).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, first, second, third)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_NearEdge_WarningStatus() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	subject.EdgeWarningPercent = 5
+
+	expectedMessage := tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: subject.TelegramUserID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(nearEdgePositionText),
+	}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(expectedMessage).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, makePosition())
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_GroupChannel_SentToGroup() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(-1001234567890)
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.MatchedBy(func(message tgbotapi.MessageConfig) bool {
+			return message.ChatID == -1001234567890
+		})).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_InvalidChatID_Error() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelTelegram, Target: "@channel"}
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: mocks.NewTgBotApi(s.T())})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().ErrorIs(err, strconv.ErrSyntax)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_Priced_ValueShown() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	position := makePosition()
+	position.Token0.PriceUSD = 2500
+	position.Token1.PriceUSD = 1
+
+	expectedMessage := tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: subject.TelegramUserID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(pricedPositionText),
+	}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(expectedMessage).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, position)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_WithDeposits_PnLShown() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	position := makePosition()
+	position.Token0.PriceUSD = 2500
+	position.Token1.PriceUSD = 1
+	position.Deposits = &domain.PositionDeposits{
+		Deposited0:     0.07,
+		Deposited1:     5610,
+		CollectedFees0: 0.001,
+		CollectedFees1: 1,
+		OpenedAt:       time.Now().Add(-365 * 24 * time.Hour),
+	}
+
+	expectedMessage := tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: subject.TelegramUserID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(pnlPositionText),
+	}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(expectedMessage).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, position)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ExplorerLinks_Shown() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	position := makePosition()
+	position.NFTLink = "https://explorer/nft/7"
+	position.PoolLink = "https://explorer/pool"
+
+	expectedMessage := tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: subject.TelegramUserID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(explorerLinksPositionText),
+	}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(expectedMessage).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, position)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_Success() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	outOfRange := makePosition()
+	outOfRange.CurrentTick = outOfRange.TickUpper + 1
+	events := []domain.PositionEvent{
+		{Kind: domain.PositionEventLeftRange, Position: outOfRange},
+		{Kind: domain.PositionEventAppeared, Position: makePosition()},
+		{Kind: domain.PositionEventFeesExceeded, Position: makePosition()},
+	}
+
+	expectedMessage := tgbotapi.MessageConfig{
+		BaseChat: tgbotapi.BaseChat{
+			ChatID: subject.TelegramUserID,
+		},
+		DisableWebPagePreview: true,
+		ParseMode:             tgbotapi.ModeHTML,
+		Text:                  strings.TrimSpace(eventsText),
+	}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(expectedMessage).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyPositionsEvents(ctx, channel, subject, events...)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_LongReport_SplitByPositions() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	positions := makePositions(20)
+
+	texts := s.collectTexts(context.Background(), channel, subject, positions)
+
+	s.Require().Greater(len(texts), 1)
+	s.Require().True(strings.HasPrefix(texts[0], "<b>Statuses:</b>"))
+
+	var sent int
+	for _, text := range texts {
+		s.Require().LessOrEqual(len([]rune(text)), 4096)
+		s.Require().Equal(strings.Count(text, "<b>"), strings.Count(text, "</b>"))
+		s.Require().True(strings.HasPrefix(text, "<b>Statuses:</b>") || strings.HasPrefix(text, "<b>Wallet:</b>"))
+		sent += strings.Count(text, "<b>Position:</b>")
+	}
+	s.Require().Equal(len(positions), sent)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_PartFailed_SentPartsReturned() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	positions := makePositions(20)
+
+	tgErr := &tgbotapi.Error{Code: http.StatusBadRequest}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Return(tgbotapi.Message{}, tgErr).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, positions...)
+	s.Require().ErrorIs(err, tgErr)
+
+	var partialErr *domain.PartialDeliveryError
+	s.Require().ErrorAs(err, &partialErr)
+	s.Require().Equal(1, partialErr.SentParts)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_SentParts_Skipped() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	positions := makePositions(20)
+
+	all := s.collectTexts(context.Background(), channel, subject, positions)
+	s.Require().Greater(len(all), 1)
+
+	retryCtx := domain.WithSentParts(context.Background(), 1)
+	s.Require().Equal(all[1:], s.collectTexts(retryCtx, channel, subject, positions))
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_TooManyRequests_Resent() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	event := domain.PositionEvent{Kind: domain.PositionEventAppeared, Position: makePosition()}
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Return(tgbotapi.Message{}, &tgbotapi.Error{Code: http.StatusTooManyRequests}).
+		Once()
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, MaxRetries: 1})
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, event)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_TooManyRequestsExhausted_Error() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	event := domain.PositionEvent{Kind: domain.PositionEventAppeared, Position: makePosition()}
+
+	tgErr := &tgbotapi.Error{Code: http.StatusTooManyRequests}
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Return(tgbotapi.Message{}, tgErr).
+		Twice()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, MaxRetries: 1})
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, event)
+	s.Require().ErrorIs(err, tgErr)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_ChatInterval_Delayed() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	event := domain.PositionEvent{Kind: domain.PositionEventAppeared, Position: makePosition()}
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Return(tgbotapi.Message{}, nil).
+		Twice()
+
+	interval := 50 * time.Millisecond
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, ChatInterval: interval})
+
+	start := time.Now()
+	s.Require().NoError(notifier.NotifyPositionsEvents(context.Background(), channel, subject, event))
+	s.Require().NoError(notifier.NotifyPositionsEvents(context.Background(), channel, subject, event))
+	s.Require().GreaterOrEqual(time.Since(start), interval)
+}
+
+func (s *notifierSuite) TestPublishDashboard_NoDashboard_SentAndPinned() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	dashboards := domainmocks.NewDashboardsRepository(s.T())
+	dashboards.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(domain.Dashboard{}, domain.ErrDashboardNotFound).
+		Once()
+	dashboards.EXPECT().
+		Add(mock.Anything, domain.Dashboard{TelegramUserID: subject.TelegramUserID, MessageID: 42}).
+		Return(nil).
+		Once()
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	expectDashboardSent(tgBot, subject.TelegramUserID, 42)
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Dashboards: dashboards})
+	err := notifier.PublishDashboard(context.Background(), subject, makePosition())
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestPublishDashboard_ReportTooLong_SummaryOfEveryPosition() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	positions := makePositions(20)
+
+	text := s.collectDashboardText(subject, positions)
+
+	s.Require().LessOrEqual(utf8.RuneCountInString(text), 4096)
+	s.Require().NotContains(text, "<b>Position:</b>")
+	s.Require().Equal(len(positions), strings.Count(text, `<a href="https://google.com">WETH/USDC</a>`))
+	s.Require().NotContains(text, "more positions do not fit")
+}
+
+func (s *notifierSuite) TestPublishDashboard_SummaryTooLong_OmittedCounted() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	positions := makePositions(200)
+
+	text := s.collectDashboardText(subject, positions)
+
+	s.Require().LessOrEqual(utf8.RuneCountInString(text), 4096)
+
+	shown := strings.Count(text, `<a href="https://google.com">WETH/USDC</a>`)
+	s.Require().Positive(shown)
+	s.Require().Contains(text, fmt.Sprintf("%d more positions do not fit", len(positions)-shown))
+}
+
+func (s *notifierSuite) TestPublishDashboard_Exists_Edited() {
+	type TestCase struct {
+		name    string
+		editErr error
+	}
+
+	testCases := []TestCase{
+		{name: "Edited", editErr: nil},
+		{
+			name:    "Not modified",
+			editErr: &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: message is not modified"},
+		},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			subject := generators.NewSubjectGenerator().Slim().Result()
+			dashboard := domain.Dashboard{TelegramUserID: subject.TelegramUserID, MessageID: 42}
+
+			dashboards := domainmocks.NewDashboardsRepository(s.T())
+			dashboards.EXPECT().
+				Get(mock.Anything, subject.TelegramUserID).
+				Return(dashboard, nil).
+				Once()
+
+			tgBot := mocks.NewTgBotApi(s.T())
+			expectDashboardEdited(tgBot, dashboard, testCase.editErr)
+
+			notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Dashboards: dashboards})
+			err := notifier.PublishDashboard(context.Background(), subject, makePosition())
+			s.Require().NoError(err)
+		})
+	}
+}
+
+func (s *notifierSuite) TestPublishDashboard_MessageDeleted_SentAgain() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	dashboard := domain.Dashboard{TelegramUserID: subject.TelegramUserID, MessageID: 42}
+
+	dashboards := domainmocks.NewDashboardsRepository(s.T())
+	dashboards.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(dashboard, nil).
+		Once()
+	dashboards.EXPECT().
+		Add(mock.Anything, domain.Dashboard{TelegramUserID: subject.TelegramUserID, MessageID: 43}).
+		Return(nil).
+		Once()
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	expectDashboardEdited(tgBot, dashboard, &tgbotapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: "Bad Request: message to edit not found",
+	})
+	expectDashboardSent(tgBot, subject.TelegramUserID, 43)
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Dashboards: dashboards})
+	err := notifier.PublishDashboard(context.Background(), subject, makePosition())
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_Charts_PhotoSent() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+	position := makePosition()
+
+	history := domainmocks.NewPositionsHistoryRepository(s.T())
+	history.EXPECT().
+		GetTimeline(mock.Anything, position.GetKey(), mock.Anything, mock.Anything).
+		Return(domain.PositionTimeline{{Position: position, CheckedAt: time.Now()}}, nil).
+		Once()
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.AnythingOfType("tgbotapi.MessageConfig")).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+	tgBot.EXPECT().
+		Send(mock.MatchedBy(func(photo tgbotapi.PhotoConfig) bool {
+			file, ok := photo.File.(tgbotapi.FileBytes)
+			return ok && len(file.Bytes) > 0 &&
+				photo.ChatID == subject.TelegramUserID &&
+				photo.ParseMode == tgbotapi.ModeHTML &&
+				strings.HasPrefix(photo.Caption, "✅ <b>WETH/USDC</b> Uniswap V3 on Base")
+		})).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Charts: true, History: history})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, position)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ChartsOfSeveralPositions_AlbumSent() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.AnythingOfType("tgbotapi.MessageConfig")).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+	tgBot.EXPECT().
+		Request(mock.MatchedBy(func(album tgbotapi.MediaGroupConfig) bool {
+			return album.ChatID == subject.TelegramUserID && len(album.Media) == 2
+		})).
+		Return(&tgbotapi.APIResponse{Ok: true}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Charts: true})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition(), makePosition())
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_ChartsOfSamePosition_SentOnce() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	events := []domain.PositionEvent{
+		{Kind: domain.PositionEventApproachingEdge, Position: makePosition()},
+		{Kind: domain.PositionEventFeesExceeded, Position: makePosition()},
+	}
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.AnythingOfType("tgbotapi.MessageConfig")).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+	tgBot.EXPECT().
+		Send(mock.AnythingOfType("tgbotapi.PhotoConfig")).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Charts: true})
+	err := notifier.NotifyPositionsEvents(context.Background(), channel, subject, events...)
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ChartsFailed_TextDelivered() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NewTelegramChannel(subject.TelegramUserID)
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.AnythingOfType("tgbotapi.MessageConfig")).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+	tgBot.EXPECT().
+		Request(mock.AnythingOfType("tgbotapi.MediaGroupConfig")).
+		Return(nil, &tgbotapi.Error{Code: http.StatusBadRequest}).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{
+		TelegramBot: tgBot,
+		Charts:      true,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition(), makePosition())
+	s.Require().NoError(err)
+}
+
+// collectTexts returns texts of the messages the positions report is sent with.
+func (s *notifierSuite) collectTexts(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	subject domain.Subject,
+	positions []domain.LiquidityPoolPosition,
+) []string {
+	var texts []string
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Run(func(c tgbotapi.Chattable) {
+			message, ok := c.(tgbotapi.MessageConfig)
+			s.Require().True(ok)
+			texts = append(texts, message.Text)
+		}).
+		Return(tgbotapi.Message{}, nil)
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot})
+	err := notifier.NotifyLiquidityPoolPositions(ctx, channel, subject, positions...)
+	s.Require().NoError(err)
+
+	return texts
+}
+
+// collectDashboardText returns the text of the new dashboard with the positions.
+func (s *notifierSuite) collectDashboardText(subject domain.Subject, positions []domain.LiquidityPoolPosition) string {
+	var text string
+
+	dashboards := domainmocks.NewDashboardsRepository(s.T())
+	dashboards.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(domain.Dashboard{}, domain.ErrDashboardNotFound).
+		Once()
+	dashboards.EXPECT().
+		Add(mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	tgBot := mocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		Send(mock.Anything).
+		Run(func(c tgbotapi.Chattable) {
+			message, ok := c.(tgbotapi.MessageConfig)
+			s.Require().True(ok)
+			text = message.Text
+		}).
+		Return(tgbotapi.Message{MessageID: 42}, nil).
+		Once()
+	tgBot.EXPECT().
+		Request(mock.Anything).
+		Return(&tgbotapi.APIResponse{Ok: true}, nil).
+		Once()
+
+	notifier := telegram.NewNotifier(telegram.NotifierConfig{TelegramBot: tgBot, Dashboards: dashboards})
+	err := notifier.PublishDashboard(context.Background(), subject, positions...)
+	s.Require().NoError(err)
+
+	return text
+}
+
+func expectDashboardSent(tgBot *mocks.TgBotApi, chatID int64, messageID int) {
+	tgBot.EXPECT().
+		Send(mock.MatchedBy(func(message tgbotapi.MessageConfig) bool {
+			return message.ChatID == chatID &&
+				strings.HasPrefix(message.Text, "<b>Updated:</b>") &&
+				strings.Contains(message.Text, "<b>Position:</b>")
+		})).
+		Return(tgbotapi.Message{MessageID: messageID}, nil).
+		Once()
+	tgBot.EXPECT().
+		Request(tgbotapi.PinChatMessageConfig{
+			ChatID:              chatID,
+			MessageID:           messageID,
+			DisableNotification: true,
+		}).
+		Return(&tgbotapi.APIResponse{Ok: true}, nil).
+		Once()
+}
+
+func expectDashboardEdited(tgBot *mocks.TgBotApi, dashboard domain.Dashboard, err error) {
+	tgBot.EXPECT().
+		Send(mock.MatchedBy(func(edit tgbotapi.EditMessageTextConfig) bool {
+			return edit.ChatID == dashboard.TelegramUserID &&
+				edit.MessageID == dashboard.MessageID &&
+				edit.ParseMode == tgbotapi.ModeHTML &&
+				strings.HasPrefix(edit.Text, "<b>Updated:</b>")
+		})).
+		Return(tgbotapi.Message{}, err).
+		Once()
+}
+
 func makePosition() domain.LiquidityPoolPosition {
 	return domain.LiquidityPoolPosition{
+		Wallet:       "0x1111111111111111111111111111111111111111",
 		Chain:        domain.ChainBase,
 		Dex:          domain.DexUniswapV3,
 		PositionLink: "https://google.com",
@@ -99,17 +736,107 @@ func makePosition() domain.LiquidityPoolPosition {
 		TickLower:   -192660,
 		CurrentTick: -191000,
 		TickUpper:   -190940,
+		Liquidity:   big.NewInt(1_000_000_000_000_000),
+
+		UncollectedFees0: big.NewInt(1_000_000_000_000_000),
+		UncollectedFees1: big.NewInt(3_500_000),
+	}
+}
+
+func makePositions(count int) []domain.LiquidityPoolPosition {
+	positions := make([]domain.LiquidityPoolPosition, 0, count)
+	for range count {
+		positions = append(positions, makePosition())
 	}
+	return positions
 }
 
 const inRangePositionText = `
 <b>Statuses:</b> ✅
 
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
+<b>Status: ✅</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+`
+
+const explorerLinksPositionText = `
+<b>Statuses:</b> ✅
+
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
 <b>Status: ✅</b>
 <b>Chain:</b> Base
 <b>Dex:</b> Uniswap V3
 <b>Position:</b> <a href="https://google.com">link</a>
-<b>Proportion:</b> WETH (3,49%) : USDC (96,51%)
+<b>Explorer:</b> <a href="https://explorer/nft/7">NFT</a> <a href="https://explorer/pool">pool</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+`
+
+const pricedPositionText = `
+<b>Statuses:</b> ✅
+<b>Total value:</b> $5778,66, fees $6,00
+
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
+<b>Status: ✅</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Value:</b> $5778,66 (WETH $105,12 : USDC $5673,54), fees $6,00
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+`
+
+const pnlPositionText = `
+<b>Statuses:</b> ✅
+<b>Total value:</b> $5778,66, fees $6,00
+
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
+<b>Status: ✅</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Value:</b> $5778,66 (WETH $105,12 : USDC $5673,54), fees $6,00
+<b>PnL vs HODL:</b> $3,16 (HODL $5785,00), IL -0,11%, fee APR 0,16%
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+`
+
+const nearEdgePositionText = `
+<b>Statuses:</b> ⚠️
+
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
+<b>Status: ⚠️</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
 <b>Range low price:</b> 1 WETH = 4298,34 USDC
 <b>Range up price:</b> 1 WETH = 5105,00 USDC
 <b>Current price:</b> 1 WETH = 5074,46 USDC
@@ -118,11 +845,15 @@ const inRangePositionText = `
 const outOfRangeLowerText = `
 <b>Statuses:</b> ❌
 
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
 <b>Status: ❌</b>
 <b>Chain:</b> Base
 <b>Dex:</b> Uniswap V3
 <b>Position:</b> <a href="https://google.com">link</a>
 <b>Proportion:</b> WETH (100,00%) : USDC (0,00%)
+<b>Amounts:</b> 1,2569 WETH : 0,0000 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
 <b>Range low price:</b> 1 WETH = 4298,34 USDC
 <b>Range up price:</b> 1 WETH = 5105,00 USDC
 <b>Current price:</b> 1 WETH = 4297,91 USDC
@@ -131,12 +862,89 @@ const outOfRangeLowerText = `
 const outOfRangeUpperText = `
 <b>Statuses:</b> ❌
 
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
 <b>Status: ❌</b>
 <b>Chain:</b> Base
 <b>Dex:</b> Uniswap V3
 <b>Position:</b> <a href="https://google.com">link</a>
 <b>Proportion:</b> WETH (0,00%) : USDC (100,00%)
+<b>Amounts:</b> 0,0000 WETH : 5887,5512 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
 <b>Range low price:</b> 1 WETH = 4298,34 USDC
 <b>Range up price:</b> 1 WETH = 5105,00 USDC
 <b>Current price:</b> 1 WETH = 5105,51 USDC
 `
+
+const severalWalletsText = `
+<b>Statuses:</b> ✅ ❌ ✅
+
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+
+<b>Status: ✅</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+
+<b>Status: ✅</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (3,62%) : USDC (96,38%)
+<b>Amounts:</b> 0,0420 WETH : 5673,5353 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+
+<b>Wallet:</b> <code>0x2222222222222222222222222222222222222222</code>
+
+<b>Status: ❌</b>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Proportion:</b> WETH (0,00%) : USDC (100,00%)
+<b>Amounts:</b> 0,0000 WETH : 5887,5512 USDC
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5105,51 USDC
+`
+
+const eventsText = `
+<b>❌ Position left range</b>
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5105,51 USDC
+
+<b>🆕 New position</b>
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+
+<b>💰 Fees ready to collect</b>
+<b>Wallet:</b> <code>0x1111111111111111111111111111111111111111</code>
+<b>Chain:</b> Base
+<b>Dex:</b> Uniswap V3
+<b>Position:</b> <a href="https://google.com">link</a>
+<b>Uncollected fees:</b> 0,0010 WETH : 3,5000 USDC
+<b>Range low price:</b> 1 WETH = 4298,34 USDC
+<b>Range up price:</b> 1 WETH = 5105,00 USDC
+<b>Current price:</b> 1 WETH = 5074,46 USDC
+`
@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/render"
+)
+
+// maxMessageLength is the Telegram limit of the message text. It is applied to the text with HTML tags,
+// which is longer than the one Telegram counts, so the messages are always accepted.
+const maxMessageLength = 4096
+
+type walletPosition struct {
+	wallet   string
+	position render.Position
+}
+
+// splitPositions renders the positions report into messages within the limit, the summary goes
+// to the first message and the wallet is repeated in every message its positions are continued in.
+func splitPositions(text string, data render.Positions, limit int) ([]string, error) {
+	return renderChunks(text, getWalletPositions(data), limit, makePositionsChunk(data))
+}
+
+// getWalletPositions flattens the positions keeping their wallets, so they can be split into chunks.
+func getWalletPositions(data render.Positions) []walletPosition {
+	var items []walletPosition
+
+	for _, wallet := range data.Wallets {
+		for _, position := range wallet.Positions {
+			items = append(items, walletPosition{wallet: wallet.Wallet, position: position})
+		}
+	}
+
+	return items
+}
+
+// makePositionsChunk returns the template data of the positions chunk, the first one carries the summary.
+func makePositionsChunk(data render.Positions) func(chunk []walletPosition, first bool) any {
+	return func(chunk []walletPosition, first bool) any {
+		part := render.Positions{Wallets: groupByWallet(chunk)}
+		if first {
+			part.Statuses = data.Statuses
+			part.TotalValue = data.TotalValue
+		}
+		return part
+	}
+}
+
+func splitEvents(text string, data render.Events, limit int) ([]string, error) {
+	return renderChunks(text, data.Events, limit, func(chunk []render.Event, _ bool) any {
+		return render.Events{Events: chunk}
+	})
+}
+
+// renderChunks renders as many items per message as fit the limit, so messages are split on item
+// boundaries and never break HTML tags. An item which does not fit alone is sent as is.
+func renderChunks[T any](
+	text string,
+	items []T,
+	limit int,
+	makeData func(chunk []T, first bool) any,
+) ([]string, error) {
+	if len(items) == 0 {
+		message, err := renderMessage(text, makeData(nil, true))
+		if err != nil {
+			return nil, fmt.Errorf("renderMessage: %w", err)
+		}
+		return []string{message}, nil
+	}
+
+	var messages []string
+
+	for start := 0; start < len(items); {
+		message, end, err := renderChunk(text, items, start, limit, makeData)
+		if err != nil {
+			return nil, fmt.Errorf("renderChunk: %w", err)
+		}
+
+		messages = append(messages, message)
+		start = end
+	}
+
+	return messages, nil
+}
+
+// renderChunk renders the longest chunk of items from start which fits the limit, and returns its end.
+// The chunk is doubled until it exceeds the limit and then bisected, so a message takes a logarithmic
+// number of renders instead of one per item.
+func renderChunk[T any](
+	text string,
+	items []T,
+	start int,
+	limit int,
+	makeData func(chunk []T, first bool) any,
+) (string, int, error) {
+	var message string
+
+	// The chunk ending at fits is rendered within the limit, the one ending at tooLong is not.
+	fits, tooLong := start, len(items)+1
+
+	for fits+1 < tooLong {
+		end := getNextEnd(start, fits, tooLong, len(items))
+
+		next, err := renderMessage(text, makeData(items[start:end], start == 0))
+		if err != nil {
+			return "", 0, fmt.Errorf("renderMessage: %w", err)
+		}
+
+		if end == start+1 || utf8.RuneCountInString(strings.TrimSpace(next)) <= limit {
+			fits, message = end, next
+		} else {
+			tooLong = end
+		}
+	}
+
+	return message, fits, nil
+}
+
+// getNextEnd doubles the chunk until the one exceeding the limit is found, then halves the remaining range.
+func getNextEnd(start, fits, tooLong, count int) int {
+	if tooLong > count {
+		return min(start+max(1, 2*(fits-start)), count)
+	}
+	return (fits + tooLong) / 2
+}
+
+// groupByWallet groups consecutive positions of the same wallet.
+func groupByWallet(items []walletPosition) []render.Wallet {
+	var wallets []render.Wallet
+
+	for _, item := range items {
+		if len(wallets) == 0 || wallets[len(wallets)-1].Wallet != item.wallet {
+			wallets = append(wallets, render.Wallet{Wallet: item.wallet})
+		}
+		last := &wallets[len(wallets)-1]
+		last.Positions = append(last.Positions, item.position)
+	}
+
+	return wallets
+}
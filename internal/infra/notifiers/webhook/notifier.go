@@ -0,0 +1,137 @@
+// Package webhook posts positions and positions events as versioned JSON to the webhook channel.
+//
+// Every request is a POST with the JSON body described by schema.json and the headers:
+//
+//	Content-Type: application/json
+//	X-Webhook-Event: positions | events
+//	X-Webhook-Version: 1
+//	X-Webhook-Signature-256: sha256=<hex HMAC-SHA256 of the raw body keyed by the channel secret>
+//
+// The signature header is omitted when the channel has no secret. Receivers should compute the HMAC
+// over the raw body bytes before parsing and compare it in constant time.
+//
+// Any 2xx response is a success, other responses and transport errors fail immediately, the outbox
+// dispatcher retries the delivery. Fields are only added within the same version, a breaking change
+// of the payload bumps the version.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+)
+
+const (
+	eventPositions = "positions"
+	eventEvents    = "events"
+
+	headerEvent     = "X-Webhook-Event"
+	headerVersion   = "X-Webhook-Version"
+	headerSignature = "X-Webhook-Signature-256"
+)
+
+type NotifierConfig struct {
+	HTTPClient *http.Client
+}
+
+// Notifier posts positions as signed JSON to the webhook channel.
+type Notifier struct {
+	config NotifierConfig
+}
+
+func NewNotifier(config NotifierConfig) *Notifier {
+	return &Notifier{
+		config: config,
+	}
+}
+
+func (n *Notifier) NotifyLiquidityPoolPositions(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	subject domain.Subject,
+	positions ...domain.LiquidityPoolPosition,
+) error {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	return n.send(ctx, channel, makePositionsPayload(subject, positions))
+}
+
+func (n *Notifier) NotifyPositionsEvents(
+	ctx context.Context,
+	channel domain.NotificationChannel,
+	subject domain.Subject,
+	events ...domain.PositionEvent,
+) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	return n.send(ctx, channel, makeEventsPayload(subject, events))
+}
+
+// post delivers the body once.
+func (n *Notifier) post(ctx context.Context, channel domain.NotificationChannel, event string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(headerEvent, event)
+	request.Header.Set(headerVersion, strconv.Itoa(payloadVersion))
+
+	if channel.Secret != "" {
+		request.Header.Set(headerSignature, "sha256="+sign(channel.Secret, body))
+	}
+
+	response, err := n.config.HTTPClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("http.Do: %w", err)
+	}
+	defer response.Body.Close() //nolint:errcheck // Nothing to do with the error.
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		reason, _ := io.ReadAll(response.Body)
+		return fmt.Errorf("%w: %s: %s", chatwebhook.ErrUnexpectedStatus, response.Status, reason)
+	}
+
+	return nil
+}
+
+// send delivers the payload once, channels without URL are skipped.
+func (n *Notifier) send(ctx context.Context, channel domain.NotificationChannel, message payload) error {
+	if channel.Target == "" {
+		return nil
+	}
+
+	body, err := jsoniter.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("jsoniter.Marshal: %w", err)
+	}
+
+	err = n.post(ctx, channel, message.Event, body)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+
+	return nil
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
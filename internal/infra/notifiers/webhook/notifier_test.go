@@ -0,0 +1,212 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/chatwebhook"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/webhook"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+type notifierSuite struct {
+	suite.Suite
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(notifierSuite))
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_Success_SignedPayload() {
+	stub := newWebhookStub(http.StatusOK)
+	defer stub.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelWebhook, Target: stub.URL, Secret: "secret"}
+
+	err := s.newNotifier(stub).NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+	s.Require().NoError(err)
+
+	s.Require().Len(stub.requests, 1)
+	request := stub.requests[0]
+	s.Require().Equal("positions", request.header.Get("X-Webhook-Event"))
+	s.Require().Equal("1", request.header.Get("X-Webhook-Version"))
+	s.Require().Equal("sha256="+sign("secret", request.body), request.header.Get("X-Webhook-Signature-256"))
+
+	body := jsoniter.Get(request.body)
+	s.Require().Equal(1, body.Get("version").ToInt())
+	s.Require().Equal(subject.TelegramUserID, body.Get("subject", "telegramUserId").ToInt64())
+
+	position := body.Get("positions", 0)
+	s.Require().Equal("0x1111111111111111111111111111111111111111", position.Get("wallet").ToString())
+	s.Require().Equal("Uniswap V3", position.Get("dex").ToString())
+	s.Require().Equal("WETH", position.Get("token0", "name").ToString())
+	s.Require().Equal(-192660, position.Get("tickLower").ToInt())
+	s.Require().Equal(-191000, position.Get("currentTick").ToInt())
+	s.Require().True(position.Get("inRange").ToBool())
+	s.Require().Equal("in_range", position.Get("status").ToString())
+	s.Require().InDelta(5074.46, position.Get("prices", "current").ToFloat64(), 0.01)
+	s.Require().InDelta(3.62, position.Get("percentages", "token0").ToFloat64(), 0.01)
+	s.Require().InDelta(3.5, position.Get("uncollectedFees", "token1").ToFloat64(), 0.0001)
+	s.Require().Equal(jsoniter.InvalidValue, position.Get("valueUsd").ValueType())
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_Staked_Emissions() {
+	stub := newWebhookStub(http.StatusOK)
+	defer stub.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelWebhook, Target: stub.URL}
+
+	position := makePosition()
+	position.Staked = true
+	position.Emissions = &domain.PositionEmissions{
+		Token:   domain.Token{Name: "AERO", Decimals: 18},
+		Pending: big.NewInt(2_500_000_000_000_000_000),
+	}
+
+	err := s.newNotifier(stub).NotifyLiquidityPoolPositions(context.Background(), channel, subject, position)
+	s.Require().NoError(err)
+
+	s.Require().Len(stub.requests, 1)
+	body := jsoniter.Get(stub.requests[0].body, "positions", 0)
+	s.Require().True(body.Get("staked").ToBool())
+	s.Require().Equal("AERO", body.Get("emissions", "token", "name").ToString())
+	s.Require().InDelta(2.5, body.Get("emissions", "pending").ToFloat64(), 0.0001)
+}
+
+func (s *notifierSuite) TestNotifyPositionsEvents_NoSecret_Unsigned() {
+	stub := newWebhookStub(http.StatusNoContent)
+	defer stub.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelWebhook, Target: stub.URL}
+
+	err := s.newNotifier(stub).NotifyPositionsEvents(context.Background(), channel, subject, domain.PositionEvent{
+		Kind:     domain.PositionEventLeftRange,
+		Position: makePosition(),
+	})
+	s.Require().NoError(err)
+
+	s.Require().Len(stub.requests, 1)
+	request := stub.requests[0]
+	s.Require().Equal("events", request.header.Get("X-Webhook-Event"))
+	s.Require().Empty(request.header.Get("X-Webhook-Signature-256"))
+	s.Require().Equal("left_range", jsoniter.Get(request.body, "events", 0, "kind").ToString())
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ServerError_NotRetried() {
+	stub := newWebhookStub(http.StatusBadGateway, http.StatusOK)
+	defer stub.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelWebhook, Target: stub.URL}
+
+	err := s.newNotifier(stub).NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+	s.Require().Len(stub.requests, 1)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_ClientError_NotRetried() {
+	stub := newWebhookStub(http.StatusBadRequest)
+	defer stub.Close()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelWebhook, Target: stub.URL}
+
+	err := s.newNotifier(stub).NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().ErrorIs(err, chatwebhook.ErrUnexpectedStatus)
+	s.Require().Len(stub.requests, 1)
+}
+
+func (s *notifierSuite) TestNotifyLiquidityPoolPositions_NoWebhook_Skipped() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	channel := domain.NotificationChannel{Kind: domain.ChannelWebhook}
+
+	notifier := webhook.NewNotifier(webhook.NotifierConfig{HTTPClient: http.DefaultClient})
+	err := notifier.NotifyLiquidityPoolPositions(context.Background(), channel, subject, makePosition())
+
+	s.Require().NoError(err)
+}
+
+func (s *notifierSuite) newNotifier(stub *webhookStub) *webhook.Notifier {
+	return webhook.NewNotifier(webhook.NotifierConfig{
+		HTTPClient: stub.Client(),
+	})
+}
+
+type recordedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookStub records the requests and answers with the statuses in order, the last one is repeated.
+type webhookStub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []recordedRequest
+}
+
+func newWebhookStub(statuses ...int) *webhookStub {
+	stub := &webhookStub{statuses: statuses}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+
+		stub.mu.Lock()
+		stub.requests = append(stub.requests, recordedRequest{header: request.Header, body: body})
+		status := stub.statuses[min(len(stub.requests), len(stub.statuses))-1]
+		stub.mu.Unlock()
+
+		writer.WriteHeader(status)
+	}))
+
+	return stub
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func makePosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		Wallet:       "0x1111111111111111111111111111111111111111",
+		Chain:        domain.ChainBase,
+		Dex:          domain.DexUniswapV3,
+		PositionLink: "https://google.com",
+		Token0: domain.Token{
+			Name:     "WETH",
+			Decimals: 18,
+		},
+		Token1: domain.Token{
+			Name:     "USDC",
+			Decimals: 6,
+		},
+		TickLower:   -192660,
+		CurrentTick: -191000,
+		TickUpper:   -190940,
+		Liquidity:   big.NewInt(1_000_000_000_000_000),
+
+		UncollectedFees0: big.NewInt(1_000_000_000_000_000),
+		UncollectedFees1: big.NewInt(3_500_000),
+	}
+}
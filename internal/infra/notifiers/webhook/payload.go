@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"strings"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// payloadVersion is bumped on breaking changes of the payload, see schema.json.
+const payloadVersion = 1
+
+type payload struct {
+	Version   int               `json:"version"`
+	Event     string            `json:"event"`
+	Subject   subjectPayload    `json:"subject"`
+	Positions []positionPayload `json:"positions,omitempty"`
+	Events    []eventPayload    `json:"events,omitempty"`
+}
+
+type subjectPayload struct {
+	TelegramUserID int64    `json:"telegramUserId"`
+	Wallets        []string `json:"wallets"`
+}
+
+type eventPayload struct {
+	Kind     string          `json:"kind"`
+	Position positionPayload `json:"position"`
+}
+
+type positionPayload struct {
+	ID              string            `json:"id"`
+	Wallet          string            `json:"wallet"`
+	Chain           string            `json:"chain"`
+	Dex             string            `json:"dex"`
+	PositionLink    string            `json:"positionLink"`
+	Token0          tokenPayload      `json:"token0"`
+	Token1          tokenPayload      `json:"token1"`
+	TickLower       int               `json:"tickLower"`
+	TickUpper       int               `json:"tickUpper"`
+	CurrentTick     int               `json:"currentTick"`
+	InRange         bool              `json:"inRange"`
+	Status          string            `json:"status"`
+	Prices          pricesPayload     `json:"prices"`
+	Percentages     pairPayload       `json:"percentages"`
+	Amounts         pairPayload       `json:"amounts"`
+	UncollectedFees pairPayload       `json:"uncollectedFees"`
+	ValueUSD        *valuePayload     `json:"valueUsd,omitempty"`
+	Staked          bool              `json:"staked"`
+	Emissions       *emissionsPayload `json:"emissions,omitempty"`
+}
+
+type tokenPayload struct {
+	Name     string `json:"name"`
+	Decimals int    `json:"decimals"`
+}
+
+// pricesPayload are prices of token0 in token1.
+type pricesPayload struct {
+	Lower   float64 `json:"lower"`
+	Upper   float64 `json:"upper"`
+	Current float64 `json:"current"`
+}
+
+type pairPayload struct {
+	Token0 float64 `json:"token0"`
+	Token1 float64 `json:"token1"`
+}
+
+type valuePayload struct {
+	Total  float64 `json:"total"`
+	Token0 float64 `json:"token0"`
+	Token1 float64 `json:"token1"`
+	Fees   float64 `json:"fees"`
+}
+
+// emissionsPayload is pending gauge rewards of the staked position in token units.
+type emissionsPayload struct {
+	Token   tokenPayload `json:"token"`
+	Pending float64      `json:"pending"`
+}
+
+func makePositionsPayload(subject domain.Subject, positions []domain.LiquidityPoolPosition) payload {
+	converted := make([]positionPayload, 0, len(positions))
+	for _, position := range positions {
+		converted = append(converted, makePositionPayload(position, subject.EdgeWarningPercent))
+	}
+
+	return payload{
+		Version:   payloadVersion,
+		Event:     eventPositions,
+		Subject:   makeSubjectPayload(subject),
+		Positions: converted,
+	}
+}
+
+func makeEventsPayload(subject domain.Subject, events []domain.PositionEvent) payload {
+	converted := make([]eventPayload, 0, len(events))
+	for _, event := range events {
+		converted = append(converted, eventPayload{
+			Kind:     toIdentifier(string(event.Kind)),
+			Position: makePositionPayload(event.Position, subject.EdgeWarningPercent),
+		})
+	}
+
+	return payload{
+		Version: payloadVersion,
+		Event:   eventEvents,
+		Subject: makeSubjectPayload(subject),
+		Events:  converted,
+	}
+}
+
+func makeSubjectPayload(subject domain.Subject) subjectPayload {
+	return subjectPayload{
+		TelegramUserID: subject.TelegramUserID,
+		Wallets:        subject.Wallets,
+	}
+}
+
+func makePositionPayload(position domain.LiquidityPoolPosition, edgeWarningPercent float64) positionPayload {
+	percent0, percent1 := position.GetTokensPercentage()
+	amount0, amount1 := position.GetTokenAmounts()
+	fees0, fees1 := position.GetUncollectedFees()
+
+	return positionPayload{
+		ID:           position.ID,
+		Wallet:       position.Wallet,
+		Chain:        string(position.Chain),
+		Dex:          string(position.Dex),
+		PositionLink: position.PositionLink,
+		Token0:       tokenPayload{Name: position.Token0.Name, Decimals: position.Token0.Decimals},
+		Token1:       tokenPayload{Name: position.Token1.Name, Decimals: position.Token1.Decimals},
+		TickLower:    position.TickLower,
+		TickUpper:    position.TickUpper,
+		CurrentTick:  position.CurrentTick,
+		InRange:      position.IsInRange(),
+		Status:       toIdentifier(string(position.GetStatus(edgeWarningPercent))),
+		Prices: pricesPayload{
+			Lower:   position.GetLowerPrice(),
+			Upper:   position.GetUpperPrice(),
+			Current: position.GetCurrentPrice(),
+		},
+		Percentages:     pairPayload{Token0: percent0, Token1: percent1},
+		Amounts:         pairPayload{Token0: amount0, Token1: amount1},
+		UncollectedFees: pairPayload{Token0: fees0, Token1: fees1},
+		ValueUSD:        makeValuePayload(position),
+		Staked:          position.Staked,
+		Emissions:       makeEmissionsPayload(position),
+	}
+}
+
+func makeEmissionsPayload(position domain.LiquidityPoolPosition) *emissionsPayload {
+	if position.Emissions == nil {
+		return nil
+	}
+
+	return &emissionsPayload{
+		Token:   tokenPayload{Name: position.Emissions.Token.Name, Decimals: position.Emissions.Token.Decimals},
+		Pending: position.Emissions.GetPending(),
+	}
+}
+
+func makeValuePayload(position domain.LiquidityPoolPosition) *valuePayload {
+	value, ok := position.GetValueUSD()
+	if !ok {
+		return nil
+	}
+
+	return &valuePayload{
+		Total:  value.GetTotalUSD(),
+		Token0: value.Token0USD,
+		Token1: value.Token1USD,
+		Fees:   value.FeesUSD,
+	}
+}
+
+// toIdentifier turns human readable domain values like "Left range" into stable identifiers like "left_range".
+func toIdentifier(value string) string {
+	return strings.ReplaceAll(strings.ToLower(value), " ", "_")
+}
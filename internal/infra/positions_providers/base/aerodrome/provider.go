@@ -3,21 +3,29 @@ package aerodrome
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"time"
 
 	"github.com/hasura/go-graphql-client"
 	"github.com/samber/lo"
 
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
 )
 
+type ProviderTheGraphConfig struct {
+	// PositionManager is the NFT contract of the positions, the subgraph does not know it.
+	PositionManager string
+}
+
 type ProviderTheGraph struct {
 	client *graphql.Client
+	config ProviderTheGraphConfig
 }
 
-func NewProviderTheGraph(client *graphql.Client) *ProviderTheGraph {
+func NewProviderTheGraph(client *graphql.Client, config ProviderTheGraphConfig) *ProviderTheGraph {
 	return &ProviderTheGraph{
 		client: client,
+		config: config,
 	}
 }
 
@@ -40,66 +48,124 @@ func (provider *ProviderTheGraph) GetPositionsWithLiquidity(
 		return nil, fmt.Errorf("graphql.Query: %w", err)
 	}
 
-	return convertToDomain(unclosedPosition.Positions), nil
+	return provider.convertToDomain(wallet, unclosedPosition.Positions), nil
 }
 
-func convertToDomain(unclosedPositions []position) []domain.LiquidityPoolPosition {
+func (provider *ProviderTheGraph) convertToDomain(
+	wallet string,
+	unclosedPositions []position,
+) []domain.LiquidityPoolPosition {
 	if len(unclosedPositions) == 0 {
 		return nil
 	}
 
 	return lo.Map(unclosedPositions, func(pos position, _ int) domain.LiquidityPoolPosition {
+		currentTick := subgraph.MustConvertToInt(pos.Pool.Tick)
+		tickLower := subgraph.MustConvertToInt(pos.TickLower.TickIdx)
+		tickUpper := subgraph.MustConvertToInt(pos.TickUpper.TickIdx)
+		liquidity := subgraph.MustConvertToBigInt(pos.Liquidity)
+		fees0, fees1 := domain.GetUncollectedFees(currentTick, tickLower, tickUpper, liquidity, pos.toFeeGrowth())
+
 		return domain.LiquidityPoolPosition{
-			Chain:        domain.ChainBase,
-			Dex:          domain.DexAerodrome,
-			PositionLink: "https://aerodrome.finance/dash",
+			ID:          pos.ID,
+			Wallet:      wallet,
+			Chain:       domain.ChainBase,
+			Dex:         domain.DexAerodrome,
+			Pool:        pos.Pool.ID,
+			NFTContract: provider.config.PositionManager,
 			Token0: domain.Token{
 				Name:     pos.Pool.Token0.Symbol,
-				Decimals: mustConvertToInt(pos.Pool.Token0.Decimals),
+				Decimals: subgraph.MustConvertToInt(pos.Pool.Token0.Decimals),
+				Address:  pos.Pool.Token0.ID,
 			},
 			Token1: domain.Token{
 				Name:     pos.Pool.Token1.Symbol,
-				Decimals: mustConvertToInt(pos.Pool.Token1.Decimals),
+				Decimals: subgraph.MustConvertToInt(pos.Pool.Token1.Decimals),
+				Address:  pos.Pool.Token1.ID,
 			},
-			CurrentTick: mustConvertToInt(pos.Pool.Tick),
-			TickLower:   mustConvertToInt(pos.TickLower.TickIdx),
-			TickUpper:   mustConvertToInt(pos.TickUpper.TickIdx),
+			CurrentTick:      currentTick,
+			TickLower:        tickLower,
+			TickUpper:        tickUpper,
+			Liquidity:        liquidity,
+			SqrtPriceX96:     subgraph.MustConvertToBigInt(pos.Pool.SqrtPrice),
+			UncollectedFees0: fees0,
+			UncollectedFees1: fees1,
+			Deposits:         pos.toDeposits(),
 		}
 	})
 }
 
-func mustConvertToInt(value string) int {
-	integer, err := strconv.Atoi(value)
-	if err != nil {
-		message := "mustConvertToInt: " + value
-		panic(message)
-	}
-
-	return integer
-}
-
 type unclosedPositionsQuery struct {
 	Positions []position `graphql:"positions(where: {owner: $wallet, liquidity_gt: 0})"`
 }
 
 type position struct {
-	ID        string
-	TickLower tick
-	TickUpper tick
-	Pool      pool
+	ID                       string
+	Liquidity                string
+	FeeGrowthInside0LastX128 string
+	FeeGrowthInside1LastX128 string
+	DepositedToken0          string
+	DepositedToken1          string
+	WithdrawnToken0          string
+	WithdrawnToken1          string
+	CollectedFeesToken0      string
+	CollectedFeesToken1      string
+	Transaction              transaction
+	TickLower                tick
+	TickUpper                tick
+	Pool                     pool
+}
+
+// toFeeGrowth collects fee accounting data, the subgraph does not expose tokensOwed, so fees
+// credited to the position on liquidity changes are not counted.
+func (pos position) toFeeGrowth() domain.FeeGrowth {
+	return domain.FeeGrowth{
+		Global0X128:       subgraph.MustConvertToBigInt(pos.Pool.FeeGrowthGlobal0X128),
+		Global1X128:       subgraph.MustConvertToBigInt(pos.Pool.FeeGrowthGlobal1X128),
+		OutsideLower0X128: subgraph.MustConvertToBigInt(pos.TickLower.FeeGrowthOutside0X128),
+		OutsideLower1X128: subgraph.MustConvertToBigInt(pos.TickLower.FeeGrowthOutside1X128),
+		OutsideUpper0X128: subgraph.MustConvertToBigInt(pos.TickUpper.FeeGrowthOutside0X128),
+		OutsideUpper1X128: subgraph.MustConvertToBigInt(pos.TickUpper.FeeGrowthOutside1X128),
+		InsideLast0X128:   subgraph.MustConvertToBigInt(pos.FeeGrowthInside0LastX128),
+		InsideLast1X128:   subgraph.MustConvertToBigInt(pos.FeeGrowthInside1LastX128),
+	}
+}
+
+// toDeposits collects the position history, amounts are already in token units.
+func (pos position) toDeposits() *domain.PositionDeposits {
+	return &domain.PositionDeposits{
+		Deposited0:     subgraph.MustConvertToFloat(pos.DepositedToken0),
+		Deposited1:     subgraph.MustConvertToFloat(pos.DepositedToken1),
+		Withdrawn0:     subgraph.MustConvertToFloat(pos.WithdrawnToken0),
+		Withdrawn1:     subgraph.MustConvertToFloat(pos.WithdrawnToken1),
+		CollectedFees0: subgraph.MustConvertToFloat(pos.CollectedFeesToken0),
+		CollectedFees1: subgraph.MustConvertToFloat(pos.CollectedFeesToken1),
+		OpenedAt:       time.Unix(int64(subgraph.MustConvertToInt(pos.Transaction.Timestamp)), 0).UTC(),
+	}
+}
+
+type transaction struct {
+	Timestamp string
 }
 
 type pool struct {
-	Tick   string
-	Token0 token
-	Token1 token
+	ID                   string
+	Tick                 string
+	SqrtPrice            string
+	FeeGrowthGlobal0X128 string
+	FeeGrowthGlobal1X128 string
+	Token0               token
+	Token1               token
 }
 
 type token struct {
+	ID       string
 	Symbol   string
 	Decimals string
 }
 
 type tick struct {
-	TickIdx string
+	TickIdx               string
+	FeeGrowthOutside0X128 string
+	FeeGrowthOutside1X128 string
 }
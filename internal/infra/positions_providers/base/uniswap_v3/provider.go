@@ -1,101 +0,0 @@
-package uniswap_v3
-
-import (
-	"context"
-	"fmt"
-	"strconv"
-
-	"github.com/hasura/go-graphql-client"
-	"github.com/samber/lo"
-
-	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
-)
-
-type ProviderTheGraph struct {
-	client *graphql.Client
-}
-
-func NewProviderTheGraph(client *graphql.Client) *ProviderTheGraph {
-	return &ProviderTheGraph{
-		client: client,
-	}
-}
-
-func (*ProviderTheGraph) GetName() string {
-	return "Base Uniswap V3"
-}
-
-func (provider *ProviderTheGraph) GetPositionsWithLiquidity(
-	ctx context.Context,
-	wallet string,
-) ([]domain.LiquidityPoolPosition, error) {
-	var unclosedPosition unclosedPositionsQuery
-
-	variables := map[string]any{
-		"wallet": wallet,
-	}
-
-	err := provider.client.Query(ctx, &unclosedPosition, variables)
-	if err != nil {
-		return nil, fmt.Errorf("graphql.Query: %w", err)
-	}
-
-	return convertToDomain(unclosedPosition.Positions), nil
-}
-
-func convertToDomain(unclosedPositions []position) []domain.LiquidityPoolPosition {
-	if len(unclosedPositions) == 0 {
-		return nil
-	}
-
-	return lo.Map(unclosedPositions, func(pos position, _ int) domain.LiquidityPoolPosition {
-		return domain.LiquidityPoolPosition{
-			Chain:        domain.ChainBase,
-			Dex:          domain.DexUniswapV3,
-			PositionLink: "https://app.uniswap.org/positions/v3/base/" + pos.ID,
-			TickLower:    pos.TickLower,
-			TickUpper:    pos.TickUpper,
-			CurrentTick:  mustConvertToInt(pos.Pool.Tick),
-			Token0: domain.Token{
-				Name:     pos.Pool.Token0.Symbol,
-				Decimals: mustConvertToInt(pos.Pool.Token0.Decimals),
-			},
-			Token1: domain.Token{
-				Name:     pos.Pool.Token1.Symbol,
-				Decimals: mustConvertToInt(pos.Pool.Token1.Decimals),
-			},
-		}
-	})
-}
-
-func mustConvertToInt(value string) int {
-	integer, err := strconv.Atoi(value)
-	if err != nil {
-		message := "mustConvertToInt: " + value
-		panic(message)
-	}
-
-	return integer
-}
-
-type unclosedPositionsQuery struct {
-	Positions []position `graphql:"positions(where: {owner: $wallet, liquidity_gt: 0})"`
-}
-
-type position struct {
-	ID        string
-	TickLower int
-	TickUpper int
-	Pool      pool
-}
-
-type pool struct {
-	Tick   string
-	Token0 token
-	Token1 token
-}
-
-type token struct {
-	Symbol   string
-	Decimals string
-}
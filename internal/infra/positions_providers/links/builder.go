@@ -0,0 +1,112 @@
+// Package links makes links of the positions to the dex apps and to the block explorers.
+package links
+
+import (
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// Key selects the dex app deployment.
+type Key struct {
+	Chain domain.Chain
+	Dex   domain.Dex
+}
+
+type positionLink func(position domain.LiquidityPoolPosition) string
+
+// Builder makes links of the positions, positions of unknown chains and dexes keep links they have.
+type Builder struct {
+	positions map[Key]positionLink
+	explorers map[domain.Chain]string
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		positions: getPositionLinks(),
+		explorers: getExplorers(),
+	}
+}
+
+// Fill returns the position with the dex app link and the explorer links of the NFT and the pool.
+func (builder *Builder) Fill(position domain.LiquidityPoolPosition) domain.LiquidityPoolPosition {
+	if makeLink, ok := builder.positions[Key{Chain: position.Chain, Dex: position.Dex}]; ok {
+		position.PositionLink = makeLink(position)
+	}
+
+	explorer, ok := builder.explorers[position.Chain]
+	if !ok {
+		return position
+	}
+
+	if position.NFTContract != "" {
+		position.NFTLink = explorer + "/nft/" + position.NFTContract + "/" + position.ID
+	}
+
+	if position.Pool != "" {
+		position.PoolLink = explorer + "/address/" + position.Pool
+	}
+
+	return position
+}
+
+// getExplorers returns Etherscan family explorers, all of them have the same paths.
+func getExplorers() map[domain.Chain]string {
+	return map[domain.Chain]string{
+		domain.ChainBase:     "https://basescan.org",
+		domain.ChainEthereum: "https://etherscan.io",
+		domain.ChainArbitrum: "https://arbiscan.io",
+		domain.ChainOptimism: "https://optimistic.etherscan.io",
+		domain.ChainPolygon:  "https://polygonscan.com",
+	}
+}
+
+func getPositionLinks() map[Key]positionLink {
+	links := map[Key]positionLink{
+		// The Aerodrome app manages Slipstream positions by the NFT within its pool.
+		{Chain: domain.ChainBase, Dex: domain.DexAerodrome}: func(position domain.LiquidityPoolPosition) string {
+			return "https://aerodrome.finance/increase?nft=" + position.ID + "&pool=" + position.Pool
+		},
+	}
+
+	for chain, slug := range getChainSlugs() {
+		links[Key{Chain: chain, Dex: domain.DexUniswapV3}] = withPrefix("https://app.uniswap.org/positions/v3/" + slug + "/")
+		links[Key{Chain: chain, Dex: domain.DexUniswapV4}] = withPrefix("https://app.uniswap.org/positions/v4/" + slug + "/")
+		// The Sushi app addresses positions within their pool.
+		links[Key{Chain: chain, Dex: domain.DexSushiSwapV3}] = func(position domain.LiquidityPoolPosition) string {
+			return "https://www.sushi.com/" + slug + "/pool/v3/" + position.Pool + "/" + position.ID
+		}
+	}
+
+	for chain, param := range getPancakeSwapChains() {
+		links[Key{Chain: chain, Dex: domain.DexPancakeSwapV3}] = func(position domain.LiquidityPoolPosition) string {
+			return "https://pancakeswap.finance/liquidity/" + position.ID + "?chain=" + param
+		}
+	}
+
+	return links
+}
+
+// getChainSlugs returns the chain names in the Uniswap and Sushi app links.
+func getChainSlugs() map[domain.Chain]string {
+	return map[domain.Chain]string{
+		domain.ChainBase:     "base",
+		domain.ChainEthereum: "ethereum",
+		domain.ChainArbitrum: "arbitrum",
+		domain.ChainOptimism: "optimism",
+		domain.ChainPolygon:  "polygon",
+	}
+}
+
+// getPancakeSwapChains returns the chain query parameter of the PancakeSwap app links.
+func getPancakeSwapChains() map[domain.Chain]string {
+	return map[domain.Chain]string{
+		domain.ChainBase:     "base",
+		domain.ChainEthereum: "eth",
+		domain.ChainArbitrum: "arb",
+	}
+}
+
+func withPrefix(prefix string) positionLink {
+	return func(position domain.LiquidityPoolPosition) string {
+		return prefix + position.ID
+	}
+}
@@ -0,0 +1,100 @@
+package links_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/links"
+)
+
+const (
+	positionManager = "0x2222222222222222222222222222222222222222"
+	pool            = "0x4444444444444444444444444444444444444444"
+)
+
+type builderSuite struct {
+	suite.Suite
+}
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(builderSuite))
+}
+
+func (s *builderSuite) TestFill_UniswapV3_PositionAndExplorerLinks() {
+	position := links.NewBuilder().Fill(domain.LiquidityPoolPosition{
+		ID:          "7",
+		Chain:       domain.ChainArbitrum,
+		Dex:         domain.DexUniswapV3,
+		Pool:        pool,
+		NFTContract: positionManager,
+	})
+
+	s.Require().Equal("https://app.uniswap.org/positions/v3/arbitrum/7", position.PositionLink)
+	s.Require().Equal("https://arbiscan.io/nft/"+positionManager+"/7", position.NFTLink)
+	s.Require().Equal("https://arbiscan.io/address/"+pool, position.PoolLink)
+}
+
+func (s *builderSuite) TestFill_BaseAerodrome_PositionAndExplorerLinks() {
+	position := links.NewBuilder().Fill(domain.LiquidityPoolPosition{
+		ID:          "7",
+		Chain:       domain.ChainBase,
+		Dex:         domain.DexAerodrome,
+		Pool:        pool,
+		NFTContract: positionManager,
+	})
+
+	s.Require().Equal("https://aerodrome.finance/increase?nft=7&pool="+pool, position.PositionLink)
+	s.Require().Equal("https://basescan.org/nft/"+positionManager+"/7", position.NFTLink)
+	s.Require().Equal("https://basescan.org/address/"+pool, position.PoolLink)
+}
+
+func (s *builderSuite) TestFill_SushiSwapV3_LinkWithinPool() {
+	position := links.NewBuilder().Fill(domain.LiquidityPoolPosition{
+		ID:    "7",
+		Chain: domain.ChainPolygon,
+		Dex:   domain.DexSushiSwapV3,
+		Pool:  pool,
+	})
+
+	s.Require().Equal("https://www.sushi.com/polygon/pool/v3/"+pool+"/7", position.PositionLink)
+	s.Require().Empty(position.NFTLink)
+}
+
+func (s *builderSuite) TestFill_PancakeSwapV3_ChainParameter() {
+	position := links.NewBuilder().Fill(domain.LiquidityPoolPosition{
+		ID:    "7",
+		Chain: domain.ChainEthereum,
+		Dex:   domain.DexPancakeSwapV3,
+	})
+
+	s.Require().Equal("https://pancakeswap.finance/liquidity/7?chain=eth", position.PositionLink)
+}
+
+func (s *builderSuite) TestFill_UniswapV4_NoPoolLink() {
+	position := links.NewBuilder().Fill(domain.LiquidityPoolPosition{
+		ID:          "7",
+		Chain:       domain.ChainBase,
+		Dex:         domain.DexUniswapV4,
+		NFTContract: positionManager,
+	})
+
+	s.Require().Equal("https://app.uniswap.org/positions/v4/base/7", position.PositionLink)
+	s.Require().Equal("https://basescan.org/nft/"+positionManager+"/7", position.NFTLink)
+	s.Require().Empty(position.PoolLink)
+}
+
+func (s *builderSuite) TestFill_UnknownChain_LinkKept() {
+	position := links.NewBuilder().Fill(domain.LiquidityPoolPosition{
+		ID:           "7",
+		Chain:        "Unknown",
+		Dex:          domain.DexUniswapV3,
+		PositionLink: "https://positions/7",
+		Pool:         pool,
+	})
+
+	s.Require().Equal("https://positions/7", position.PositionLink)
+	s.Require().Empty(position.PoolLink)
+}
@@ -0,0 +1,45 @@
+package links
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// Provider fills links of the positions read by the wrapped provider.
+type Provider struct {
+	impl    domain.LiquidityPoolPositionsProvider
+	builder *Builder
+}
+
+func NewProvider(impl domain.LiquidityPoolPositionsProvider, builder *Builder) *Provider {
+	return &Provider{
+		impl:    impl,
+		builder: builder,
+	}
+}
+
+func (provider *Provider) GetName() string {
+	return provider.impl.GetName()
+}
+
+func (provider *Provider) GetPositionsWithLiquidity(
+	ctx context.Context,
+	wallet string,
+) ([]domain.LiquidityPoolPosition, error) {
+	positions, err := provider.impl.GetPositionsWithLiquidity(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("GetPositionsWithLiquidity: %w", err)
+	}
+
+	if len(positions) == 0 {
+		return positions, nil
+	}
+
+	return lo.Map(positions, func(position domain.LiquidityPoolPosition, _ int) domain.LiquidityPoolPosition {
+		return provider.builder.Fill(position)
+	}), nil
+}
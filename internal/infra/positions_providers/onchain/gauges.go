@@ -0,0 +1,336 @@
+package onchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/iter"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+const (
+	selectorStakedValues = "4b937763" // stakedValues(address)
+	selectorEarned       = "3e491d47" // earned(address,uint256)
+	selectorRewardToken  = "f7c618c1" // rewardToken()
+	selectorIsGauge      = "aa79979b" // isGauge(address)
+
+	// eventDeposit is emitted by the gauge on stake, the depositor is the first indexed argument.
+	eventDeposit = "Deposit(address,uint256,uint128)"
+)
+
+// GaugesConfig enables positions staked to Slipstream gauges. The gauge owns the staked NFT, so gauges
+// of the wallet are discovered from its deposit events and asked for the staked token IDs.
+type GaugesConfig struct {
+	// Voter registers gauges, deposit events of other contracts are ignored.
+	Voter string
+	// FromBlock is the first block scanned for deposits, e.g. the gauge factory deployment block.
+	FromBlock uint64
+	// BlockRange limits blocks of the single eth_getLogs request.
+	BlockRange uint64
+	// MaxScanBlocks limits blocks scanned for the wallet by a single poll, so the backfill since FromBlock
+	// spreads over polls. Zero scans up to the latest block.
+	MaxScanBlocks uint64
+	// Scans are optional, they keep the scan progress across restarts, so the backfill is done once.
+	Scans domain.GaugesScansRepository
+}
+
+// holding is the position NFT of the wallet, the gauge is empty unless the NFT is staked.
+type holding struct {
+	tokenID *big.Int
+	gauge   string
+}
+
+// gaugesScan is the progress of the wallet deposits scan, it is locked while the wallet is scanned.
+type gaugesScan struct {
+	mu sync.Mutex
+	// loaded is set once the saved progress is read.
+	loaded    bool
+	nextBlock uint64
+	gauges    map[string]struct{}
+}
+
+// addGauges remembers the deposit event emitters registered by the voter.
+func (provider *ProviderRPC) addGauges(ctx context.Context, scan *gaugesScan, emitters []string) error {
+	for _, emitter := range emitters {
+		if _, known := scan.gauges[emitter]; known {
+			continue
+		}
+
+		registered, err := provider.isGauge(ctx, emitter)
+		if err != nil {
+			return fmt.Errorf("isGauge: %w", err)
+		}
+		if registered {
+			scan.gauges[emitter] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// discoverGauges scans deposit events of the wallet since the last scan and returns all gauges it staked to.
+// Gauges of blocks beyond MaxScanBlocks are found by the next polls.
+func (provider *ProviderRPC) discoverGauges(ctx context.Context, wallet string) ([]string, error) {
+	scan := provider.getScan(wallet)
+
+	scan.mu.Lock()
+	defer scan.mu.Unlock()
+
+	err := provider.loadScan(ctx, wallet, scan)
+	if err != nil {
+		return nil, fmt.Errorf("loadScan: %w", err)
+	}
+
+	scanned := scan.nextBlock
+
+	err = provider.scanBlocks(ctx, wallet, scan)
+	if err != nil {
+		return nil, fmt.Errorf("scanBlocks: %w", err)
+	}
+
+	gauges := lo.Keys(scan.gauges)
+	slices.Sort(gauges)
+
+	if scan.nextBlock > scanned {
+		err = provider.saveScan(ctx, wallet, scan.nextBlock, gauges)
+		if err != nil {
+			return nil, fmt.Errorf("saveScan: %w", err)
+		}
+	}
+
+	return gauges, nil
+}
+
+func (provider *ProviderRPC) getEmissions(
+	ctx context.Context,
+	wallet string,
+	stake holding,
+) (domain.PositionEmissions, error) {
+	account, err := ethrpc.EncodeAddress(wallet)
+	if err != nil {
+		return domain.PositionEmissions{}, fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	data := ethrpc.MustEncodeCall(selectorEarned, account, ethrpc.EncodeUint(stake.tokenID))
+
+	earned, err := provider.call(ctx, stake.gauge, data, 1)
+	if err != nil {
+		return domain.PositionEmissions{}, fmt.Errorf("earned: %w", err)
+	}
+
+	rewardToken, err := provider.call(ctx, stake.gauge, ethrpc.MustEncodeCall(selectorRewardToken), 1)
+	if err != nil {
+		return domain.PositionEmissions{}, fmt.Errorf("rewardToken: %w", err)
+	}
+
+	token, err := provider.getToken(ctx, rewardToken.Address(0))
+	if err != nil {
+		return domain.PositionEmissions{}, fmt.Errorf("getToken: %w", err)
+	}
+
+	return domain.PositionEmissions{
+		Token:   token,
+		Pending: earned.Uint(0),
+	}, nil
+}
+
+// getHolding returns the position of the NFT, staked positions are completed with the gauge rewards.
+func (provider *ProviderRPC) getHolding(
+	ctx context.Context,
+	wallet string,
+	nft holding,
+) (*domain.LiquidityPoolPosition, error) {
+	position, err := provider.getPosition(ctx, wallet, nft.tokenID)
+	if err != nil || position == nil || nft.gauge == "" {
+		return position, err
+	}
+
+	emissions, err := provider.getEmissions(ctx, wallet, nft)
+	if err != nil {
+		return nil, fmt.Errorf("getEmissions: %w", err)
+	}
+
+	position.Staked = true
+	position.Emissions = &emissions
+	position.UncollectedFees0 = new(big.Int)
+	position.UncollectedFees1 = new(big.Int)
+
+	return position, nil
+}
+
+func (provider *ProviderRPC) getScan(wallet string) *gaugesScan {
+	key := strings.ToLower(wallet)
+
+	provider.scansMu.Lock()
+	defer provider.scansMu.Unlock()
+
+	scan, ok := provider.scans[key]
+	if !ok {
+		scan = &gaugesScan{
+			nextBlock: provider.config.Gauges.FromBlock,
+			gauges:    make(map[string]struct{}),
+		}
+		provider.scans[key] = scan
+	}
+
+	return scan
+}
+
+// getStakes returns NFTs the wallet staked to gauges, nothing if gauges are not configured.
+func (provider *ProviderRPC) getStakes(ctx context.Context, wallet string) ([]holding, error) {
+	if provider.config.Gauges == nil {
+		return nil, nil
+	}
+
+	gauges, err := provider.discoverGauges(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("discoverGauges: %w", err)
+	}
+
+	stakes, err := iter.MapErr(gauges, func(gauge *string) ([]holding, error) {
+		return provider.getStakedValues(ctx, wallet, *gauge)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getStakedValues: %w", err)
+	}
+
+	return lo.Flatten(stakes), nil
+}
+
+func (provider *ProviderRPC) getStakedValues(ctx context.Context, wallet, gauge string) ([]holding, error) {
+	depositor, err := ethrpc.EncodeAddress(wallet)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	result, err := provider.client.Call(ctx, gauge, ethrpc.MustEncodeCall(selectorStakedValues, depositor))
+	if err != nil {
+		return nil, fmt.Errorf("stakedValues: %w", err)
+	}
+
+	tokenIDs, err := ethrpc.DecodeUints(result)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.DecodeUints: %w", err)
+	}
+
+	return lo.Map(tokenIDs, func(tokenID *big.Int, _ int) holding {
+		return holding{tokenID: tokenID, gauge: gauge}
+	}), nil
+}
+
+func (provider *ProviderRPC) isGauge(ctx context.Context, address string) (bool, error) {
+	encoded, err := ethrpc.EncodeAddress(address)
+	if err != nil {
+		return false, fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	registered, err := provider.call(ctx, provider.config.Gauges.Voter, ethrpc.MustEncodeCall(selectorIsGauge, encoded), 1)
+	if err != nil {
+		return false, fmt.Errorf("isGauge: %w", err)
+	}
+
+	return registered.Uint(0).Sign() != 0, nil
+}
+
+// loadScan restores the saved progress of the wallet scan once, the scan starts from FromBlock
+// if nothing is saved.
+func (provider *ProviderRPC) loadScan(ctx context.Context, wallet string, scan *gaugesScan) error {
+	scans := provider.config.Gauges.Scans
+	if scan.loaded || scans == nil {
+		return nil
+	}
+
+	saved, err := scans.Get(ctx, provider.config.Chain, strings.ToLower(wallet))
+	if errors.Is(err, domain.ErrGaugesScanNotFound) {
+		scan.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("GaugesScansRepository.Get: %w", err)
+	}
+
+	scan.loaded = true
+	scan.nextBlock = max(scan.nextBlock, saved.NextBlock)
+	for _, gauge := range saved.Gauges {
+		scan.gauges[gauge] = struct{}{}
+	}
+
+	return nil
+}
+
+// saveScan keeps the progress of the wallet scan if scans are configured.
+func (provider *ProviderRPC) saveScan(ctx context.Context, wallet string, nextBlock uint64, gauges []string) error {
+	scans := provider.config.Gauges.Scans
+	if scans == nil {
+		return nil
+	}
+
+	err := scans.Add(ctx, domain.GaugesScan{
+		Chain:     provider.config.Chain,
+		Wallet:    strings.ToLower(wallet),
+		NextBlock: nextBlock,
+		Gauges:    gauges,
+	})
+	if err != nil {
+		return fmt.Errorf("GaugesScansRepository.Add: %w", err)
+	}
+
+	return nil
+}
+
+// scanBlocks scans deposits from the next block up to the latest one or MaxScanBlocks.
+func (provider *ProviderRPC) scanBlocks(ctx context.Context, wallet string, scan *gaugesScan) error {
+	latest, err := provider.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("ethrpc.BlockNumber: %w", err)
+	}
+
+	if maxBlocks := provider.config.Gauges.MaxScanBlocks; maxBlocks > 0 {
+		latest = min(latest, scan.nextBlock+maxBlocks-1)
+	}
+
+	blockRange := max(provider.config.Gauges.BlockRange, 1)
+
+	for scan.nextBlock <= latest {
+		err = provider.scanDeposits(ctx, wallet, scan, min(scan.nextBlock+blockRange-1, latest))
+		if err != nil {
+			return fmt.Errorf("scanDeposits: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanDeposits collects gauges from deposit events of the wallet up to toBlock inclusive.
+func (provider *ProviderRPC) scanDeposits(ctx context.Context, wallet string, scan *gaugesScan, toBlock uint64) error {
+	depositor, err := ethrpc.EncodeAddress(wallet)
+	if err != nil {
+		return fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	logs, err := provider.client.GetLogs(ctx, scan.nextBlock, toBlock, ethrpc.Keccak256([]byte(eventDeposit)), depositor)
+	if err != nil {
+		return fmt.Errorf("ethrpc.GetLogs: %w", err)
+	}
+
+	emitters := lo.Uniq(lo.Map(logs, func(log ethrpc.Log, _ int) string {
+		return strings.ToLower(log.Address)
+	}))
+
+	err = provider.addGauges(ctx, scan, emitters)
+	if err != nil {
+		return fmt.Errorf("addGauges: %w", err)
+	}
+
+	scan.nextBlock = toBlock + 1
+
+	return nil
+}
@@ -0,0 +1,430 @@
+package onchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/iter"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+// FactoryKind is the getPool selector of the factory, it also defines the pool ABI flavour.
+type FactoryKind string
+
+const (
+	// FactoryUniswapV3 pools are keyed by fee: getPool(address,address,uint24).
+	FactoryUniswapV3 FactoryKind = "1698ee82"
+	// FactorySlipstream pools are keyed by tick spacing: getPool(address,address,int24).
+	FactorySlipstream FactoryKind = "28af8d0b"
+)
+
+const (
+	selectorBalanceOf           = "70a08231" // balanceOf(address)
+	selectorTokenOfOwnerByIndex = "2f745c59" // tokenOfOwnerByIndex(address,uint256)
+	selectorPositions           = "99fbab88" // positions(uint256)
+	selectorSlot0               = "3850c7bd" // slot0()
+	selectorFeeGrowthGlobal0    = "f3058399" // feeGrowthGlobal0X128()
+	selectorFeeGrowthGlobal1    = "46141319" // feeGrowthGlobal1X128()
+	selectorTicks               = "f30dba93" // ticks(int24)
+	selectorSymbol              = "95d89b41" // symbol()
+	selectorDecimals            = "313ce567" // decimals()
+)
+
+// Words of positions(uint256) result, the same for Uniswap V3 and Slipstream position managers.
+const (
+	positionToken0Word = iota + 2
+	positionToken1Word
+	positionPoolKeyWord
+	positionTickLowerWord
+	positionTickUpperWord
+	positionLiquidityWord
+	positionFeeGrowthInside0LastWord
+	positionFeeGrowthInside1LastWord
+	positionTokensOwed0Word
+	positionTokensOwed1Word
+	positionWords
+)
+
+const (
+	slot0SqrtPriceWord = iota
+	slot0TickWord
+	slot0Words
+)
+
+// Words of ticks(int24) result, Slipstream pools have extra stakedLiquidityNet before fee growth.
+const (
+	ticksFeeGrowthOutside0Word = 2
+	ticksSlipstreamShift       = 1
+	ticksWords                 = 8
+)
+
+type ProviderRPCConfig struct {
+	Name            string
+	Chain           domain.Chain
+	Dex             domain.Dex
+	PositionManager string
+	Factory         string
+	FactoryKind     FactoryKind
+	// Gauges enables positions staked to gauges, nil disables.
+	Gauges *GaugesConfig
+}
+
+// ProviderRPC reads positions directly from the NonfungiblePositionManager contract.
+type ProviderRPC struct {
+	client *ethrpc.Client
+	config ProviderRPCConfig
+
+	tokensMu sync.Mutex
+	tokens   map[string]domain.Token
+
+	// scansMu guards the map only, each wallet scan is locked on its own.
+	scansMu sync.Mutex
+	scans   map[string]*gaugesScan
+}
+
+func NewProviderRPC(client *ethrpc.Client, config ProviderRPCConfig) *ProviderRPC {
+	return &ProviderRPC{
+		client: client,
+		config: config,
+		tokens: make(map[string]domain.Token),
+		scans:  make(map[string]*gaugesScan),
+	}
+}
+
+func (provider *ProviderRPC) GetName() string {
+	return provider.config.Name
+}
+
+func (provider *ProviderRPC) GetPositionsWithLiquidity(
+	ctx context.Context,
+	wallet string,
+) ([]domain.LiquidityPoolPosition, error) {
+	holdings, err := provider.getHoldings(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("getHoldings: %w", err)
+	}
+
+	positions, err := iter.MapErr(holdings, func(nft *holding) (*domain.LiquidityPoolPosition, error) {
+		return provider.getHolding(ctx, wallet, *nft)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getHolding: %w", err)
+	}
+
+	if len(positions) == 0 {
+		return nil, nil
+	}
+
+	open := lo.Compact(positions)
+
+	return lo.Map(open, func(position *domain.LiquidityPoolPosition, _ int) domain.LiquidityPoolPosition {
+		return *position
+	}), nil
+}
+
+func (provider *ProviderRPC) call(ctx context.Context, to string, data []byte, words int) (ethrpc.Words, error) {
+	result, err := provider.client.Call(ctx, to, data)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.Call: %w", err)
+	}
+
+	decoded, err := ethrpc.DecodeWords(result, words)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.DecodeWords: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// getHoldings returns NFTs of the wallet and NFTs it staked.
+func (provider *ProviderRPC) getHoldings(ctx context.Context, wallet string) ([]holding, error) {
+	tokenIDs, err := provider.getTokenIDs(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("getTokenIDs: %w", err)
+	}
+
+	stakes, err := provider.getStakes(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("getStakes: %w", err)
+	}
+
+	owned := lo.Map(tokenIDs, func(tokenID *big.Int, _ int) holding {
+		return holding{tokenID: tokenID}
+	})
+
+	return append(owned, stakes...), nil
+}
+
+// getPosition returns nil for positions without liquidity.
+func (provider *ProviderRPC) getPosition(
+	ctx context.Context,
+	wallet string,
+	tokenID *big.Int,
+) (*domain.LiquidityPoolPosition, error) {
+	raw, err := provider.getRawPosition(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("getRawPosition: %w", err)
+	}
+
+	if raw.liquidity.Sign() == 0 {
+		return nil, nil //nolint:nilnil // Closed position is not an error.
+	}
+
+	pool, err := provider.getPoolState(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("getPoolState: %w", err)
+	}
+
+	token0, token1, err := provider.getTokens(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("getTokens: %w", err)
+	}
+
+	fees0, fees1 := raw.getUncollectedFees(pool)
+
+	return &domain.LiquidityPoolPosition{
+		ID:               tokenID.String(),
+		Wallet:           wallet,
+		Chain:            provider.config.Chain,
+		Dex:              provider.config.Dex,
+		Token0:           token0,
+		Token1:           token1,
+		CurrentTick:      pool.tick,
+		TickLower:        raw.tickLower,
+		TickUpper:        raw.tickUpper,
+		Liquidity:        raw.liquidity,
+		SqrtPriceX96:     pool.sqrtPriceX96,
+		UncollectedFees0: fees0,
+		UncollectedFees1: fees1,
+		Pool:             pool.address,
+		NFTContract:      provider.config.PositionManager,
+	}, nil
+}
+
+// getFeeGrowth reads the pool part of the position fee growth: global and outside of the range ticks.
+func (provider *ProviderRPC) getFeeGrowth(ctx context.Context, pool string, raw rawPosition) (domain.FeeGrowth, error) {
+	global0, err := provider.call(ctx, pool, ethrpc.MustEncodeCall(selectorFeeGrowthGlobal0), 1)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("feeGrowthGlobal0X128: %w", err)
+	}
+
+	global1, err := provider.call(ctx, pool, ethrpc.MustEncodeCall(selectorFeeGrowthGlobal1), 1)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("feeGrowthGlobal1X128: %w", err)
+	}
+
+	lower, err := provider.getFeeGrowthOutside(ctx, pool, raw.tickLower)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("getFeeGrowthOutside: %w", err)
+	}
+
+	upper, err := provider.getFeeGrowthOutside(ctx, pool, raw.tickUpper)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("getFeeGrowthOutside: %w", err)
+	}
+
+	return domain.FeeGrowth{
+		Global0X128:       global0.Uint(0),
+		Global1X128:       global1.Uint(0),
+		OutsideLower0X128: lower.Uint(0),
+		OutsideLower1X128: lower.Uint(1),
+		OutsideUpper0X128: upper.Uint(0),
+		OutsideUpper1X128: upper.Uint(1),
+	}, nil
+}
+
+// getFeeGrowthOutside returns feeGrowthOutside0X128 and feeGrowthOutside1X128 words of the tick.
+func (provider *ProviderRPC) getFeeGrowthOutside(ctx context.Context, pool string, tick int) (ethrpc.Words, error) {
+	ticks, err := provider.call(ctx, pool, ethrpc.MustEncodeCall(selectorTicks, ethrpc.EncodeInt(int64(tick))), ticksWords)
+	if err != nil {
+		return nil, fmt.Errorf("ticks: %w", err)
+	}
+
+	first := ticksFeeGrowthOutside0Word
+	if provider.config.FactoryKind == FactorySlipstream {
+		first += ticksSlipstreamShift
+	}
+
+	return ticks[first : first+2], nil
+}
+
+func (provider *ProviderRPC) getPoolAddress(ctx context.Context, raw rawPosition) (string, error) {
+	token0, err := ethrpc.EncodeAddress(raw.token0)
+	if err != nil {
+		return "", fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	token1, err := ethrpc.EncodeAddress(raw.token1)
+	if err != nil {
+		return "", fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	data := ethrpc.MustEncodeCall(string(provider.config.FactoryKind), token0, token1, ethrpc.EncodeInt(raw.poolKey))
+
+	pool, err := provider.call(ctx, provider.config.Factory, data, 1)
+	if err != nil {
+		return "", fmt.Errorf("getPool: %w", err)
+	}
+
+	return pool.Address(0), nil
+}
+
+// getPoolState reads the pool price and fee growth of the position range.
+func (provider *ProviderRPC) getPoolState(ctx context.Context, raw rawPosition) (poolState, error) {
+	pool, err := provider.getPoolAddress(ctx, raw)
+	if err != nil {
+		return poolState{}, fmt.Errorf("getPoolAddress: %w", err)
+	}
+
+	slot0, err := provider.call(ctx, pool, ethrpc.MustEncodeCall(selectorSlot0), slot0Words)
+	if err != nil {
+		return poolState{}, fmt.Errorf("slot0: %w", err)
+	}
+
+	feeGrowth, err := provider.getFeeGrowth(ctx, pool, raw)
+	if err != nil {
+		return poolState{}, fmt.Errorf("getFeeGrowth: %w", err)
+	}
+
+	return poolState{
+		address:      pool,
+		sqrtPriceX96: slot0.Uint(slot0SqrtPriceWord),
+		tick:         int(slot0.Int(slot0TickWord).Int64()),
+		feeGrowth:    feeGrowth,
+	}, nil
+}
+
+func (provider *ProviderRPC) getRawPosition(ctx context.Context, tokenID *big.Int) (rawPosition, error) {
+	data := ethrpc.MustEncodeCall(selectorPositions, ethrpc.EncodeUint(tokenID))
+
+	words, err := provider.call(ctx, provider.config.PositionManager, data, positionWords)
+	if err != nil {
+		return rawPosition{}, fmt.Errorf("positions: %w", err)
+	}
+
+	return rawPosition{
+		token0:    words.Address(positionToken0Word),
+		token1:    words.Address(positionToken1Word),
+		poolKey:   words.Int(positionPoolKeyWord).Int64(),
+		tickLower: int(words.Int(positionTickLowerWord).Int64()),
+		tickUpper: int(words.Int(positionTickUpperWord).Int64()),
+		liquidity: words.Uint(positionLiquidityWord),
+
+		feeGrowthInside0Last: words.Uint(positionFeeGrowthInside0LastWord),
+		feeGrowthInside1Last: words.Uint(positionFeeGrowthInside1LastWord),
+		tokensOwed0:          words.Uint(positionTokensOwed0Word),
+		tokensOwed1:          words.Uint(positionTokensOwed1Word),
+	}, nil
+}
+
+func (provider *ProviderRPC) getToken(ctx context.Context, address string) (domain.Token, error) {
+	provider.tokensMu.Lock()
+	token, ok := provider.tokens[address]
+	provider.tokensMu.Unlock()
+
+	if ok {
+		return token, nil
+	}
+
+	symbol, err := provider.client.Call(ctx, address, ethrpc.MustEncodeCall(selectorSymbol))
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("symbol: %w", err)
+	}
+
+	name, err := ethrpc.DecodeString(symbol)
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("ethrpc.DecodeString: %w", err)
+	}
+
+	decimals, err := provider.call(ctx, address, ethrpc.MustEncodeCall(selectorDecimals), 1)
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("decimals: %w", err)
+	}
+
+	token = domain.Token{
+		Name:     name,
+		Decimals: int(decimals.Uint(0).Int64()),
+		Address:  address,
+	}
+
+	provider.tokensMu.Lock()
+	provider.tokens[address] = token
+	provider.tokensMu.Unlock()
+
+	return token, nil
+}
+
+func (provider *ProviderRPC) getTokens(ctx context.Context, raw rawPosition) (token0, token1 domain.Token, err error) {
+	token0, err = provider.getToken(ctx, raw.token0)
+	if err != nil {
+		return domain.Token{}, domain.Token{}, fmt.Errorf("getToken: %w", err)
+	}
+
+	token1, err = provider.getToken(ctx, raw.token1)
+	if err != nil {
+		return domain.Token{}, domain.Token{}, fmt.Errorf("getToken: %w", err)
+	}
+
+	return token0, token1, nil
+}
+
+func (provider *ProviderRPC) getTokenIDs(ctx context.Context, wallet string) ([]*big.Int, error) {
+	owner, err := ethrpc.EncodeAddress(wallet)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	balance, err := provider.call(ctx, provider.config.PositionManager, ethrpc.MustEncodeCall(selectorBalanceOf, owner), 1)
+	if err != nil {
+		return nil, fmt.Errorf("balanceOf: %w", err)
+	}
+
+	indexes := lo.Range(int(balance.Uint(0).Int64()))
+
+	return iter.MapErr(indexes, func(index *int) (*big.Int, error) {
+		data := ethrpc.MustEncodeCall(selectorTokenOfOwnerByIndex, owner, ethrpc.EncodeUint(big.NewInt(int64(*index))))
+
+		tokenID, err := provider.call(ctx, provider.config.PositionManager, data, 1)
+		if err != nil {
+			return nil, fmt.Errorf("tokenOfOwnerByIndex: %w", err)
+		}
+
+		return tokenID.Uint(0), nil
+	})
+}
+
+type rawPosition struct {
+	token0    string
+	token1    string
+	poolKey   int64
+	tickLower int
+	tickUpper int
+	liquidity *big.Int
+
+	feeGrowthInside0Last *big.Int
+	feeGrowthInside1Last *big.Int
+	tokensOwed0          *big.Int
+	tokensOwed1          *big.Int
+}
+
+func (raw rawPosition) getUncollectedFees(pool poolState) (fees0, fees1 *big.Int) {
+	growth := pool.feeGrowth
+	growth.InsideLast0X128 = raw.feeGrowthInside0Last
+	growth.InsideLast1X128 = raw.feeGrowthInside1Last
+	growth.TokensOwed0 = raw.tokensOwed0
+	growth.TokensOwed1 = raw.tokensOwed1
+
+	return domain.GetUncollectedFees(pool.tick, raw.tickLower, raw.tickUpper, raw.liquidity, growth)
+}
+
+type poolState struct {
+	address      string
+	sqrtPriceX96 *big.Int
+	tick         int
+	feeGrowth    domain.FeeGrowth
+}
@@ -0,0 +1,363 @@
+package onchain_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/onchain"
+	domainmocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+const (
+	wallet          = "0x1111111111111111111111111111111111111111"
+	positionManager = "0x2222222222222222222222222222222222222222"
+	factory         = "0x3333333333333333333333333333333333333333"
+	pool            = "0x4444444444444444444444444444444444444444"
+	token0          = "0x5555555555555555555555555555555555555555"
+	token1          = "0x6666666666666666666666666666666666666666"
+	voter           = "0x7777777777777777777777777777777777777777"
+	gauge           = "0x8888888888888888888888888888888888888888"
+	rewardToken     = "0x9999999999999999999999999999999999999999"
+	notGauge        = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+)
+
+type providerSuite struct {
+	suite.Suite
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(providerSuite))
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_OpenAndClosed_OnlyOpen() {
+	node := newNodeStub()
+	node.expect(positionManager, call("70a08231", address(wallet)), uint64Word(2))
+	node.expect(positionManager, call("2f745c59", address(wallet), uint64Word(0)), uint64Word(7))
+	node.expect(positionManager, call("2f745c59", address(wallet), uint64Word(1)), uint64Word(8))
+	node.expect(positionManager, call("99fbab88", uint64Word(7)), positionWords(-10, 10, 100))
+	node.expect(positionManager, call("99fbab88", uint64Word(8)), positionWords(-10, 10, 0))
+	node.expect(factory, call("1698ee82", address(token0), address(token1), ethrpc.EncodeInt(500)), address(pool))
+	node.expect(pool, call("3850c7bd"), concat(uint64Word(1), ethrpc.EncodeInt(-5)))
+	node.expect(pool, call("f3058399"), q128Word(3))
+	node.expect(pool, call("46141319"), uint64Word(0))
+	node.expect(pool, call("f30dba93", ethrpc.EncodeInt(-10)), tickWords(q128Word(1), uint64Word(0)))
+	node.expect(pool, call("f30dba93", ethrpc.EncodeInt(10)), tickWords(q128Word(1), uint64Word(0)))
+	node.expect(token0, call("95d89b41"), stringResult("WETH"))
+	node.expect(token0, call("313ce567"), uint64Word(18))
+	node.expect(token1, call("95d89b41"), leftAligned("USDC"))
+	node.expect(token1, call("313ce567"), uint64Word(6))
+
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	provider := onchain.NewProviderRPC(ethrpc.NewClient(server.URL, server.Client()), onchain.ProviderRPCConfig{
+		Name:            "Test",
+		Chain:           domain.ChainBase,
+		Dex:             domain.DexUniswapV3,
+		PositionManager: positionManager,
+		Factory:         factory,
+		FactoryKind:     onchain.FactoryUniswapV3,
+	})
+
+	positions, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+	s.Require().NoError(err)
+
+	s.Require().Equal([]domain.LiquidityPoolPosition{
+		{
+			ID:           "7",
+			Wallet:       wallet,
+			Chain:        domain.ChainBase,
+			Dex:          domain.DexUniswapV3,
+			Token0:       domain.Token{Name: "WETH", Decimals: 18, Address: token0},
+			Token1:       domain.Token{Name: "USDC", Decimals: 6, Address: token1},
+			CurrentTick:  -5,
+			TickLower:    -10,
+			TickUpper:    10,
+			Liquidity:    big.NewInt(100),
+			SqrtPriceX96: big.NewInt(1),
+			// Fee growth inside is 1 per liquidity plus tokens owed.
+			UncollectedFees0: big.NewInt(105),
+			UncollectedFees1: big.NewInt(0),
+			Pool:             pool,
+			NFTContract:      positionManager,
+		},
+	}, positions)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_StakedToGauge_Emissions() {
+	node := newNodeStub()
+	node.blockNumber = 25
+	node.logs = []ethrpc.Log{{Address: gauge}, {Address: notGauge}}
+	node.expect(positionManager, call("70a08231", address(wallet)), uint64Word(0))
+	node.expect(voter, call("aa79979b", address(gauge)), uint64Word(1))
+	node.expect(voter, call("aa79979b", address(notGauge)), uint64Word(0))
+	stakedValues := concat(uint64Word(ethrpc.WordSize), uint64Word(1), uint64Word(7))
+	node.expect(gauge, call("4b937763", address(wallet)), stakedValues)
+	node.expect(gauge, call("3e491d47", address(wallet), uint64Word(7)), uint64Word(2_500_000_000_000_000_000))
+	node.expect(gauge, call("f7c618c1"), address(rewardToken))
+	node.expect(rewardToken, call("95d89b41"), stringResult("AERO"))
+	node.expect(rewardToken, call("313ce567"), uint64Word(18))
+	node.expect(positionManager, call("99fbab88", uint64Word(7)), positionWords(-10, 10, 100))
+	node.expect(factory, call("1698ee82", address(token0), address(token1), ethrpc.EncodeInt(500)), address(pool))
+	node.expect(pool, call("3850c7bd"), concat(uint64Word(1), ethrpc.EncodeInt(-5)))
+	node.expect(pool, call("f3058399"), q128Word(3))
+	node.expect(pool, call("46141319"), uint64Word(0))
+	node.expect(pool, call("f30dba93", ethrpc.EncodeInt(-10)), tickWords(q128Word(1), uint64Word(0)))
+	node.expect(pool, call("f30dba93", ethrpc.EncodeInt(10)), tickWords(q128Word(1), uint64Word(0)))
+	node.expect(token0, call("95d89b41"), stringResult("WETH"))
+	node.expect(token0, call("313ce567"), uint64Word(18))
+	node.expect(token1, call("95d89b41"), leftAligned("USDC"))
+	node.expect(token1, call("313ce567"), uint64Word(6))
+
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	provider := onchain.NewProviderRPC(ethrpc.NewClient(server.URL, server.Client()), onchain.ProviderRPCConfig{
+		PositionManager: positionManager,
+		Factory:         factory,
+		FactoryKind:     onchain.FactoryUniswapV3,
+		Gauges:          &onchain.GaugesConfig{Voter: voter, FromBlock: 10, BlockRange: 10},
+	})
+
+	positions, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+	s.Require().NoError(err)
+
+	s.Require().Len(positions, 1)
+	s.Require().True(positions[0].Staked)
+	s.Require().Equal(&domain.PositionEmissions{
+		Token:   domain.Token{Name: "AERO", Decimals: 18, Address: rewardToken},
+		Pending: big.NewInt(2_500_000_000_000_000_000),
+	}, positions[0].Emissions)
+	// Fees of staked liquidity go to voters.
+	s.Require().Zero(positions[0].UncollectedFees0.Sign())
+	// Blocks 10-25 are scanned by two requests.
+	s.Require().Equal(2, node.logsRequests)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_MaxScanBlocks_BackfillSpreadOverPolls() {
+	node := newNodeStub()
+	node.blockNumber = 45
+	node.logs = []ethrpc.Log{}
+	node.expect(positionManager, call("70a08231", address(wallet)), uint64Word(0))
+
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	provider := onchain.NewProviderRPC(ethrpc.NewClient(server.URL, server.Client()), onchain.ProviderRPCConfig{
+		PositionManager: positionManager,
+		Factory:         factory,
+		FactoryKind:     onchain.FactoryUniswapV3,
+		Gauges:          &onchain.GaugesConfig{Voter: voter, FromBlock: 10, BlockRange: 10, MaxScanBlocks: 20},
+	})
+
+	// Blocks 10-29 are scanned by the first poll, blocks 30-45 by the second one.
+	for _, logsRequests := range []int{2, 4, 4} {
+		_, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+		s.Require().NoError(err)
+		s.Require().Equal(logsRequests, node.logsRequests)
+	}
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_SavedScan_Resumed() {
+	node := newNodeStub()
+	node.blockNumber = 45
+	node.logs = []ethrpc.Log{}
+	node.expect(positionManager, call("70a08231", address(wallet)), uint64Word(0))
+	node.expect(gauge, call("4b937763", address(wallet)), concat(uint64Word(ethrpc.WordSize), uint64Word(0)))
+
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	saved := domain.GaugesScan{Chain: domain.ChainBase, Wallet: wallet, NextBlock: 40, Gauges: []string{gauge}}
+	resumed := saved
+	resumed.NextBlock = 46
+
+	scans := domainmocks.NewGaugesScansRepository(s.T())
+	scans.EXPECT().
+		Get(mock.Anything, domain.ChainBase, wallet).
+		Return(saved, nil).
+		Once()
+	scans.EXPECT().
+		Add(mock.Anything, resumed).
+		Return(nil).
+		Once()
+
+	provider := onchain.NewProviderRPC(ethrpc.NewClient(server.URL, server.Client()), onchain.ProviderRPCConfig{
+		Chain:           domain.ChainBase,
+		PositionManager: positionManager,
+		Factory:         factory,
+		FactoryKind:     onchain.FactoryUniswapV3,
+		Gauges:          &onchain.GaugesConfig{Voter: voter, FromBlock: 10, BlockRange: 10, Scans: scans},
+	})
+
+	_, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+	s.Require().NoError(err)
+
+	// Only blocks 40-45 are scanned, the saved gauge is asked for stakes without its deposit in them.
+	s.Require().Equal(1, node.logsRequests)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_CallReverted_Error() {
+	server := httptest.NewServer(newNodeStub())
+	defer server.Close()
+
+	provider := onchain.NewProviderRPC(ethrpc.NewClient(server.URL, server.Client()), onchain.ProviderRPCConfig{
+		PositionManager: positionManager,
+		Factory:         factory,
+		FactoryKind:     onchain.FactoryUniswapV3,
+	})
+
+	_, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+
+	var rpcErr *ethrpc.Error
+	s.Require().ErrorAs(err, &rpcErr)
+}
+
+// nodeStub answers eth_call with canned results, unknown calls are reverted. Every eth_getLogs request
+// returns the same logs.
+type nodeStub struct {
+	results      map[string]string
+	blockNumber  uint64
+	logs         []ethrpc.Log
+	logsRequests int
+}
+
+func newNodeStub() *nodeStub {
+	return &nodeStub{
+		results: make(map[string]string),
+	}
+}
+
+func (stub *nodeStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var rpcRequest struct {
+		ID     int64  `json:"id"`
+		Method string `json:"method"`
+		Params []jsoniter.RawMessage
+	}
+
+	err := jsoniter.NewDecoder(request.Body).Decode(&rpcRequest)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]any{"jsonrpc": "2.0", "id": rpcRequest.ID}
+
+	switch rpcRequest.Method {
+	case "eth_blockNumber":
+		response["result"] = "0x" + strconv.FormatUint(stub.blockNumber, 16)
+	case "eth_getLogs":
+		stub.logsRequests++
+		response["result"] = stub.logs
+	default:
+		stub.answerCall(rpcRequest.Params[0], response)
+	}
+
+	_ = jsoniter.NewEncoder(writer).Encode(response)
+}
+
+func (stub *nodeStub) answerCall(rawParams jsoniter.RawMessage, response map[string]any) {
+	var params struct {
+		To   string `json:"to"`
+		Data string `json:"data"`
+	}
+
+	_ = jsoniter.Unmarshal(rawParams, &params)
+
+	result, ok := stub.results[key(params.To, params.Data)]
+	if ok {
+		response["result"] = result
+	} else {
+		response["error"] = map[string]any{"code": 3, "message": "execution reverted"}
+	}
+}
+
+func (stub *nodeStub) expect(to string, data, result []byte) {
+	stub.results[key(to, "0x"+hex.EncodeToString(data))] = "0x" + hex.EncodeToString(result)
+}
+
+func key(to, data string) string {
+	return strings.ToLower(to) + strings.ToLower(data)
+}
+
+func call(selector string, args ...[]byte) []byte {
+	return ethrpc.MustEncodeCall(selector, args...)
+}
+
+func address(value string) []byte {
+	encoded, err := ethrpc.EncodeAddress(value)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
+func uint64Word(value uint64) []byte {
+	return ethrpc.EncodeUint(new(big.Int).SetUint64(value))
+}
+
+func positionWords(tickLower, tickUpper int64, liquidity uint64) []byte {
+	return concat(
+		uint64Word(0),
+		address(wallet),
+		address(token0),
+		address(token1),
+		uint64Word(500),
+		ethrpc.EncodeInt(tickLower),
+		ethrpc.EncodeInt(tickUpper),
+		uint64Word(liquidity),
+		uint64Word(0),
+		uint64Word(0),
+		uint64Word(5),
+		uint64Word(0),
+	)
+}
+
+// tickWords makes ticks(int24) result of Uniswap V3 pool.
+func tickWords(feeGrowthOutside0, feeGrowthOutside1 []byte) []byte {
+	return concat(
+		uint64Word(0),
+		uint64Word(0),
+		feeGrowthOutside0,
+		feeGrowthOutside1,
+		uint64Word(0),
+		uint64Word(0),
+		uint64Word(0),
+		uint64Word(1),
+	)
+}
+
+func q128Word(value int64) []byte {
+	return ethrpc.EncodeUint(new(big.Int).Lsh(big.NewInt(value), 128))
+}
+
+func stringResult(value string) []byte {
+	return concat(uint64Word(ethrpc.WordSize), uint64Word(uint64(len(value))), leftAligned(value))
+}
+
+func leftAligned(value string) []byte {
+	word := make([]byte, ethrpc.WordSize)
+	copy(word, value)
+	return word
+}
+
+func concat(words ...[]byte) []byte {
+	var data []byte
+	for _, word := range words {
+		data = append(data, word...)
+	}
+	return data
+}
@@ -0,0 +1,325 @@
+package positions_providers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hasura/go-graphql-client"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/onchain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/uniswap_v3"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/uniswap_v3_forks"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/uniswap_v4"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+var ErrUnsupportedChain = errors.New("unsupported chain")
+
+const (
+	// Uniswap V3 is deployed at the same addresses on most chains.
+	uniswapV3PositionManager = "0xC36442b4a4522E871399CD717aBDD847Ab11FE88"
+	uniswapV3Factory         = "0x1F98431c8aD98523631AE4a59f267346ea31F984"
+
+	baseUniswapV3PositionManager = "0x03a520b32C04BF3bEEf7BEb72E919cf822Ed34f1"
+	baseUniswapV3Factory         = "0x33128a8fC17869897dcE68Ed026d694621f6FDfD"
+
+	// PancakeSwap V3 is deployed at the same address on all supported chains.
+	pancakeSwapV3PositionManager = "0x46A15B0b27311cedF172AB29E4f4766fbE7F4364"
+
+	theGraphURL = "https://gateway.thegraph.com/api/subgraphs/id/"
+)
+
+// ChainSource is where positions of the chain are read from. Uniswap V3 positions are read from the RPC node
+// in preference to the subgraph, the forks are read from their subgraphs only. Uniswap V4 needs both the RPC node
+// and the subgraph listing positions of the owner. Not set sources are skipped.
+type ChainSource struct {
+	Chain                domain.Chain
+	RPCURL               string
+	UniswapV3GraphID     string
+	UniswapV4GraphID     string
+	PancakeSwapV3GraphID string
+	SushiSwapV3GraphID   string
+}
+
+type RegistryConfig struct {
+	Sources []ChainSource
+	// TheGraphToken is required for the subgraph sources, they are skipped without it.
+	TheGraphToken string
+	HTTPClient    *http.Client
+}
+
+// NewProviders makes providers of all supported DEXes on the chains.
+func NewProviders(config RegistryConfig) ([]domain.LiquidityPoolPositionsProvider, error) {
+	var providers []domain.LiquidityPoolPositionsProvider
+
+	dexes := []func(RegistryConfig) ([]domain.LiquidityPoolPositionsProvider, error){
+		NewUniswapV3Providers,
+		NewUniswapV4Providers,
+		NewPancakeSwapV3Providers,
+		NewSushiSwapV3Providers,
+	}
+
+	for _, newDexProviders := range dexes {
+		dexProviders, err := newDexProviders(config)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, dexProviders...)
+	}
+
+	return providers, nil
+}
+
+// NewPancakeSwapV3Providers makes PancakeSwap V3 providers of the chains with the subgraph,
+// ErrUnsupportedChain if PancakeSwap V3 is not known on the chain.
+func NewPancakeSwapV3Providers(config RegistryConfig) ([]domain.LiquidityPoolPositionsProvider, error) {
+	return makeProviders(config, newPancakeSwapV3Provider)
+}
+
+// NewSushiSwapV3Providers makes SushiSwap V3 providers of the chains with the subgraph,
+// ErrUnsupportedChain if SushiSwap V3 is not known on the chain.
+func NewSushiSwapV3Providers(config RegistryConfig) ([]domain.LiquidityPoolPositionsProvider, error) {
+	return makeProviders(config, newSushiSwapV3Provider)
+}
+
+// NewUniswapV3Providers makes Uniswap V3 providers of the chains, ErrUnsupportedChain if Uniswap V3
+// is not known on the chain.
+func NewUniswapV3Providers(config RegistryConfig) ([]domain.LiquidityPoolPositionsProvider, error) {
+	return makeProviders(config, newUniswapV3Provider)
+}
+
+// NewUniswapV4Providers makes Uniswap V4 providers of the chains, ErrUnsupportedChain if Uniswap V4
+// is not known on the chain.
+func NewUniswapV4Providers(config RegistryConfig) ([]domain.LiquidityPoolPositionsProvider, error) {
+	return makeProviders(config, newUniswapV4Provider)
+}
+
+// NewTheGraphClient makes the client of the subgraph on The Graph decentralized network.
+func NewTheGraphClient(httpClient *http.Client, token, graphID string) *graphql.Client {
+	setAuth := func(r *http.Request) {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return graphql.NewClient(theGraphURL+graphID, httpClient).WithRequestModifier(setAuth)
+}
+
+type providerFactory func(RegistryConfig, ChainSource) (domain.LiquidityPoolPositionsProvider, error)
+
+// makeProviders makes providers of the sources, not configured sources are skipped.
+func makeProviders(
+	config RegistryConfig,
+	newProvider providerFactory,
+) ([]domain.LiquidityPoolPositionsProvider, error) {
+	var providers []domain.LiquidityPoolPositionsProvider
+
+	for _, source := range config.Sources {
+		provider, err := newProvider(config, source)
+		if err != nil {
+			return nil, err
+		}
+		if provider != nil {
+			providers = append(providers, provider)
+		}
+	}
+
+	return providers, nil
+}
+
+// getPancakeSwapV3PositionManagers returns the NFT contracts of PancakeSwap V3 on the chains.
+func getPancakeSwapV3PositionManagers() map[domain.Chain]string {
+	return map[domain.Chain]string{
+		domain.ChainBase:     pancakeSwapV3PositionManager,
+		domain.ChainEthereum: pancakeSwapV3PositionManager,
+		domain.ChainArbitrum: pancakeSwapV3PositionManager,
+	}
+}
+
+// getSushiSwapV3PositionManagers returns the NFT contracts of SushiSwap V3 on the chains.
+func getSushiSwapV3PositionManagers() map[domain.Chain]string {
+	return map[domain.Chain]string{
+		domain.ChainBase:     "0x80C7DD17B01855a6D2347444a0FCC36136a314de",
+		domain.ChainEthereum: "0x2214A42d8e2A1d20635c2cb0664422c528B6A432",
+		domain.ChainArbitrum: "0xF0cBce1942A68BEB3d1b73F0dd86C8DCc363eF49",
+		domain.ChainOptimism: "0x1af415a1EbA07a4986a52B6f2e7dE7003D82231e",
+		domain.ChainPolygon:  "0xb7402ee99F0A008e461098AC3A27F4957Df89a40",
+	}
+}
+
+type uniswapV3Deployment struct {
+	positionManager string
+	factory         string
+}
+
+func getUniswapV3Deployments() map[domain.Chain]uniswapV3Deployment {
+	return map[domain.Chain]uniswapV3Deployment{
+		domain.ChainBase: {
+			positionManager: baseUniswapV3PositionManager,
+			factory:         baseUniswapV3Factory,
+		},
+		domain.ChainEthereum: {
+			positionManager: uniswapV3PositionManager,
+			factory:         uniswapV3Factory,
+		},
+		domain.ChainArbitrum: {
+			positionManager: uniswapV3PositionManager,
+			factory:         uniswapV3Factory,
+		},
+		domain.ChainOptimism: {
+			positionManager: uniswapV3PositionManager,
+			factory:         uniswapV3Factory,
+		},
+		domain.ChainPolygon: {
+			positionManager: uniswapV3PositionManager,
+			factory:         uniswapV3Factory,
+		},
+	}
+}
+
+type uniswapV4Deployment struct {
+	positionManager string
+	stateView       string
+	nativeSymbol    string
+}
+
+func getUniswapV4Deployments() map[domain.Chain]uniswapV4Deployment {
+	return map[domain.Chain]uniswapV4Deployment{
+		domain.ChainBase: {
+			positionManager: "0x7C5f5A4bBd8fD63184577525326123B519429bDc",
+			stateView:       "0xA3c0c9b65baD0b08107Aa264b0f3dB444b867A71",
+			nativeSymbol:    "ETH",
+		},
+		domain.ChainEthereum: {
+			positionManager: "0xbD216513d74C8cf14cf4747E6AaA6420FF64ee9e",
+			stateView:       "0x7fFE42C4a5DEeA5b0feC41C94C136Cf115597227",
+			nativeSymbol:    "ETH",
+		},
+		domain.ChainArbitrum: {
+			positionManager: "0xd88F38F930b7952f2DB2432Cb002E7abbF3dD869",
+			stateView:       "0x76Fd297e2D437cd7f76d50F01AfE6160f86e9990",
+			nativeSymbol:    "ETH",
+		},
+		domain.ChainOptimism: {
+			positionManager: "0x3C3Ea4B57a46241e54610e5f022E5c45859A1017",
+			stateView:       "0xc18a3169788F4F75A170290584ECA6395C75Ecdb",
+			nativeSymbol:    "ETH",
+		},
+		domain.ChainPolygon: {
+			positionManager: "0x1Ec2eBf4F37E7363FDfe3551602425af0B3ceef9",
+			stateView:       "0x5eA1bD7974c8A611cBAB0bDCAFcB1D9CC9b3BA5a",
+			nativeSymbol:    "POL",
+		},
+	}
+}
+
+// newPancakeSwapV3Provider returns nil if the chain subgraph is not configured.
+//
+//nolint:ireturn // Same signature as other DEXes.
+func newPancakeSwapV3Provider(
+	config RegistryConfig,
+	source ChainSource,
+) (domain.LiquidityPoolPositionsProvider, error) {
+	return newUniswapV3ForkProvider(
+		config, source, domain.DexPancakeSwapV3, source.PancakeSwapV3GraphID, getPancakeSwapV3PositionManagers(),
+	)
+}
+
+// newSushiSwapV3Provider returns nil if the chain subgraph is not configured.
+//
+//nolint:ireturn // Same signature as other DEXes.
+func newSushiSwapV3Provider(
+	config RegistryConfig,
+	source ChainSource,
+) (domain.LiquidityPoolPositionsProvider, error) {
+	return newUniswapV3ForkProvider(
+		config, source, domain.DexSushiSwapV3, source.SushiSwapV3GraphID, getSushiSwapV3PositionManagers(),
+	)
+}
+
+// newUniswapV3ForkProvider returns nil if the chain subgraph of the fork is not configured.
+//
+//nolint:ireturn // Same signature as other DEXes.
+func newUniswapV3ForkProvider(
+	config RegistryConfig,
+	source ChainSource,
+	dex domain.Dex,
+	graphID string,
+	positionManagers map[domain.Chain]string,
+) (domain.LiquidityPoolPositionsProvider, error) {
+	if graphID == "" || config.TheGraphToken == "" {
+		return nil, nil //nolint:nilnil // Not configured chain is skipped.
+	}
+
+	positionManager, ok := positionManagers[source.Chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s on %s", ErrUnsupportedChain, dex, source.Chain)
+	}
+
+	client := NewTheGraphClient(config.HTTPClient, config.TheGraphToken, graphID)
+
+	return uniswap_v3_forks.NewProviderTheGraph(client, uniswap_v3_forks.ProviderTheGraphConfig{
+		Chain:           source.Chain,
+		Dex:             dex,
+		PositionManager: positionManager,
+	}), nil
+}
+
+// newUniswapV3Provider returns nil if the chain source is not configured.
+//
+//nolint:ireturn // Either RPC or subgraph provider.
+func newUniswapV3Provider(
+	config RegistryConfig,
+	source ChainSource,
+) (domain.LiquidityPoolPositionsProvider, error) {
+	deployment, ok := getUniswapV3Deployments()[source.Chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedChain, source.Chain)
+	}
+
+	switch {
+	case source.RPCURL != "":
+		return onchain.NewProviderRPC(ethrpc.NewClient(source.RPCURL, config.HTTPClient), onchain.ProviderRPCConfig{
+			Name:            string(source.Chain) + " Uniswap V3 RPC",
+			Chain:           source.Chain,
+			Dex:             domain.DexUniswapV3,
+			PositionManager: deployment.positionManager,
+			Factory:         deployment.factory,
+			FactoryKind:     onchain.FactoryUniswapV3,
+		}), nil
+	case source.UniswapV3GraphID != "" && config.TheGraphToken != "":
+		client := NewTheGraphClient(config.HTTPClient, config.TheGraphToken, source.UniswapV3GraphID)
+		return uniswap_v3.NewProviderTheGraph(client, uniswap_v3.ProviderTheGraphConfig{
+			Chain:           source.Chain,
+			PositionManager: deployment.positionManager,
+		}), nil
+	default:
+		return nil, nil //nolint:nilnil // Not configured chain is skipped.
+	}
+}
+
+// newUniswapV4Provider returns nil if either the RPC node or the subgraph of the chain is not configured.
+//
+//nolint:ireturn // Same signature as other DEXes.
+func newUniswapV4Provider(
+	config RegistryConfig,
+	source ChainSource,
+) (domain.LiquidityPoolPositionsProvider, error) {
+	if source.RPCURL == "" || source.UniswapV4GraphID == "" || config.TheGraphToken == "" {
+		return nil, nil //nolint:nilnil // Not configured chain is skipped.
+	}
+
+	deployment, ok := getUniswapV4Deployments()[source.Chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: Uniswap V4 on %s", ErrUnsupportedChain, source.Chain)
+	}
+
+	graph := NewTheGraphClient(config.HTTPClient, config.TheGraphToken, source.UniswapV4GraphID)
+
+	return uniswap_v4.NewProvider(graph, ethrpc.NewClient(source.RPCURL, config.HTTPClient), uniswap_v4.ProviderConfig{
+		Chain:           source.Chain,
+		PositionManager: deployment.positionManager,
+		StateView:       deployment.stateView,
+		NativeSymbol:    deployment.nativeSymbol,
+	}), nil
+}
@@ -0,0 +1,97 @@
+package positions_providers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers"
+)
+
+type registrySuite struct {
+	suite.Suite
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(registrySuite))
+}
+
+func (s *registrySuite) TestNewUniswapV3Providers_UnsupportedChain_Error() {
+	_, err := positions_providers.NewUniswapV3Providers(positions_providers.RegistryConfig{
+		Sources:    []positions_providers.ChainSource{{Chain: "Unknown", RPCURL: "http://localhost"}},
+		HTTPClient: http.DefaultClient,
+	})
+	s.Require().ErrorIs(err, positions_providers.ErrUnsupportedChain)
+}
+
+func (s *registrySuite) TestNewUniswapV3Providers_Sources_RPCPreferred() {
+	providers, err := positions_providers.NewUniswapV3Providers(positions_providers.RegistryConfig{
+		Sources: []positions_providers.ChainSource{
+			{Chain: domain.ChainEthereum, RPCURL: "http://localhost", UniswapV3GraphID: "ethereum"},
+			{Chain: domain.ChainArbitrum, UniswapV3GraphID: "arbitrum"},
+			{Chain: domain.ChainPolygon},
+		},
+		TheGraphToken: "token",
+		HTTPClient:    http.DefaultClient,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(providers, 2)
+	s.Require().Equal("Ethereum Uniswap V3 RPC", providers[0].GetName())
+	s.Require().Equal("Arbitrum Uniswap V3", providers[1].GetName())
+}
+
+func (s *registrySuite) TestNewUniswapV3Providers_NoGraphToken_SubgraphSkipped() {
+	providers, err := positions_providers.NewUniswapV3Providers(positions_providers.RegistryConfig{
+		Sources:    []positions_providers.ChainSource{{Chain: domain.ChainOptimism, UniswapV3GraphID: "optimism"}},
+		HTTPClient: http.DefaultClient,
+	})
+	s.Require().NoError(err)
+	s.Require().Empty(providers)
+}
+
+func (s *registrySuite) TestNewProviders_ForkSubgraphs_AllDexes() {
+	providers, err := positions_providers.NewProviders(positions_providers.RegistryConfig{
+		Sources: []positions_providers.ChainSource{{
+			Chain:                domain.ChainArbitrum,
+			UniswapV3GraphID:     "uniswap",
+			PancakeSwapV3GraphID: "pancakeswap",
+			SushiSwapV3GraphID:   "sushiswap",
+		}},
+		TheGraphToken: "token",
+		HTTPClient:    http.DefaultClient,
+	})
+	s.Require().NoError(err)
+
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, provider.GetName())
+	}
+	s.Require().Equal([]string{"Arbitrum Uniswap V3", "Arbitrum PancakeSwap V3", "Arbitrum SushiSwap V3"}, names)
+}
+
+func (s *registrySuite) TestNewProviders_PancakeSwapOnOptimism_Error() {
+	_, err := positions_providers.NewProviders(positions_providers.RegistryConfig{
+		Sources:       []positions_providers.ChainSource{{Chain: domain.ChainOptimism, PancakeSwapV3GraphID: "pancakeswap"}},
+		TheGraphToken: "token",
+		HTTPClient:    http.DefaultClient,
+	})
+	s.Require().ErrorIs(err, positions_providers.ErrUnsupportedChain)
+}
+
+func (s *registrySuite) TestNewUniswapV4Providers_RPCAndSubgraph_Provider() {
+	providers, err := positions_providers.NewUniswapV4Providers(positions_providers.RegistryConfig{
+		Sources: []positions_providers.ChainSource{
+			{Chain: domain.ChainBase, RPCURL: "http://localhost", UniswapV4GraphID: "base"},
+			{Chain: domain.ChainEthereum, RPCURL: "http://localhost"},
+			{Chain: domain.ChainArbitrum, UniswapV4GraphID: "arbitrum"},
+		},
+		TheGraphToken: "token",
+		HTTPClient:    http.DefaultClient,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(providers, 1)
+	s.Require().Equal("Base Uniswap V4", providers[0].GetName())
+}
@@ -0,0 +1,40 @@
+// Package subgraph holds helpers shared by the subgraph positions providers.
+package subgraph
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// MustConvertToBigInt parses the decimal BigInt of the subgraph response, panics if the schema is violated.
+func MustConvertToBigInt(value string) *big.Int {
+	integer, ok := new(big.Int).SetString(value, 10) //nolint:mnd // Decimal.
+	if !ok {
+		message := "MustConvertToBigInt: " + value
+		panic(message)
+	}
+
+	return integer
+}
+
+// MustConvertToFloat parses the BigDecimal of the subgraph response, panics if the schema is violated.
+func MustConvertToFloat(value string) float64 {
+	float, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		message := "MustConvertToFloat: " + value
+		panic(message)
+	}
+
+	return float
+}
+
+// MustConvertToInt parses the small BigInt of the subgraph response, panics if the schema is violated.
+func MustConvertToInt(value string) int {
+	integer, err := strconv.Atoi(value)
+	if err != nil {
+		message := "MustConvertToInt: " + value
+		panic(message)
+	}
+
+	return integer
+}
@@ -0,0 +1,32 @@
+package subgraph
+
+import (
+	"github.com/hasura/go-graphql-client"
+)
+
+// PageSize is the maximum number of entities The Graph returns by a single query, 100 are returned without `first`.
+const PageSize = 1000
+
+// FetchAll pages through entities ordered by ID. The fetch gets up to PageSize entities with ID greater than
+// the last ID, the empty last ID starts from the first entity.
+func FetchAll[T any](fetch func(lastID graphql.ID) ([]T, error), getID func(T) string) ([]T, error) {
+	var (
+		all    []T
+		lastID graphql.ID
+	)
+
+	for {
+		page, err := fetch(lastID)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, page...)
+
+		if len(page) < PageSize {
+			return all, nil
+		}
+
+		lastID = graphql.ID(getID(page[len(page)-1]))
+	}
+}
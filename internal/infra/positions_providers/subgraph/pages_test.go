@@ -0,0 +1,54 @@
+package subgraph_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+)
+
+var errFetch = errors.New("fetch")
+
+type pagesSuite struct {
+	suite.Suite
+}
+
+func TestPages(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(pagesSuite))
+}
+
+func (s *pagesSuite) TestFetchAll_FullPage_NextPageFetched() {
+	var lastIDs []graphql.ID
+
+	all, err := subgraph.FetchAll(func(lastID graphql.ID) ([]int, error) {
+		lastIDs = append(lastIDs, lastID)
+		if lastID == "" {
+			return makePage(1, subgraph.PageSize), nil
+		}
+		return makePage(subgraph.PageSize+1, 1), nil
+	}, strconv.Itoa)
+	s.Require().NoError(err)
+
+	s.Require().Len(all, subgraph.PageSize+1)
+	s.Require().Equal([]graphql.ID{"", graphql.ID(strconv.Itoa(subgraph.PageSize))}, lastIDs)
+}
+
+func (s *pagesSuite) TestFetchAll_Error_Returned() {
+	_, err := subgraph.FetchAll(func(graphql.ID) ([]int, error) {
+		return nil, errFetch
+	}, strconv.Itoa)
+	s.Require().ErrorIs(err, errFetch)
+}
+
+func makePage(first, size int) []int {
+	page := make([]int, size)
+	for i := range page {
+		page[i] = first + i
+	}
+	return page
+}
@@ -0,0 +1,254 @@
+package uniswap_v3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+)
+
+// ErrTickNotFound means the subgraph does not return the range tick of the position.
+var ErrTickNotFound = errors.New("range tick not found")
+
+type ProviderTheGraphConfig struct {
+	Chain domain.Chain
+	// PositionManager is the NFT contract of the positions, the subgraph does not know it.
+	PositionManager string
+}
+
+// ProviderTheGraph reads positions from the Uniswap V3 subgraph of the chain.
+type ProviderTheGraph struct {
+	client *graphql.Client
+	config ProviderTheGraphConfig
+}
+
+func NewProviderTheGraph(client *graphql.Client, config ProviderTheGraphConfig) *ProviderTheGraph {
+	return &ProviderTheGraph{
+		client: client,
+		config: config,
+	}
+}
+
+func (provider *ProviderTheGraph) GetName() string {
+	return string(provider.config.Chain) + " Uniswap V3"
+}
+
+func (provider *ProviderTheGraph) GetPositionsWithLiquidity(
+	ctx context.Context,
+	wallet string,
+) ([]domain.LiquidityPoolPosition, error) {
+	unclosedPositions, err := provider.getUnclosedPositions(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("getUnclosedPositions: %w", err)
+	}
+
+	if len(unclosedPositions) == 0 {
+		return nil, nil
+	}
+
+	ticks, err := provider.getRangeTicks(ctx, unclosedPositions)
+	if err != nil {
+		return nil, fmt.Errorf("getRangeTicks: %w", err)
+	}
+
+	positions := make([]domain.LiquidityPoolPosition, 0, len(unclosedPositions))
+
+	for _, pos := range unclosedPositions {
+		feeGrowth, err := pos.toFeeGrowth(ticks)
+		if err != nil {
+			return nil, fmt.Errorf("position %s: %w", pos.ID, err)
+		}
+
+		positions = append(positions, provider.convertToDomain(wallet, pos, feeGrowth))
+	}
+
+	return positions, nil
+}
+
+func (provider *ProviderTheGraph) convertToDomain(
+	wallet string,
+	pos position,
+	feeGrowth domain.FeeGrowth,
+) domain.LiquidityPoolPosition {
+	currentTick := subgraph.MustConvertToInt(pos.Pool.Tick)
+	liquidity := subgraph.MustConvertToBigInt(pos.Liquidity)
+	fees0, fees1 := domain.GetUncollectedFees(currentTick, pos.TickLower, pos.TickUpper, liquidity, feeGrowth)
+
+	return domain.LiquidityPoolPosition{
+		ID:               pos.ID,
+		Wallet:           wallet,
+		Chain:            provider.config.Chain,
+		Dex:              domain.DexUniswapV3,
+		Pool:             pos.Pool.ID,
+		NFTContract:      provider.config.PositionManager,
+		TickLower:        pos.TickLower,
+		TickUpper:        pos.TickUpper,
+		CurrentTick:      currentTick,
+		Liquidity:        liquidity,
+		SqrtPriceX96:     subgraph.MustConvertToBigInt(pos.Pool.SqrtPrice),
+		UncollectedFees0: fees0,
+		UncollectedFees1: fees1,
+		Token0: domain.Token{
+			Name:     pos.Pool.Token0.Symbol,
+			Decimals: subgraph.MustConvertToInt(pos.Pool.Token0.Decimals),
+			Address:  pos.Pool.Token0.ID,
+		},
+		Token1: domain.Token{
+			Name:     pos.Pool.Token1.Symbol,
+			Decimals: subgraph.MustConvertToInt(pos.Pool.Token1.Decimals),
+			Address:  pos.Pool.Token1.ID,
+		},
+		Deposits: pos.toDeposits(),
+	}
+}
+
+// getRangeTicks fetches bound ticks of the positions ranges keyed by tick ID, a page per PageSize ticks.
+func (provider *ProviderTheGraph) getRangeTicks(ctx context.Context, positions []position) (map[string]tick, error) {
+	ids := lo.Uniq(lo.FlatMap(positions, func(pos position, _ int) []graphql.ID {
+		return []graphql.ID{makeTickID(pos.Pool.ID, pos.TickLower), makeTickID(pos.Pool.ID, pos.TickUpper)}
+	}))
+
+	ticks := make(map[string]tick, len(ids))
+
+	for _, chunk := range lo.Chunk(ids, subgraph.PageSize) {
+		var rangeTicks rangeTicksQuery
+
+		variables := map[string]any{
+			"ids":   chunk,
+			"first": subgraph.PageSize,
+		}
+
+		err := provider.client.Query(ctx, &rangeTicks, variables)
+		if err != nil {
+			return nil, fmt.Errorf("graphql.Query: %w", err)
+		}
+
+		for _, t := range rangeTicks.Ticks {
+			ticks[t.ID] = t
+		}
+	}
+
+	return ticks, nil
+}
+
+func (provider *ProviderTheGraph) getUnclosedPositions(ctx context.Context, wallet string) ([]position, error) {
+	return subgraph.FetchAll(func(lastID graphql.ID) ([]position, error) {
+		var unclosedPositions unclosedPositionsQuery
+
+		variables := map[string]any{
+			"wallet": wallet,
+			"first":  subgraph.PageSize,
+			"lastID": lastID,
+		}
+
+		err := provider.client.Query(ctx, &unclosedPositions, variables)
+		if err != nil {
+			return nil, fmt.Errorf("graphql.Query: %w", err)
+		}
+
+		return unclosedPositions.Positions, nil
+	}, func(pos position) string {
+		return pos.ID
+	})
+}
+
+// makeTickID makes the subgraph tick entity ID.
+func makeTickID(poolID string, tickIdx int) graphql.ID {
+	return graphql.ID(poolID + "#" + strconv.Itoa(tickIdx))
+}
+
+type unclosedPositionsQuery struct {
+	Positions []position `graphql:"positions(first: $first, where: {owner: $wallet, liquidity_gt: 0, id_gt: $lastID})"`
+}
+
+type rangeTicksQuery struct {
+	Ticks []tick `graphql:"ticks(first: $first, where: {id_in: $ids})"`
+}
+
+type position struct {
+	ID                       string
+	Liquidity                string
+	FeeGrowthInside0LastX128 string
+	FeeGrowthInside1LastX128 string
+	DepositedToken0          string
+	DepositedToken1          string
+	WithdrawnToken0          string
+	WithdrawnToken1          string
+	CollectedFeesToken0      string
+	CollectedFeesToken1      string
+	Transaction              transaction
+	TickLower                int
+	TickUpper                int
+	Pool                     pool
+}
+
+// toFeeGrowth collects fee accounting data, the subgraph does not expose tokensOwed, so fees
+// credited to the position on liquidity changes are not counted.
+func (pos position) toFeeGrowth(ticks map[string]tick) (domain.FeeGrowth, error) {
+	lower, ok := ticks[string(makeTickID(pos.Pool.ID, pos.TickLower))]
+	if !ok {
+		return domain.FeeGrowth{}, fmt.Errorf("%w: %d", ErrTickNotFound, pos.TickLower)
+	}
+
+	upper, ok := ticks[string(makeTickID(pos.Pool.ID, pos.TickUpper))]
+	if !ok {
+		return domain.FeeGrowth{}, fmt.Errorf("%w: %d", ErrTickNotFound, pos.TickUpper)
+	}
+
+	return domain.FeeGrowth{
+		Global0X128:       subgraph.MustConvertToBigInt(pos.Pool.FeeGrowthGlobal0X128),
+		Global1X128:       subgraph.MustConvertToBigInt(pos.Pool.FeeGrowthGlobal1X128),
+		OutsideLower0X128: subgraph.MustConvertToBigInt(lower.FeeGrowthOutside0X128),
+		OutsideLower1X128: subgraph.MustConvertToBigInt(lower.FeeGrowthOutside1X128),
+		OutsideUpper0X128: subgraph.MustConvertToBigInt(upper.FeeGrowthOutside0X128),
+		OutsideUpper1X128: subgraph.MustConvertToBigInt(upper.FeeGrowthOutside1X128),
+		InsideLast0X128:   subgraph.MustConvertToBigInt(pos.FeeGrowthInside0LastX128),
+		InsideLast1X128:   subgraph.MustConvertToBigInt(pos.FeeGrowthInside1LastX128),
+	}, nil
+}
+
+// toDeposits collects the position history, amounts are already in token units.
+func (pos position) toDeposits() *domain.PositionDeposits {
+	return &domain.PositionDeposits{
+		Deposited0:     subgraph.MustConvertToFloat(pos.DepositedToken0),
+		Deposited1:     subgraph.MustConvertToFloat(pos.DepositedToken1),
+		Withdrawn0:     subgraph.MustConvertToFloat(pos.WithdrawnToken0),
+		Withdrawn1:     subgraph.MustConvertToFloat(pos.WithdrawnToken1),
+		CollectedFees0: subgraph.MustConvertToFloat(pos.CollectedFeesToken0),
+		CollectedFees1: subgraph.MustConvertToFloat(pos.CollectedFeesToken1),
+		OpenedAt:       time.Unix(int64(subgraph.MustConvertToInt(pos.Transaction.Timestamp)), 0).UTC(),
+	}
+}
+
+type transaction struct {
+	Timestamp string
+}
+
+type pool struct {
+	ID                   string
+	Tick                 string
+	SqrtPrice            string
+	FeeGrowthGlobal0X128 string
+	FeeGrowthGlobal1X128 string
+	Token0               token
+	Token1               token
+}
+
+type token struct {
+	ID       string
+	Symbol   string
+	Decimals string
+}
+
+type tick struct {
+	ID                    string
+	FeeGrowthOutside0X128 string
+	FeeGrowthOutside1X128 string
+}
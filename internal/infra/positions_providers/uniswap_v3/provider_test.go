@@ -0,0 +1,193 @@
+package uniswap_v3_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/uniswap_v3"
+)
+
+const (
+	wallet          = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	positionManager = "0x03a520b32C04BF3bEEf7BEb72E919cf822Ed34f1"
+	poolID          = "0x1111111111111111111111111111111111111111"
+)
+
+type providerSuite struct {
+	suite.Suite
+}
+
+func TestProviderTheGraph(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(providerSuite))
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_InRange_FeesCalculated() {
+	stub := newGraphStub()
+	stub.positions[""] = []map[string]any{makePosition("7", -10, 10)}
+	stub.addTick(-10, 1)
+	stub.addTick(10, 1)
+
+	positions, err := s.getPositions(stub)
+	s.Require().NoError(err)
+
+	s.Require().Len(positions, 1)
+	s.Require().Equal("7", positions[0].ID)
+	s.Require().Equal(domain.ChainBase, positions[0].Chain)
+	s.Require().Equal(poolID, positions[0].Pool)
+	s.Require().Equal(positionManager, positions[0].NFTContract)
+	// Fee growth inside is 3 - 1 - 1 = 1 per liquidity.
+	s.Require().Equal(big.NewInt(100), positions[0].UncollectedFees0)
+	s.Require().Equal(big.NewInt(100), positions[0].UncollectedFees1)
+	s.Require().Equal(1.5, positions[0].Deposits.Deposited0)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_MissingTick_Error() {
+	stub := newGraphStub()
+	stub.positions[""] = []map[string]any{makePosition("7", -10, 10)}
+	stub.addTick(-10, 1)
+
+	_, err := s.getPositions(stub)
+	s.Require().ErrorIs(err, uniswap_v3.ErrTickNotFound)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_FullPage_NextPageFetched() {
+	stub := newGraphStub()
+	for i := range subgraph.PageSize {
+		stub.positions[""] = append(stub.positions[""], makePosition(strconv.Itoa(i), -10, 10))
+	}
+	lastID := strconv.Itoa(subgraph.PageSize - 1)
+	stub.positions[lastID] = []map[string]any{makePosition("next", -10, 10)}
+	stub.addTick(-10, 1)
+	stub.addTick(10, 1)
+
+	positions, err := s.getPositions(stub)
+	s.Require().NoError(err)
+
+	s.Require().Len(positions, subgraph.PageSize+1)
+	s.Require().Equal("next", positions[subgraph.PageSize].ID)
+	s.Require().Equal([]int{subgraph.PageSize, subgraph.PageSize, subgraph.PageSize}, stub.firsts)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_NoPositions_Empty() {
+	positions, err := s.getPositions(newGraphStub())
+	s.Require().NoError(err)
+	s.Require().Empty(positions)
+}
+
+func (s *providerSuite) getPositions(stub *graphStub) ([]domain.LiquidityPoolPosition, error) {
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	provider := uniswap_v3.NewProviderTheGraph(
+		graphql.NewClient(server.URL, server.Client()),
+		uniswap_v3.ProviderTheGraphConfig{Chain: domain.ChainBase, PositionManager: positionManager},
+	)
+
+	return provider.GetPositionsWithLiquidity(context.Background(), wallet)
+}
+
+// graphStub answers the positions pages keyed by the last ID and the ticks by ID.
+type graphStub struct {
+	mu        sync.Mutex
+	positions map[string][]map[string]any
+	ticks     map[string]map[string]any
+	firsts    []int
+}
+
+func newGraphStub() *graphStub {
+	return &graphStub{
+		positions: make(map[string][]map[string]any),
+		ticks:     make(map[string]map[string]any),
+	}
+}
+
+func (stub *graphStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var graphRequest struct {
+		Query     string `json:"query"`
+		Variables struct {
+			Wallet string   `json:"wallet"`
+			LastID string   `json:"lastID"`
+			First  int      `json:"first"`
+			IDs    []string `json:"ids"`
+		} `json:"variables"`
+	}
+
+	_ = jsoniter.NewDecoder(request.Body).Decode(&graphRequest)
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	stub.firsts = append(stub.firsts, graphRequest.Variables.First)
+
+	data := make(map[string]any)
+	if strings.Contains(graphRequest.Query, "ticks(") {
+		ticks := make([]map[string]any, 0, len(graphRequest.Variables.IDs))
+		for _, id := range graphRequest.Variables.IDs {
+			if t, ok := stub.ticks[id]; ok {
+				ticks = append(ticks, t)
+			}
+		}
+		data["ticks"] = ticks
+	} else {
+		positions := stub.positions[graphRequest.Variables.LastID]
+		if graphRequest.Variables.Wallet != wallet || positions == nil {
+			positions = []map[string]any{}
+		}
+		data["positions"] = positions
+	}
+
+	_ = jsoniter.NewEncoder(writer).Encode(map[string]any{"data": data})
+}
+
+func (stub *graphStub) addTick(tickIdx int, feeGrowthOutside int64) {
+	id := poolID + "#" + strconv.Itoa(tickIdx)
+	stub.ticks[id] = map[string]any{
+		"id":                    id,
+		"feeGrowthOutside0X128": q128(feeGrowthOutside),
+		"feeGrowthOutside1X128": q128(feeGrowthOutside),
+	}
+}
+
+func makePosition(id string, tickLower, tickUpper int) map[string]any {
+	return map[string]any{
+		"id":                       id,
+		"liquidity":                "100",
+		"feeGrowthInside0LastX128": "0",
+		"feeGrowthInside1LastX128": "0",
+		"depositedToken0":          "1.5",
+		"depositedToken1":          "0",
+		"withdrawnToken0":          "0",
+		"withdrawnToken1":          "0",
+		"collectedFeesToken0":      "0",
+		"collectedFeesToken1":      "0",
+		"transaction":              map[string]any{"timestamp": "1700000000"},
+		"tickLower":                tickLower,
+		"tickUpper":                tickUpper,
+		"pool": map[string]any{
+			"id":                   poolID,
+			"tick":                 "0",
+			"sqrtPrice":            "79228162514264337593543950336",
+			"feeGrowthGlobal0X128": q128(3),
+			"feeGrowthGlobal1X128": q128(3),
+			"token0":               map[string]any{"id": "0x01", "symbol": "WETH", "decimals": "18"},
+			"token1":               map[string]any{"id": "0x02", "symbol": "USDC", "decimals": "6"},
+		},
+	}
+}
+
+func q128(value int64) string {
+	return new(big.Int).Lsh(big.NewInt(value), 128).String()
+}
@@ -0,0 +1,188 @@
+// Package uniswap_v3_forks reads positions of the Uniswap V3 forks from their subgraphs.
+package uniswap_v3_forks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+)
+
+type ProviderTheGraphConfig struct {
+	Chain domain.Chain
+	Dex   domain.Dex
+	// PositionManager is the NFT contract of the positions, the subgraph does not know it.
+	PositionManager string
+}
+
+// ProviderTheGraph reads positions from the subgraph of the fork on the chain. PancakeSwap V3 and SushiSwap V3
+// subgraphs keep the original Uniswap V3 schema, unlike the current Uniswap V3 subgraph, range ticks
+// are nested entities with indexes as strings, so fee growth comes with the positions.
+type ProviderTheGraph struct {
+	client *graphql.Client
+	config ProviderTheGraphConfig
+}
+
+func NewProviderTheGraph(client *graphql.Client, config ProviderTheGraphConfig) *ProviderTheGraph {
+	return &ProviderTheGraph{
+		client: client,
+		config: config,
+	}
+}
+
+func (provider *ProviderTheGraph) GetName() string {
+	return string(provider.config.Chain) + " " + string(provider.config.Dex)
+}
+
+func (provider *ProviderTheGraph) GetPositionsWithLiquidity(
+	ctx context.Context,
+	wallet string,
+) ([]domain.LiquidityPoolPosition, error) {
+	unclosedPositions, err := subgraph.FetchAll(func(lastID graphql.ID) ([]position, error) {
+		var unclosedPosition unclosedPositionsQuery
+
+		variables := map[string]any{
+			"wallet": wallet,
+			"first":  subgraph.PageSize,
+			"lastID": lastID,
+		}
+
+		err := provider.client.Query(ctx, &unclosedPosition, variables)
+		if err != nil {
+			return nil, fmt.Errorf("graphql.Query: %w", err)
+		}
+
+		return unclosedPosition.Positions, nil
+	}, func(pos position) string {
+		return pos.ID
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subgraph.FetchAll: %w", err)
+	}
+
+	if len(unclosedPositions) == 0 {
+		return nil, nil
+	}
+
+	return provider.convertToDomain(wallet, unclosedPositions), nil
+}
+
+func (provider *ProviderTheGraph) convertToDomain(
+	wallet string,
+	unclosedPositions []position,
+) []domain.LiquidityPoolPosition {
+	return lo.Map(unclosedPositions, func(pos position, _ int) domain.LiquidityPoolPosition {
+		currentTick := subgraph.MustConvertToInt(pos.Pool.Tick)
+		tickLower := subgraph.MustConvertToInt(pos.TickLower.TickIdx)
+		tickUpper := subgraph.MustConvertToInt(pos.TickUpper.TickIdx)
+		liquidity := subgraph.MustConvertToBigInt(pos.Liquidity)
+		fees0, fees1 := domain.GetUncollectedFees(currentTick, tickLower, tickUpper, liquidity, pos.toFeeGrowth())
+
+		return domain.LiquidityPoolPosition{
+			ID:               pos.ID,
+			Wallet:           wallet,
+			Chain:            provider.config.Chain,
+			Dex:              provider.config.Dex,
+			Pool:             pos.Pool.ID,
+			NFTContract:      provider.config.PositionManager,
+			TickLower:        tickLower,
+			TickUpper:        tickUpper,
+			CurrentTick:      currentTick,
+			Liquidity:        liquidity,
+			SqrtPriceX96:     subgraph.MustConvertToBigInt(pos.Pool.SqrtPrice),
+			UncollectedFees0: fees0,
+			UncollectedFees1: fees1,
+			Token0: domain.Token{
+				Name:     pos.Pool.Token0.Symbol,
+				Decimals: subgraph.MustConvertToInt(pos.Pool.Token0.Decimals),
+				Address:  pos.Pool.Token0.ID,
+			},
+			Token1: domain.Token{
+				Name:     pos.Pool.Token1.Symbol,
+				Decimals: subgraph.MustConvertToInt(pos.Pool.Token1.Decimals),
+				Address:  pos.Pool.Token1.ID,
+			},
+			Deposits: pos.toDeposits(),
+		}
+	})
+}
+
+type unclosedPositionsQuery struct {
+	Positions []position `graphql:"positions(first: $first, where: {owner: $wallet, liquidity_gt: 0, id_gt: $lastID})"`
+}
+
+type position struct {
+	ID                       string
+	Liquidity                string
+	FeeGrowthInside0LastX128 string
+	FeeGrowthInside1LastX128 string
+	DepositedToken0          string
+	DepositedToken1          string
+	WithdrawnToken0          string
+	WithdrawnToken1          string
+	CollectedFeesToken0      string
+	CollectedFeesToken1      string
+	Transaction              transaction
+	TickLower                tick
+	TickUpper                tick
+	Pool                     pool
+}
+
+// toFeeGrowth collects fee accounting data, the subgraph does not expose tokensOwed, so fees
+// credited to the position on liquidity changes are not counted.
+func (pos position) toFeeGrowth() domain.FeeGrowth {
+	return domain.FeeGrowth{
+		Global0X128:       subgraph.MustConvertToBigInt(pos.Pool.FeeGrowthGlobal0X128),
+		Global1X128:       subgraph.MustConvertToBigInt(pos.Pool.FeeGrowthGlobal1X128),
+		OutsideLower0X128: subgraph.MustConvertToBigInt(pos.TickLower.FeeGrowthOutside0X128),
+		OutsideLower1X128: subgraph.MustConvertToBigInt(pos.TickLower.FeeGrowthOutside1X128),
+		OutsideUpper0X128: subgraph.MustConvertToBigInt(pos.TickUpper.FeeGrowthOutside0X128),
+		OutsideUpper1X128: subgraph.MustConvertToBigInt(pos.TickUpper.FeeGrowthOutside1X128),
+		InsideLast0X128:   subgraph.MustConvertToBigInt(pos.FeeGrowthInside0LastX128),
+		InsideLast1X128:   subgraph.MustConvertToBigInt(pos.FeeGrowthInside1LastX128),
+	}
+}
+
+// toDeposits collects the position history, amounts are already in token units.
+func (pos position) toDeposits() *domain.PositionDeposits {
+	return &domain.PositionDeposits{
+		Deposited0:     subgraph.MustConvertToFloat(pos.DepositedToken0),
+		Deposited1:     subgraph.MustConvertToFloat(pos.DepositedToken1),
+		Withdrawn0:     subgraph.MustConvertToFloat(pos.WithdrawnToken0),
+		Withdrawn1:     subgraph.MustConvertToFloat(pos.WithdrawnToken1),
+		CollectedFees0: subgraph.MustConvertToFloat(pos.CollectedFeesToken0),
+		CollectedFees1: subgraph.MustConvertToFloat(pos.CollectedFeesToken1),
+		OpenedAt:       time.Unix(int64(subgraph.MustConvertToInt(pos.Transaction.Timestamp)), 0).UTC(),
+	}
+}
+
+type transaction struct {
+	Timestamp string
+}
+
+type pool struct {
+	ID                   string
+	Tick                 string
+	SqrtPrice            string
+	FeeGrowthGlobal0X128 string
+	FeeGrowthGlobal1X128 string
+	Token0               token
+	Token1               token
+}
+
+type token struct {
+	ID       string
+	Symbol   string
+	Decimals string
+}
+
+type tick struct {
+	TickIdx               string
+	FeeGrowthOutside0X128 string
+	FeeGrowthOutside1X128 string
+}
@@ -0,0 +1,206 @@
+package uniswap_v3_forks_test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/uniswap_v3_forks"
+)
+
+const (
+	wallet          = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	positionManager = "0x46A15B0b27311cedF172AB29E4f4766fbE7F4364"
+)
+
+// pancakeSwapPositions is a page of the PancakeSwap V3 exchange subgraph on Base.
+const pancakeSwapPositions = `{"data": {"positions": [{
+	"id": "1234567",
+	"liquidity": "1000",
+	"feeGrowthInside0LastX128": "0",
+	"feeGrowthInside1LastX128": "0",
+	"depositedToken0": "0.5",
+	"depositedToken1": "1700.123456",
+	"withdrawnToken0": "0",
+	"withdrawnToken1": "0",
+	"collectedFeesToken0": "0",
+	"collectedFeesToken1": "0",
+	"transaction": {"timestamp": "1700000000"},
+	"tickLower": {
+		"tickIdx": "-200",
+		"feeGrowthOutside0X128": "340282366920938463463374607431768211456",
+		"feeGrowthOutside1X128": "340282366920938463463374607431768211456"
+	},
+	"tickUpper": {
+		"tickIdx": "200",
+		"feeGrowthOutside0X128": "340282366920938463463374607431768211456",
+		"feeGrowthOutside1X128": "340282366920938463463374607431768211456"
+	},
+	"pool": {
+		"id": "0x72ab388e2e2f6facef59e3c3fa2c4e29011c2d38",
+		"tick": "-100",
+		"sqrtPrice": "79228162514264337593543950336",
+		"feeGrowthGlobal0X128": "1020847100762815390390123822295304634368",
+		"feeGrowthGlobal1X128": "1701411834604692317316873037158841057280",
+		"token0": {"id": "0x4200000000000000000000000000000000000006", "symbol": "WETH", "decimals": "18"},
+		"token1": {"id": "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913", "symbol": "USDC", "decimals": "6"}
+	}
+}]}}`
+
+// sushiSwapPositions is a page of the SushiSwap V3 subgraph on Arbitrum.
+const sushiSwapPositions = `{"data": {"positions": [{
+	"id": "98765",
+	"liquidity": "1000",
+	"feeGrowthInside0LastX128": "340282366920938463463374607431768211456",
+	"feeGrowthInside1LastX128": "0",
+	"depositedToken0": "100",
+	"depositedToken1": "0",
+	"withdrawnToken0": "0",
+	"withdrawnToken1": "0",
+	"collectedFeesToken0": "0",
+	"collectedFeesToken1": "0",
+	"transaction": {"timestamp": "1710000000"},
+	"tickLower": {"tickIdx": "-60", "feeGrowthOutside0X128": "0", "feeGrowthOutside1X128": "0"},
+	"tickUpper": {"tickIdx": "60", "feeGrowthOutside0X128": "0", "feeGrowthOutside1X128": "0"},
+	"pool": {
+		"id": "0xf3eb87c1f6020982173c908e7eb31aa66c1f0296",
+		"tick": "0",
+		"sqrtPrice": "79228162514264337593543950336",
+		"feeGrowthGlobal0X128": "1020847100762815390390123822295304634368",
+		"feeGrowthGlobal1X128": "0",
+		"token0": {"id": "0x82af49447d8a07e3bd95bd0d56f35241523fbab1", "symbol": "WETH", "decimals": "18"},
+		"token1": {"id": "0xaf88d065e77c8cc2239327c5edb3a432268e5831", "symbol": "USDC", "decimals": "6"}
+	}
+}]}}`
+
+type providerSuite struct {
+	suite.Suite
+}
+
+func TestProviderTheGraph(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(providerSuite))
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_PancakeSwap_NestedTicks() {
+	stub := &graphStub{page: pancakeSwapPositions}
+	config := uniswap_v3_forks.ProviderTheGraphConfig{
+		Chain:           domain.ChainBase,
+		Dex:             domain.DexPancakeSwapV3,
+		PositionManager: positionManager,
+	}
+
+	positions, err := s.getPositions(stub, config)
+	s.Require().NoError(err)
+
+	s.Require().Len(positions, 1)
+	s.Require().Equal("1234567", positions[0].ID)
+	s.Require().Equal(domain.ChainBase, positions[0].Chain)
+	s.Require().Equal(domain.DexPancakeSwapV3, positions[0].Dex)
+	s.Require().Equal("0x72ab388e2e2f6facef59e3c3fa2c4e29011c2d38", positions[0].Pool)
+	s.Require().Equal(positionManager, positions[0].NFTContract)
+	s.Require().Equal(-200, positions[0].TickLower)
+	s.Require().Equal(200, positions[0].TickUpper)
+	s.Require().Equal(-100, positions[0].CurrentTick)
+	s.Require().Equal(domain.Token{Name: "USDC", Decimals: 6, Address: "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"},
+		positions[0].Token1)
+	// Fee growth inside is 3 - 1 - 1 = 1 and 5 - 1 - 1 = 3 per liquidity.
+	s.Require().Equal(big.NewInt(1000), positions[0].UncollectedFees0)
+	s.Require().Equal(big.NewInt(3000), positions[0].UncollectedFees1)
+	s.Require().InDelta(1700.123456, positions[0].Deposits.Deposited1, 1e-9)
+	s.Require().Equal(time.Unix(1700000000, 0).UTC(), positions[0].Deposits.OpenedAt)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_SushiSwap_FeesSinceLastCollect() {
+	stub := &graphStub{page: sushiSwapPositions}
+	config := uniswap_v3_forks.ProviderTheGraphConfig{
+		Chain:           domain.ChainArbitrum,
+		Dex:             domain.DexSushiSwapV3,
+		PositionManager: positionManager,
+	}
+
+	positions, err := s.getPositions(stub, config)
+	s.Require().NoError(err)
+
+	s.Require().Len(positions, 1)
+	s.Require().Equal("98765", positions[0].ID)
+	s.Require().Equal(domain.DexSushiSwapV3, positions[0].Dex)
+	s.Require().Equal(-60, positions[0].TickLower)
+	s.Require().Equal(60, positions[0].TickUpper)
+	// Fee growth inside is 3 per liquidity, 1 of it is already collected.
+	s.Require().Equal(big.NewInt(2000), positions[0].UncollectedFees0)
+	s.Require().Equal(big.NewInt(0), positions[0].UncollectedFees1)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_Query_Paginated() {
+	stub := &graphStub{page: sushiSwapPositions}
+
+	_, err := s.getPositions(stub, uniswap_v3_forks.ProviderTheGraphConfig{Dex: domain.DexSushiSwapV3})
+	s.Require().NoError(err)
+
+	s.Require().Equal(subgraph.PageSize, stub.first)
+	s.Require().Equal(wallet, stub.wallet)
+}
+
+func (s *providerSuite) TestGetName_ChainAndDex() {
+	provider := uniswap_v3_forks.NewProviderTheGraph(nil, uniswap_v3_forks.ProviderTheGraphConfig{
+		Chain: domain.ChainArbitrum,
+		Dex:   domain.DexPancakeSwapV3,
+	})
+	s.Require().Equal("Arbitrum PancakeSwap V3", provider.GetName())
+}
+
+func (s *providerSuite) getPositions(
+	stub *graphStub,
+	config uniswap_v3_forks.ProviderTheGraphConfig,
+) ([]domain.LiquidityPoolPosition, error) {
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	provider := uniswap_v3_forks.NewProviderTheGraph(graphql.NewClient(server.URL, server.Client()), config)
+
+	return provider.GetPositionsWithLiquidity(context.Background(), wallet)
+}
+
+// graphStub answers the first page with the canned response and records the query variables.
+type graphStub struct {
+	mu     sync.Mutex
+	page   string
+	wallet string
+	first  int
+}
+
+func (stub *graphStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var graphRequest struct {
+		Variables struct {
+			Wallet string `json:"wallet"`
+			LastID string `json:"lastID"`
+			First  int    `json:"first"`
+		} `json:"variables"`
+	}
+
+	_ = jsoniter.NewDecoder(request.Body).Decode(&graphRequest)
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	stub.wallet = graphRequest.Variables.Wallet
+	stub.first = graphRequest.Variables.First
+
+	if graphRequest.Variables.LastID != "" {
+		_, _ = writer.Write([]byte(`{"data": {"positions": []}}`))
+		return
+	}
+
+	_, _ = writer.Write([]byte(stub.page))
+}
@@ -0,0 +1,442 @@
+package uniswap_v4
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/samber/lo"
+	"github.com/sourcegraph/conc/iter"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+const (
+	selectorGetPoolAndPositionInfo  = "7ba03aad" // getPoolAndPositionInfo(uint256)
+	selectorGetSlot0                = "c815641c" // getSlot0(bytes32)
+	selectorGetFeeGrowthGlobals     = "9ec538c8" // getFeeGrowthGlobals(bytes32)
+	selectorGetTickFeeGrowthOutside = "8a2bb9e6" // getTickFeeGrowthOutside(bytes32,int24)
+	selectorGetPositionInfo         = "dacf1d2f" // getPositionInfo(bytes32,address,int24,int24,bytes32)
+	selectorSymbol                  = "95d89b41" // symbol()
+	selectorDecimals                = "313ce567" // decimals()
+)
+
+// Words of getPoolAndPositionInfo(uint256) result, the pool key words are followed by the packed position info.
+const (
+	poolKeyCurrency0Word = iota
+	poolKeyCurrency1Word
+	poolKeyFeeWord
+	poolKeyTickSpacingWord
+	poolKeyHooksWord
+	positionInfoWord
+	poolAndPositionInfoWords
+)
+
+const (
+	slot0SqrtPriceWord = iota
+	slot0TickWord
+	slot0ProtocolFeeWord
+	slot0LPFeeWord
+	slot0Words
+)
+
+const (
+	stateLiquidityWord = iota
+	stateFeeGrowthInside0LastWord
+	stateFeeGrowthInside1LastWord
+	stateWords
+)
+
+// Position info is packed as: pool ID (25 bytes) | tickUpper (3 bytes) | tickLower (3 bytes) | hasSubscriber (1 byte).
+const (
+	packedTickSize     = 3
+	packedTickLowerEnd = ethrpc.WordSize - 1
+	packedTickUpperEnd = packedTickLowerEnd - packedTickSize
+	int24SignBit       = 1 << 23
+	int24Modulus       = 1 << 24
+)
+
+const (
+	feeGrowthWords = 2
+	// nativeCurrency is the currency address of the chain native token, V4 pools hold it without wrapping.
+	nativeCurrency = "0x0000000000000000000000000000000000000000"
+	nativeDecimals = 18
+)
+
+type ProviderConfig struct {
+	Chain           domain.Chain
+	PositionManager string
+	StateView       string
+	// NativeSymbol is the name of the chain native token, e.g. "ETH".
+	NativeSymbol string
+}
+
+// Provider reads positions of the Uniswap V4 PositionManager. The manager is not enumerable, so position IDs
+// of the owner are taken from the subgraph, while the positions state is read from the manager and StateView.
+type Provider struct {
+	graph  *graphql.Client
+	rpc    *ethrpc.Client
+	config ProviderConfig
+
+	tokensMu sync.Mutex
+	tokens   map[string]domain.Token
+}
+
+func NewProvider(graph *graphql.Client, rpc *ethrpc.Client, config ProviderConfig) *Provider {
+	return &Provider{
+		graph:  graph,
+		rpc:    rpc,
+		config: config,
+		tokens: make(map[string]domain.Token),
+	}
+}
+
+func (provider *Provider) GetName() string {
+	return string(provider.config.Chain) + " Uniswap V4"
+}
+
+func (provider *Provider) GetPositionsWithLiquidity(
+	ctx context.Context,
+	wallet string,
+) ([]domain.LiquidityPoolPosition, error) {
+	tokenIDs, err := provider.getTokenIDs(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("getTokenIDs: %w", err)
+	}
+
+	positions, err := iter.MapErr(tokenIDs, func(tokenID **big.Int) (*domain.LiquidityPoolPosition, error) {
+		return provider.getPosition(ctx, wallet, *tokenID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getPosition: %w", err)
+	}
+
+	open := lo.Compact(positions)
+	if len(open) == 0 {
+		return nil, nil
+	}
+
+	return lo.Map(open, func(position *domain.LiquidityPoolPosition, _ int) domain.LiquidityPoolPosition {
+		return *position
+	}), nil
+}
+
+func (provider *Provider) call(ctx context.Context, to string, data []byte, words int) (ethrpc.Words, error) {
+	result, err := provider.rpc.Call(ctx, to, data)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.Call: %w", err)
+	}
+
+	decoded, err := ethrpc.DecodeWords(result, words)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.DecodeWords: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// getFeeGrowth reads the pool fee growth of the position range and the growth inside it on the last update.
+func (provider *Provider) getFeeGrowth(ctx context.Context, raw rawPosition) (domain.FeeGrowth, error) {
+	globals, err := provider.call(ctx, provider.config.StateView,
+		ethrpc.MustEncodeCall(selectorGetFeeGrowthGlobals, raw.poolID), feeGrowthWords)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("getFeeGrowthGlobals: %w", err)
+	}
+
+	lower, err := provider.getFeeGrowthOutside(ctx, raw.poolID, raw.tickLower)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("getFeeGrowthOutside: %w", err)
+	}
+
+	upper, err := provider.getFeeGrowthOutside(ctx, raw.poolID, raw.tickUpper)
+	if err != nil {
+		return domain.FeeGrowth{}, fmt.Errorf("getFeeGrowthOutside: %w", err)
+	}
+
+	return domain.FeeGrowth{
+		Global0X128:       globals.Uint(0),
+		Global1X128:       globals.Uint(1),
+		OutsideLower0X128: lower.Uint(0),
+		OutsideLower1X128: lower.Uint(1),
+		OutsideUpper0X128: upper.Uint(0),
+		OutsideUpper1X128: upper.Uint(1),
+		InsideLast0X128:   raw.feeGrowthInside0Last,
+		InsideLast1X128:   raw.feeGrowthInside1Last,
+	}, nil
+}
+
+func (provider *Provider) getFeeGrowthOutside(ctx context.Context, poolID []byte, tick int) (ethrpc.Words, error) {
+	data := ethrpc.MustEncodeCall(selectorGetTickFeeGrowthOutside, poolID, ethrpc.EncodeInt(int64(tick)))
+
+	outside, err := provider.call(ctx, provider.config.StateView, data, feeGrowthWords)
+	if err != nil {
+		return nil, fmt.Errorf("getTickFeeGrowthOutside: %w", err)
+	}
+
+	return outside, nil
+}
+
+// getPosition returns nil for positions without liquidity.
+func (provider *Provider) getPosition(
+	ctx context.Context,
+	wallet string,
+	tokenID *big.Int,
+) (*domain.LiquidityPoolPosition, error) {
+	raw, err := provider.getRawPosition(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("getRawPosition: %w", err)
+	}
+
+	if raw.liquidity.Sign() == 0 {
+		return nil, nil //nolint:nilnil // Closed position is not an error.
+	}
+
+	pool, err := provider.getPoolState(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("getPoolState: %w", err)
+	}
+
+	token0, token1, err := provider.getTokens(ctx, raw)
+	if err != nil {
+		return nil, fmt.Errorf("getTokens: %w", err)
+	}
+
+	position := raw.toDomain(pool, token0, token1)
+	position.ID = tokenID.String()
+	position.Wallet = wallet
+	position.Chain = provider.config.Chain
+	position.NFTContract = provider.config.PositionManager
+
+	return &position, nil
+}
+
+// getPoolState reads the pool price and fee growth of the position range.
+func (provider *Provider) getPoolState(ctx context.Context, raw rawPosition) (poolState, error) {
+	slot0, err := provider.call(ctx, provider.config.StateView,
+		ethrpc.MustEncodeCall(selectorGetSlot0, raw.poolID), slot0Words)
+	if err != nil {
+		return poolState{}, fmt.Errorf("getSlot0: %w", err)
+	}
+
+	feeGrowth, err := provider.getFeeGrowth(ctx, raw)
+	if err != nil {
+		return poolState{}, fmt.Errorf("getFeeGrowth: %w", err)
+	}
+
+	return poolState{
+		sqrtPriceX96: slot0.Uint(slot0SqrtPriceWord),
+		tick:         int(slot0.Int(slot0TickWord).Int64()),
+		feeGrowth:    feeGrowth,
+	}, nil
+}
+
+// getPositionState reads the position liquidity and fee growth inside its range on the last update,
+// the PositionManager owns positions in the PoolManager salted with the token ID.
+func (provider *Provider) getPositionState(
+	ctx context.Context,
+	tokenID *big.Int,
+	raw rawPosition,
+) (ethrpc.Words, error) {
+	owner, err := ethrpc.EncodeAddress(provider.config.PositionManager)
+	if err != nil {
+		return nil, fmt.Errorf("ethrpc.EncodeAddress: %w", err)
+	}
+
+	data := ethrpc.MustEncodeCall(selectorGetPositionInfo, raw.poolID, owner,
+		ethrpc.EncodeInt(int64(raw.tickLower)), ethrpc.EncodeInt(int64(raw.tickUpper)), ethrpc.EncodeUint(tokenID))
+
+	state, err := provider.call(ctx, provider.config.StateView, data, stateWords)
+	if err != nil {
+		return nil, fmt.Errorf("getPositionInfo: %w", err)
+	}
+
+	return state, nil
+}
+
+// getRawPosition resolves the pool key and range of the position. The pool ID is the hash of the whole key,
+// so pools of the same pair with different hooks or the dynamic fee flag are told apart.
+func (provider *Provider) getRawPosition(ctx context.Context, tokenID *big.Int) (rawPosition, error) {
+	info, err := provider.call(ctx, provider.config.PositionManager,
+		ethrpc.MustEncodeCall(selectorGetPoolAndPositionInfo, ethrpc.EncodeUint(tokenID)), poolAndPositionInfoWords)
+	if err != nil {
+		return rawPosition{}, fmt.Errorf("getPoolAndPositionInfo: %w", err)
+	}
+
+	raw := rawPosition{
+		poolID:    ethrpc.Keccak256(info[:positionInfoWord]...),
+		currency0: info.Address(poolKeyCurrency0Word),
+		currency1: info.Address(poolKeyCurrency1Word),
+		tickLower: decodePackedTick(info[positionInfoWord], packedTickLowerEnd),
+		tickUpper: decodePackedTick(info[positionInfoWord], packedTickUpperEnd),
+	}
+
+	state, err := provider.getPositionState(ctx, tokenID, raw)
+	if err != nil {
+		return rawPosition{}, fmt.Errorf("getPositionState: %w", err)
+	}
+
+	raw.liquidity = state.Uint(stateLiquidityWord)
+	raw.feeGrowthInside0Last = state.Uint(stateFeeGrowthInside0LastWord)
+	raw.feeGrowthInside1Last = state.Uint(stateFeeGrowthInside1LastWord)
+
+	return raw, nil
+}
+
+// getToken returns the native token without calls, it has no ERC20 contract.
+func (provider *Provider) getToken(ctx context.Context, address string) (domain.Token, error) {
+	if address == nativeCurrency {
+		return domain.Token{
+			Name:     provider.config.NativeSymbol,
+			Decimals: nativeDecimals,
+			Address:  address,
+		}, nil
+	}
+
+	provider.tokensMu.Lock()
+	token, ok := provider.tokens[address]
+	provider.tokensMu.Unlock()
+
+	if ok {
+		return token, nil
+	}
+
+	token, err := provider.readToken(ctx, address)
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	provider.tokensMu.Lock()
+	provider.tokens[address] = token
+	provider.tokensMu.Unlock()
+
+	return token, nil
+}
+
+// getTokenIDs lists all positions of the wallet, the subgraph does not track liquidity, so closed positions
+// are filtered out by their state on chain.
+func (provider *Provider) getTokenIDs(ctx context.Context, wallet string) ([]*big.Int, error) {
+	owned, err := subgraph.FetchAll(func(lastID graphql.ID) ([]position, error) {
+		var owned ownedPositionsQuery
+
+		// The subgraph keeps owners as lower case strings.
+		variables := map[string]any{
+			"wallet": strings.ToLower(wallet),
+			"first":  subgraph.PageSize,
+			"lastID": lastID,
+		}
+
+		err := provider.graph.Query(ctx, &owned, variables)
+		if err != nil {
+			return nil, fmt.Errorf("graphql.Query: %w", err)
+		}
+
+		return owned.Positions, nil
+	}, func(pos position) string {
+		return pos.ID
+	})
+	if err != nil {
+		return nil, fmt.Errorf("subgraph.FetchAll: %w", err)
+	}
+
+	return lo.Map(owned, func(pos position, _ int) *big.Int {
+		return subgraph.MustConvertToBigInt(pos.TokenID)
+	}), nil
+}
+
+func (provider *Provider) getTokens(ctx context.Context, raw rawPosition) (token0, token1 domain.Token, err error) {
+	token0, err = provider.getToken(ctx, raw.currency0)
+	if err != nil {
+		return domain.Token{}, domain.Token{}, fmt.Errorf("getToken: %w", err)
+	}
+
+	token1, err = provider.getToken(ctx, raw.currency1)
+	if err != nil {
+		return domain.Token{}, domain.Token{}, fmt.Errorf("getToken: %w", err)
+	}
+
+	return token0, token1, nil
+}
+
+func (provider *Provider) readToken(ctx context.Context, address string) (domain.Token, error) {
+	symbol, err := provider.rpc.Call(ctx, address, ethrpc.MustEncodeCall(selectorSymbol))
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("symbol: %w", err)
+	}
+
+	name, err := ethrpc.DecodeString(symbol)
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("ethrpc.DecodeString: %w", err)
+	}
+
+	decimals, err := provider.call(ctx, address, ethrpc.MustEncodeCall(selectorDecimals), 1)
+	if err != nil {
+		return domain.Token{}, fmt.Errorf("decimals: %w", err)
+	}
+
+	return domain.Token{
+		Name:     name,
+		Decimals: int(decimals.Uint(0).Int64()),
+		Address:  address,
+	}, nil
+}
+
+// decodePackedTick decodes int24 tick packed into the bytes of the word before end.
+func decodePackedTick(word []byte, end int) int {
+	tick := new(big.Int).SetBytes(word[end-packedTickSize : end]).Int64()
+	if tick >= int24SignBit {
+		tick -= int24Modulus
+	}
+
+	return int(tick)
+}
+
+type ownedPositionsQuery struct {
+	Positions []position `graphql:"positions(first: $first, where: {owner: $wallet, id_gt: $lastID})"`
+}
+
+type position struct {
+	ID      string
+	TokenID string `graphql:"tokenId"`
+}
+
+type rawPosition struct {
+	poolID    []byte
+	currency0 string
+	currency1 string
+	tickLower int
+	tickUpper int
+	liquidity *big.Int
+
+	feeGrowthInside0Last *big.Int
+	feeGrowthInside1Last *big.Int
+}
+
+// toDomain makes the position of the pool, fees are settled on every liquidity change in V4,
+// so there are no tokens owed.
+func (raw rawPosition) toDomain(pool poolState, token0, token1 domain.Token) domain.LiquidityPoolPosition {
+	fees0, fees1 := domain.GetUncollectedFees(pool.tick, raw.tickLower, raw.tickUpper, raw.liquidity, pool.feeGrowth)
+
+	return domain.LiquidityPoolPosition{
+		Dex:              domain.DexUniswapV4,
+		Token0:           token0,
+		Token1:           token1,
+		CurrentTick:      pool.tick,
+		TickLower:        raw.tickLower,
+		TickUpper:        raw.tickUpper,
+		Liquidity:        raw.liquidity,
+		SqrtPriceX96:     pool.sqrtPriceX96,
+		UncollectedFees0: fees0,
+		UncollectedFees1: fees1,
+	}
+}
+
+type poolState struct {
+	sqrtPriceX96 *big.Int
+	tick         int
+	feeGrowth    domain.FeeGrowth
+}
@@ -0,0 +1,247 @@
+package uniswap_v4_test
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/subgraph"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/positions_providers/uniswap_v4"
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+const (
+	wallet          = "0xAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaaAAAAaaaa"
+	positionManager = "0x2222222222222222222222222222222222222222"
+	stateView       = "0x3333333333333333333333333333333333333333"
+	hooks           = "0x4444444444444444444444444444444444444444"
+	native          = "0x0000000000000000000000000000000000000000"
+	token1          = "0x6666666666666666666666666666666666666666"
+	dynamicFee      = 0x800000
+)
+
+type providerSuite struct {
+	suite.Suite
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(providerSuite))
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_OpenAndClosed_OnlyOpen() {
+	key := poolKey()
+	poolID := ethrpc.Keccak256(key)
+
+	node := newNodeStub()
+	node.expect(positionManager, call("7ba03aad", uint64Word(7)), concat(key, packedInfo(-10, 10)))
+	node.expect(positionManager, call("7ba03aad", uint64Word(8)), concat(key, packedInfo(-20, 20)))
+	node.expect(stateView, positionInfoCall(poolID, -10, 10, 7), concat(uint64Word(100), uint64Word(0), uint64Word(0)))
+	node.expect(stateView, positionInfoCall(poolID, -20, 20, 8), concat(uint64Word(0), uint64Word(0), uint64Word(0)))
+	slot0 := concat(uint64Word(1), ethrpc.EncodeInt(-5), uint64Word(0), uint64Word(3000))
+	node.expect(stateView, call("c815641c", poolID), slot0)
+	node.expect(stateView, call("9ec538c8", poolID), concat(q128Word(3), uint64Word(0)))
+	node.expect(stateView, call("8a2bb9e6", poolID, ethrpc.EncodeInt(-10)), concat(q128Word(1), uint64Word(0)))
+	node.expect(stateView, call("8a2bb9e6", poolID, ethrpc.EncodeInt(10)), concat(q128Word(1), uint64Word(0)))
+	node.expect(token1, call("95d89b41"), stringResult("USDC"))
+	node.expect(token1, call("313ce567"), uint64Word(6))
+
+	rpcServer := httptest.NewServer(node)
+	defer rpcServer.Close()
+
+	graphServer := httptest.NewServer(graphStub{"7", "8"})
+	defer graphServer.Close()
+
+	provider := uniswap_v4.NewProvider(
+		graphql.NewClient(graphServer.URL, graphServer.Client()),
+		ethrpc.NewClient(rpcServer.URL, rpcServer.Client()),
+		uniswap_v4.ProviderConfig{
+			Chain:           domain.ChainBase,
+			PositionManager: positionManager,
+			StateView:       stateView,
+			NativeSymbol:    "ETH",
+		},
+	)
+
+	positions, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+	s.Require().NoError(err)
+
+	s.Require().Equal([]domain.LiquidityPoolPosition{
+		{
+			ID:           "7",
+			Wallet:       wallet,
+			Chain:        domain.ChainBase,
+			Dex:          domain.DexUniswapV4,
+			Token0:       domain.Token{Name: "ETH", Decimals: 18, Address: native},
+			Token1:       domain.Token{Name: "USDC", Decimals: 6, Address: token1},
+			CurrentTick:  -5,
+			TickLower:    -10,
+			TickUpper:    10,
+			Liquidity:    big.NewInt(100),
+			SqrtPriceX96: big.NewInt(1),
+			// Fee growth inside is 1 per liquidity.
+			UncollectedFees0: big.NewInt(100),
+			UncollectedFees1: big.NewInt(0),
+			NFTContract:      positionManager,
+		},
+	}, positions)
+}
+
+func (s *providerSuite) TestGetPositionsWithLiquidity_NoPositions_Empty() {
+	rpcServer := httptest.NewServer(newNodeStub())
+	defer rpcServer.Close()
+
+	graphServer := httptest.NewServer(graphStub{})
+	defer graphServer.Close()
+
+	provider := uniswap_v4.NewProvider(
+		graphql.NewClient(graphServer.URL, graphServer.Client()),
+		ethrpc.NewClient(rpcServer.URL, rpcServer.Client()),
+		uniswap_v4.ProviderConfig{PositionManager: positionManager, StateView: stateView},
+	)
+
+	positions, err := provider.GetPositionsWithLiquidity(context.Background(), wallet)
+	s.Require().NoError(err)
+	s.Require().Empty(positions)
+}
+
+// graphStub returns the token IDs as positions of the wallet, owners are expected in lower case.
+// Only the first page is answered, pages are expected to be requested by PageSize.
+type graphStub []string
+
+func (stub graphStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var graphRequest struct {
+		Variables struct {
+			Wallet string `json:"wallet"`
+			LastID string `json:"lastID"`
+			First  int    `json:"first"`
+		} `json:"variables"`
+	}
+
+	_ = jsoniter.NewDecoder(request.Body).Decode(&graphRequest)
+
+	positions := make([]map[string]string, 0, len(stub))
+	if graphRequest.Variables.Wallet == strings.ToLower(wallet) && graphRequest.Variables.LastID == "" &&
+		graphRequest.Variables.First == subgraph.PageSize {
+		for _, tokenID := range stub {
+			positions = append(positions, map[string]string{"id": tokenID, "tokenId": tokenID})
+		}
+	}
+
+	_ = jsoniter.NewEncoder(writer).Encode(map[string]any{"data": map[string]any{"positions": positions}})
+}
+
+// nodeStub answers eth_call with canned results, unknown calls are reverted.
+type nodeStub struct {
+	results map[string]string
+}
+
+func newNodeStub() *nodeStub {
+	return &nodeStub{
+		results: make(map[string]string),
+	}
+}
+
+func (stub *nodeStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var rpcRequest struct {
+		ID     int64 `json:"id"`
+		Params []jsoniter.RawMessage
+	}
+
+	err := jsoniter.NewDecoder(request.Body).Decode(&rpcRequest)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var params struct {
+		To   string `json:"to"`
+		Data string `json:"data"`
+	}
+
+	_ = jsoniter.Unmarshal(rpcRequest.Params[0], &params)
+
+	response := map[string]any{"jsonrpc": "2.0", "id": rpcRequest.ID}
+
+	result, ok := stub.results[key(params.To, params.Data)]
+	if ok {
+		response["result"] = result
+	} else {
+		response["error"] = map[string]any{"code": 3, "message": "execution reverted"}
+	}
+
+	_ = jsoniter.NewEncoder(writer).Encode(response)
+}
+
+func (stub *nodeStub) expect(to string, data, result []byte) {
+	stub.results[key(to, "0x"+hex.EncodeToString(data))] = "0x" + hex.EncodeToString(result)
+}
+
+func key(to, data string) string {
+	return strings.ToLower(to) + strings.ToLower(data)
+}
+
+func call(selector string, args ...[]byte) []byte {
+	return ethrpc.MustEncodeCall(selector, args...)
+}
+
+func positionInfoCall(poolID []byte, tickLower, tickUpper int64, tokenID uint64) []byte {
+	return call("dacf1d2f",
+		poolID, address(positionManager), ethrpc.EncodeInt(tickLower), ethrpc.EncodeInt(tickUpper), uint64Word(tokenID))
+}
+
+// poolKey makes ABI encoded key of the native token pool with the hook and the dynamic fee.
+func poolKey() []byte {
+	return concat(address(native), address(token1), uint64Word(dynamicFee), ethrpc.EncodeInt(60), address(hooks))
+}
+
+// packedInfo makes the position info with the truncated pool ID and the subscriber flag set.
+func packedInfo(tickLower, tickUpper int64) []byte {
+	word := make([]byte, ethrpc.WordSize)
+	for i := range 25 {
+		word[i] = 0xAB
+	}
+	copy(word[25:28], ethrpc.EncodeInt(tickUpper)[29:])
+	copy(word[28:31], ethrpc.EncodeInt(tickLower)[29:])
+	word[31] = 1
+	return word
+}
+
+func address(value string) []byte {
+	encoded, err := ethrpc.EncodeAddress(value)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
+func uint64Word(value uint64) []byte {
+	return ethrpc.EncodeUint(new(big.Int).SetUint64(value))
+}
+
+func q128Word(value int64) []byte {
+	return ethrpc.EncodeUint(new(big.Int).Lsh(big.NewInt(value), 128))
+}
+
+func stringResult(value string) []byte {
+	word := make([]byte, ethrpc.WordSize)
+	copy(word, value)
+	return concat(uint64Word(ethrpc.WordSize), uint64Word(uint64(len(value))), word)
+}
+
+func concat(words ...[]byte) []byte {
+	var data []byte
+	for _, word := range words {
+		data = append(data, word...)
+	}
+	return data
+}
@@ -0,0 +1,37 @@
+package price_providers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// ByChain prices tokens by the provider of their chain, tokens of chains without provider are not priced.
+type ByChain struct {
+	impls map[domain.Chain]domain.PriceProvider
+}
+
+func NewByChain(impls map[domain.Chain]domain.PriceProvider) *ByChain {
+	return &ByChain{
+		impls: impls,
+	}
+}
+
+func (*ByChain) GetName() string {
+	return "By chain"
+}
+
+func (c *ByChain) GetPricesUSD(ctx context.Context, chain domain.Chain, tokens []domain.Token) (domain.Prices, error) {
+	impl, ok := c.impls[chain]
+	if !ok {
+		return domain.Prices{}, nil
+	}
+
+	prices, err := impl.GetPricesUSD(ctx, chain, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", chain, impl.GetName(), err)
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,71 @@
+package price_providers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/price_providers"
+	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+)
+
+type byChainSuite struct {
+	suite.Suite
+}
+
+func TestByChain(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(byChainSuite))
+}
+
+func (s *byChainSuite) TestGetPricesUSD_SameAddressOnChains_PricedByChainProvider() {
+	token := domain.Token{Name: "USDC", Address: "0x1"}
+
+	base := mocks.NewPriceProvider(s.T())
+	base.EXPECT().GetPricesUSD(mock.Anything, domain.ChainBase, []domain.Token{token}).
+		Return(domain.Prices{"0x1": 1}, nil).
+		Once()
+
+	polygon := mocks.NewPriceProvider(s.T())
+	polygon.EXPECT().GetPricesUSD(mock.Anything, domain.ChainPolygon, []domain.Token{token}).
+		Return(domain.Prices{"0x1": 0.5}, nil).
+		Once()
+
+	provider := price_providers.NewByChain(map[domain.Chain]domain.PriceProvider{
+		domain.ChainBase:    base,
+		domain.ChainPolygon: polygon,
+	})
+
+	prices, err := provider.GetPricesUSD(context.Background(), domain.ChainPolygon, []domain.Token{token})
+	s.Require().NoError(err)
+	s.Require().Equal(domain.Prices{"0x1": 0.5}, prices)
+
+	prices, err = provider.GetPricesUSD(context.Background(), domain.ChainBase, []domain.Token{token})
+	s.Require().NoError(err)
+	s.Require().Equal(domain.Prices{"0x1": 1}, prices)
+}
+
+func (s *byChainSuite) TestGetPricesUSD_UnknownChain_NotPriced() {
+	provider := price_providers.NewByChain(map[domain.Chain]domain.PriceProvider{})
+
+	prices, err := provider.GetPricesUSD(context.Background(), domain.ChainArbitrum, []domain.Token{{Address: "0x1"}})
+	s.Require().NoError(err)
+	s.Require().Empty(prices)
+}
+
+func (s *byChainSuite) TestGetPricesUSD_ProviderFailed_Error() {
+	errTest := errors.New("test")
+
+	base := mocks.NewPriceProvider(s.T())
+	base.EXPECT().GetName().Return("Test")
+	base.EXPECT().GetPricesUSD(mock.Anything, domain.ChainBase, mock.Anything).Return(nil, errTest).Once()
+
+	provider := price_providers.NewByChain(map[domain.Chain]domain.PriceProvider{domain.ChainBase: base})
+
+	_, err := provider.GetPricesUSD(context.Background(), domain.ChainBase, nil)
+	s.Require().ErrorIs(err, errTest)
+}
@@ -0,0 +1,73 @@
+package static
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// ProviderStatic returns fixed prices, it is handy for tests and for tokens without liquid markets.
+type ProviderStatic struct {
+	prices domain.Prices
+}
+
+func NewProviderStatic(prices domain.Prices) *ProviderStatic {
+	normalized := make(domain.Prices, len(prices))
+	for key, price := range prices {
+		if strings.HasPrefix(key, "0x") {
+			key = strings.ToLower(key)
+		}
+		normalized[key] = price
+	}
+
+	return &ProviderStatic{
+		prices: normalized,
+	}
+}
+
+// NewProviderStaticFromFile reads prices from JSON object keyed by token address or name
+// e.g: {"WETH": 2500, "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913": 1}.
+func NewProviderStaticFromFile(path string) (*ProviderStatic, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var prices domain.Prices
+
+	err = jsoniter.Unmarshal(content, &prices)
+	if err != nil {
+		return nil, fmt.Errorf("jsoniter.Unmarshal: %w", err)
+	}
+
+	return NewProviderStatic(prices), nil
+}
+
+func (*ProviderStatic) GetName() string {
+	return "Static prices"
+}
+
+// GetPricesUSD returns the same prices on all chains.
+func (provider *ProviderStatic) GetPricesUSD(
+	_ context.Context,
+	_ domain.Chain,
+	tokens []domain.Token,
+) (domain.Prices, error) {
+	prices := make(domain.Prices, len(tokens))
+
+	for _, token := range tokens {
+		if price, ok := provider.prices.Get(token); ok {
+			prices[token.Name] = price
+			if token.Address != "" {
+				prices[strings.ToLower(token.Address)] = price
+			}
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,47 @@
+package static_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/price_providers/static"
+)
+
+type providerSuite struct {
+	suite.Suite
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(providerSuite))
+}
+
+func (s *providerSuite) TestGetPricesUSD_FromFile_KnownTokensPriced() {
+	path := filepath.Join(s.T().TempDir(), "prices.json")
+	err := os.WriteFile(path, []byte(`{"WETH": 2500, "0xAAAA": 1}`), 0o600)
+	s.Require().NoError(err)
+
+	provider, err := static.NewProviderStaticFromFile(path)
+	s.Require().NoError(err)
+
+	weth := domain.Token{Name: "WETH", Address: "0x4200"}
+	usdc := domain.Token{Name: "USDC", Address: "0xaaaa"}
+	unknown := domain.Token{Name: "PEPE", Address: "0xbbbb"}
+
+	prices, err := provider.GetPricesUSD(context.Background(), domain.ChainBase, []domain.Token{weth, usdc, unknown})
+	s.Require().NoError(err)
+
+	for token, expected := range map[domain.Token]float64{weth: 2500, usdc: 1} {
+		price, ok := prices.Get(token)
+		s.Require().True(ok)
+		s.Require().InDelta(expected, price, 0)
+	}
+
+	_, ok := prices.Get(unknown)
+	s.Require().False(ok)
+}
@@ -0,0 +1,91 @@
+package thegraph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// ProviderTheGraph prices tokens by Uniswap V3 like subgraph: derivedETH of the token times ETH price in USD.
+// The subgraph indexes the single chain, so the chain of the tokens is not checked.
+type ProviderTheGraph struct {
+	client *graphql.Client
+}
+
+func NewProviderTheGraph(client *graphql.Client) *ProviderTheGraph {
+	return &ProviderTheGraph{
+		client: client,
+	}
+}
+
+func (*ProviderTheGraph) GetName() string {
+	return "The Graph prices"
+}
+
+func (provider *ProviderTheGraph) GetPricesUSD(
+	ctx context.Context,
+	_ domain.Chain,
+	tokens []domain.Token,
+) (domain.Prices, error) {
+	ids := lo.Uniq(lo.FilterMap(tokens, func(token domain.Token, _ int) (graphql.ID, bool) {
+		return graphql.ID(strings.ToLower(token.Address)), token.Address != ""
+	}))
+	if len(ids) == 0 {
+		return domain.Prices{}, nil
+	}
+
+	var prices pricesQuery
+
+	variables := map[string]any{
+		"ids": ids,
+	}
+
+	err := provider.client.Query(ctx, &prices, variables)
+	if err != nil {
+		return nil, fmt.Errorf("graphql.Query: %w", err)
+	}
+
+	return convertToDomain(prices)
+}
+
+func convertToDomain(prices pricesQuery) (domain.Prices, error) {
+	ethPriceUSD, err := strconv.ParseFloat(prices.Bundle.EthPriceUSD, 64)
+	if err != nil {
+		return nil, fmt.Errorf("strconv.ParseFloat: %w", err)
+	}
+
+	result := make(domain.Prices, len(prices.Tokens))
+
+	for _, token := range prices.Tokens {
+		derivedETH, err := strconv.ParseFloat(token.DerivedETH, 64)
+		if err != nil {
+			return nil, fmt.Errorf("strconv.ParseFloat: %w", err)
+		}
+
+		if derivedETH > 0 {
+			result[strings.ToLower(token.ID)] = derivedETH * ethPriceUSD
+		}
+	}
+
+	return result, nil
+}
+
+type pricesQuery struct {
+	Bundle bundle  `graphql:"bundle(id: \"1\")"`
+	Tokens []token `graphql:"tokens(where: {id_in: $ids})"`
+}
+
+type bundle struct {
+	EthPriceUSD string
+}
+
+type token struct {
+	ID         string
+	DerivedETH string
+}
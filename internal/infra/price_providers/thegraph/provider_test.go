@@ -0,0 +1,65 @@
+package thegraph_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/price_providers/thegraph"
+)
+
+type providerSuite struct {
+	suite.Suite
+}
+
+func TestProvider(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(providerSuite))
+}
+
+func (s *providerSuite) TestGetPricesUSD_DerivedETH_PricedByBundle() {
+	var query string
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		query = string(body)
+
+		_, _ = io.WriteString(writer, `{"data": {
+			"bundle": {"ethPriceUSD": "2500"},
+			"tokens": [
+				{"id": "0x4200", "derivedETH": "1"},
+				{"id": "0xaaaa", "derivedETH": "0.0004"},
+				{"id": "0xbbbb", "derivedETH": "0"}
+			]
+		}}`)
+	}))
+	defer server.Close()
+
+	provider := thegraph.NewProviderTheGraph(graphql.NewClient(server.URL, server.Client()))
+
+	prices, err := provider.GetPricesUSD(context.Background(), domain.ChainBase, []domain.Token{
+		{Name: "WETH", Address: "0x4200"},
+		{Name: "USDC", Address: "0xAAAA"},
+		{Name: "DEAD", Address: "0xbbbb"},
+	})
+	s.Require().NoError(err)
+
+	s.Require().Contains(query, "0xaaaa")
+	s.Require().Len(prices, 2)
+	s.Require().InDelta(2500, prices["0x4200"], 1e-9)
+	s.Require().InDelta(1, prices["0xaaaa"], 1e-9)
+}
+
+func (s *providerSuite) TestGetPricesUSD_NoAddresses_NoQuery() {
+	provider := thegraph.NewProviderTheGraph(graphql.NewClient("http://unreachable", nil))
+
+	prices, err := provider.GetPricesUSD(context.Background(), domain.ChainBase, []domain.Token{{Name: "WETH"}})
+	s.Require().NoError(err)
+	s.Require().Empty(prices)
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+type dashboardModel struct {
+	TelegramUserID int64 `db:"telegram_user_id"`
+	MessageID      int   `db:"message_id"`
+}
+
+func newDashboardModel(dashboard domain.Dashboard) dashboardModel {
+	return dashboardModel{
+		TelegramUserID: dashboard.TelegramUserID,
+		MessageID:      dashboard.MessageID,
+	}
+}
+
+func (model dashboardModel) toDashboard() domain.Dashboard {
+	return domain.Dashboard{
+		TelegramUserID: model.TelegramUserID,
+		MessageID:      model.MessageID,
+	}
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// Executor allows repository work with sqlx.DB and sqlx.Tx as driver.
+//
+//nolint:revive // Unnecessary comments for technical interfaces.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+type DashboardsRepository struct {
+	db Executor
+}
+
+func NewDashboardsRepository(db Executor) *DashboardsRepository {
+	return &DashboardsRepository{
+		db: db,
+	}
+}
+
+func (p DashboardsRepository) Add(ctx context.Context, dashboard domain.Dashboard) error {
+	model := newDashboardModel(dashboard)
+
+	_, err := p.db.ExecContext(ctx, queryAddDashboard, model.TelegramUserID, model.MessageID)
+	if err != nil {
+		return fmt.Errorf("ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (p DashboardsRepository) Get(ctx context.Context, telegramUserID int64) (domain.Dashboard, error) {
+	var models []dashboardModel
+
+	err := p.db.SelectContext(ctx, &models, queryGetDashboard, telegramUserID)
+	if err != nil {
+		return domain.Dashboard{}, fmt.Errorf("SelectContext: %w", err)
+	}
+
+	if len(models) == 0 {
+		return domain.Dashboard{}, domain.ErrDashboardNotFound
+	}
+
+	return models[0].toDashboard(), nil
+}
+
+const queryGetDashboard = `
+SELECT 
+    telegram_user_id, 
+    message_id 
+FROM 
+    dashboards
+WHERE 
+    telegram_user_id = $1
+`
+
+const queryAddDashboard = `
+INSERT INTO 
+    dashboards (telegram_user_id, message_id)
+VALUES 
+    ($1, $2)
+ON CONFLICT 
+    (telegram_user_id)
+DO UPDATE SET 
+	message_id=EXCLUDED.message_id
+`
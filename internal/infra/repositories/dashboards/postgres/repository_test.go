@@ -0,0 +1,76 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	pg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/dashboards/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+	"github.com/DanilaKorobkov/defi-monitoring/test/postgres"
+)
+
+type repositorySuite struct {
+	suite.Suite
+
+	db         *sqlx.DB
+	tx         *sqlx.Tx
+	dashboards *pg.DashboardsRepository
+}
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(repositorySuite))
+}
+
+func (s *repositorySuite) SetupSuite() {
+	db, err := postgres.Connect()
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *repositorySuite) SetupTest() {
+	tx, err := s.db.Beginx()
+	s.Require().NoError(err)
+	s.tx = tx
+
+	s.dashboards = pg.NewDashboardsRepository(tx)
+}
+
+func (s *repositorySuite) TearDownTest() {
+	err := s.tx.Rollback()
+	s.Require().NoError(err)
+}
+
+func (s *repositorySuite) TestAdd_AlreadyExists_Override() {
+	ctx := context.Background()
+
+	dashboard := domain.Dashboard{
+		TelegramUserID: generators.NewSubjectGenerator().Slim().Result().TelegramUserID,
+		MessageID:      generators.RandomInt(1, 1000),
+	}
+
+	err := s.dashboards.Add(ctx, dashboard)
+	s.Require().NoError(err)
+
+	dashboard.MessageID++
+
+	err = s.dashboards.Add(ctx, dashboard)
+	s.Require().NoError(err)
+
+	stored, err := s.dashboards.Get(ctx, dashboard.TelegramUserID)
+	s.Require().NoError(err)
+	s.Require().Equal(dashboard, stored)
+}
+
+func (s *repositorySuite) TestGet_NotFound() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	_, err := s.dashboards.Get(ctx, subject.TelegramUserID)
+	s.Require().ErrorIs(err, domain.ErrDashboardNotFound)
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"fmt"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+type scanModel struct {
+	Chain     string `db:"chain"`
+	Wallet    string `db:"wallet"`
+	NextBlock int64  `db:"next_block"`
+	Gauges    string `db:"gauges"`
+}
+
+func newScanModel(scan domain.GaugesScan) (scanModel, error) {
+	gauges, err := jsoniter.MarshalToString(scan.Gauges)
+	if err != nil {
+		return scanModel{}, fmt.Errorf("jsoniter.MarshalToString: %w", err)
+	}
+
+	return scanModel{
+		Chain:     string(scan.Chain),
+		Wallet:    scan.Wallet,
+		NextBlock: int64(scan.NextBlock), //nolint:gosec // Block numbers are far below the limit.
+		Gauges:    gauges,
+	}, nil
+}
+
+func (model scanModel) toScan() (domain.GaugesScan, error) {
+	var gauges []string
+
+	err := jsoniter.UnmarshalFromString(model.Gauges, &gauges)
+	if err != nil {
+		return domain.GaugesScan{}, fmt.Errorf("jsoniter.UnmarshalFromString: %w", err)
+	}
+
+	return domain.GaugesScan{
+		Chain:     domain.Chain(model.Chain),
+		Wallet:    model.Wallet,
+		NextBlock: uint64(model.NextBlock), //nolint:gosec // Stored block numbers are not negative.
+		Gauges:    gauges,
+	}, nil
+}
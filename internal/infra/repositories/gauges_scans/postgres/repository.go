@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// Executor allows repository work with sqlx.DB and sqlx.Tx as driver.
+//
+//nolint:revive // Unnecessary comments for technical interfaces.
+type Executor interface {
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+type GaugesScansRepository struct {
+	db Executor
+}
+
+func NewGaugesScansRepository(db Executor) *GaugesScansRepository {
+	return &GaugesScansRepository{
+		db: db,
+	}
+}
+
+func (p GaugesScansRepository) Add(ctx context.Context, scan domain.GaugesScan) error {
+	model, err := newScanModel(scan)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.NamedExecContext(ctx, queryAddScan, model)
+	if err != nil {
+		return fmt.Errorf("NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (p GaugesScansRepository) Get(ctx context.Context, chain domain.Chain, wallet string) (domain.GaugesScan, error) {
+	var models []scanModel
+
+	err := p.db.SelectContext(ctx, &models, queryGetScan, chain, wallet)
+	if err != nil {
+		return domain.GaugesScan{}, fmt.Errorf("SelectContext: %w", err)
+	}
+
+	if len(models) == 0 {
+		return domain.GaugesScan{}, domain.ErrGaugesScanNotFound
+	}
+
+	return models[0].toScan()
+}
+
+const queryGetScan = `
+SELECT 
+    chain, 
+    wallet, 
+    next_block, 
+    gauges 
+FROM 
+    gauges_scans
+WHERE 
+    chain = $1 AND wallet = $2
+`
+
+const queryAddScan = `
+INSERT INTO 
+    gauges_scans (chain, wallet, next_block, gauges)
+VALUES 
+    (:chain, :wallet, :next_block, :gauges)
+ON CONFLICT 
+    (chain, wallet)
+DO UPDATE SET 
+    next_block = EXCLUDED.next_block,
+    gauges = EXCLUDED.gauges
+`
@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	pg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/gauges_scans/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+	"github.com/DanilaKorobkov/defi-monitoring/test/postgres"
+)
+
+type repositorySuite struct {
+	suite.Suite
+
+	db    *sqlx.DB
+	tx    *sqlx.Tx
+	scans *pg.GaugesScansRepository
+}
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(repositorySuite))
+}
+
+func (s *repositorySuite) SetupSuite() {
+	db, err := postgres.Connect()
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *repositorySuite) SetupTest() {
+	tx, err := s.db.Beginx()
+	s.Require().NoError(err)
+	s.tx = tx
+
+	s.scans = pg.NewGaugesScansRepository(tx)
+}
+
+func (s *repositorySuite) TearDownTest() {
+	err := s.tx.Rollback()
+	s.Require().NoError(err)
+}
+
+func (s *repositorySuite) TestAdd_AlreadyExists_Override() {
+	ctx := context.Background()
+
+	scan := domain.GaugesScan{
+		Chain:     domain.ChainBase,
+		Wallet:    strings.ToLower(generators.NewSubjectGenerator().Result().Wallets[0]),
+		NextBlock: 12_000_000,
+		Gauges:    []string{"0x8888888888888888888888888888888888888888"},
+	}
+
+	err := s.scans.Add(ctx, scan)
+	s.Require().NoError(err)
+
+	scan.NextBlock += 500_000
+	scan.Gauges = append(scan.Gauges, "0x9999999999999999999999999999999999999999")
+
+	err = s.scans.Add(ctx, scan)
+	s.Require().NoError(err)
+
+	stored, err := s.scans.Get(ctx, scan.Chain, scan.Wallet)
+	s.Require().NoError(err)
+	s.Require().Equal(scan, stored)
+}
+
+func (s *repositorySuite) TestGet_OtherChain_NotFound() {
+	ctx := context.Background()
+
+	scan := domain.GaugesScan{
+		Chain:     domain.ChainBase,
+		Wallet:    strings.ToLower(generators.NewSubjectGenerator().Result().Wallets[0]),
+		NextBlock: 12_000_000,
+		Gauges:    []string{},
+	}
+
+	err := s.scans.Add(ctx, scan)
+	s.Require().NoError(err)
+
+	_, err = s.scans.Get(ctx, domain.ChainArbitrum, scan.Wallet)
+	s.Require().ErrorIs(err, domain.ErrGaugesScanNotFound)
+}
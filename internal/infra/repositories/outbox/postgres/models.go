@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+type messageModel struct {
+	ID             int64     `db:"id"`
+	TelegramUserID int64     `db:"telegram_user_id"`
+	Status         string    `db:"status"`
+	Attempts       int       `db:"attempts"`
+	LastError      string    `db:"last_error"`
+	CreatedAt      time.Time `db:"created_at"`
+	NextAttemptAt  time.Time `db:"next_attempt_at"`
+	Payload        string    `db:"payload"`
+}
+
+// messagePayloadModel stores events and positions as is, messages live until delivered, so they are not
+// expected to survive changes of the entities. The subject is referenced by the row, channel targets
+// and secrets are loaded on delivery.
+type messagePayloadModel struct {
+	Deliveries []deliveryPayload              `db:"deliveries"`
+	Events     []domain.PositionEvent         `db:"events"`
+	Positions  []domain.LiquidityPoolPosition `db:"positions"`
+}
+
+type deliveryPayload struct {
+	Channel   int `db:"channel"`
+	SentParts int `db:"sent_parts"`
+}
+
+func newMessageModel(message domain.OutboxMessage) (messageModel, error) {
+	dump, err := jsoniter.MarshalToString(messagePayloadModel{
+		Deliveries: lo.Map(message.Deliveries, func(delivery domain.OutboxDelivery, _ int) deliveryPayload {
+			return deliveryPayload(delivery)
+		}),
+		Events:    message.Events,
+		Positions: message.Positions,
+	})
+	if err != nil {
+		return messageModel{}, fmt.Errorf("jsoniter.MarshalToString: %w", err)
+	}
+
+	model := messageModel{
+		ID:             message.ID,
+		TelegramUserID: message.TelegramUserID,
+		Status:         string(message.Status),
+		Attempts:       message.Attempts,
+		LastError:      message.LastError,
+		CreatedAt:      message.CreatedAt,
+		NextAttemptAt:  message.NextAttemptAt,
+		Payload:        dump,
+	}
+
+	return model, nil
+}
+
+// toMessage returns the message without the payload if it can not be decoded, so broken messages
+// are still listed.
+func (model messageModel) toMessage() (domain.OutboxMessage, error) {
+	message := domain.OutboxMessage{
+		ID:             model.ID,
+		TelegramUserID: model.TelegramUserID,
+		Status:         domain.OutboxStatus(model.Status),
+		Attempts:       model.Attempts,
+		LastError:      model.LastError,
+		CreatedAt:      model.CreatedAt,
+		NextAttemptAt:  model.NextAttemptAt,
+	}
+
+	payloadModel := messagePayloadModel{}
+
+	err := jsoniter.UnmarshalFromString(model.Payload, &payloadModel)
+	if err != nil {
+		return message, fmt.Errorf("jsoniter.UnmarshalFromString: %w", err)
+	}
+
+	message.Deliveries = lo.Map(payloadModel.Deliveries, func(delivery deliveryPayload, _ int) domain.OutboxDelivery {
+		return domain.OutboxDelivery(delivery)
+	})
+	message.Events = payloadModel.Events
+	message.Positions = payloadModel.Positions
+
+	return message, nil
+}
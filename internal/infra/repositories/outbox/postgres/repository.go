@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	historypg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/positions_history/postgres"
+)
+
+// DB allows repository work with sqlx.DB as driver, transactions are opened by the repository itself.
+//
+//nolint:revive // Unnecessary comments for technical interfaces.
+type DB interface {
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+type OutboxRepository struct {
+	db DB
+}
+
+func NewOutboxRepository(db DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+func (p OutboxRepository) Add(
+	ctx context.Context,
+	snapshots []domain.PositionSnapshot,
+	messages []domain.OutboxMessage,
+) error {
+	models := make([]messageModel, 0, len(messages))
+	for _, message := range messages {
+		model, err := newMessageModel(message)
+		if err != nil {
+			return err
+		}
+		models = append(models, model)
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("BeginTxx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // Fails after commit, nothing to do with the error.
+
+	err = historypg.NewPositionsHistoryRepository(tx).Add(ctx, snapshots)
+	if err != nil {
+		return fmt.Errorf("PositionsHistoryRepository.Add: %w", err)
+	}
+
+	if len(models) > 0 {
+		_, err = tx.NamedExecContext(ctx, queryAddMessages, models)
+		if err != nil {
+			return fmt.Errorf("NamedExecContext: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Claim dead-letters claimed messages which payload can not be decoded, so they are not claimed again.
+func (p OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	var models []messageModel
+
+	now := time.Now()
+
+	err := p.db.SelectContext(ctx, &models, queryClaimMessages, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("SelectContext: %w", err)
+	}
+
+	var messages []domain.OutboxMessage
+	for _, model := range models {
+		message, err := model.toMessage()
+		if err == nil {
+			messages = append(messages, message)
+			continue
+		}
+
+		err = p.bury(ctx, model.ID, err)
+		if err != nil {
+			return nil, fmt.Errorf("bury: %w", err)
+		}
+	}
+
+	return messages, nil
+}
+
+func (p OutboxRepository) GetByStatus(
+	ctx context.Context,
+	status domain.OutboxStatus,
+	limit int,
+) ([]domain.OutboxMessage, error) {
+	var models []messageModel
+
+	err := p.db.SelectContext(ctx, &models, queryGetMessagesByStatus, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("SelectContext: %w", err)
+	}
+
+	return toMessages(models), nil
+}
+
+func (p OutboxRepository) Update(ctx context.Context, message domain.OutboxMessage) error {
+	model, err := newMessageModel(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.NamedExecContext(ctx, queryUpdateMessage, model)
+	if err != nil {
+		return fmt.Errorf("NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// bury dead-letters the message which can not be delivered at all.
+func (p OutboxRepository) bury(ctx context.Context, id int64, reason error) error {
+	model := messageModel{
+		ID:        id,
+		Status:    string(domain.OutboxStatusDead),
+		LastError: reason.Error(),
+	}
+
+	_, err := p.db.NamedExecContext(ctx, queryBuryMessage, model)
+	if err != nil {
+		return fmt.Errorf("NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+// toMessages keeps messages which payload can not be decoded without it.
+func toMessages(models []messageModel) []domain.OutboxMessage {
+	if len(models) == 0 {
+		return nil
+	}
+
+	return lo.Map(models, func(item messageModel, _ int) domain.OutboxMessage {
+		message, _ := item.toMessage()
+		return message
+	})
+}
+
+const queryAddMessages = `
+INSERT INTO 
+    outbox (telegram_user_id, status, attempts, last_error, created_at, next_attempt_at, payload)
+VALUES 
+    (:telegram_user_id, :status, :attempts, :last_error, :created_at, :next_attempt_at, :payload)
+`
+
+const queryClaimMessages = `
+UPDATE 
+    outbox
+SET 
+    next_attempt_at = $2
+WHERE 
+    id IN (
+        SELECT 
+            id 
+        FROM 
+            outbox 
+        WHERE 
+            status = 'pending' AND next_attempt_at <= $1
+        ORDER BY 
+            next_attempt_at
+        LIMIT 
+            $3
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING 
+    id, telegram_user_id, status, attempts, last_error, created_at, next_attempt_at, payload
+`
+
+const queryUpdateMessage = `
+UPDATE 
+    outbox
+SET 
+    status = :status, 
+    attempts = :attempts, 
+    last_error = :last_error, 
+    next_attempt_at = :next_attempt_at, 
+    payload = :payload
+WHERE 
+    id = :id
+`
+
+const queryBuryMessage = `
+UPDATE 
+    outbox
+SET 
+    status = :status, 
+    last_error = :last_error
+WHERE 
+    id = :id
+`
+
+const queryGetMessagesByStatus = `
+SELECT 
+    id, telegram_user_id, status, attempts, last_error, created_at, next_attempt_at, payload
+FROM 
+    outbox
+WHERE 
+    status = $1
+ORDER BY 
+    created_at DESC
+LIMIT 
+    $2
+`
@@ -0,0 +1,186 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	pg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/outbox/postgres"
+	historypg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/positions_history/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+	"github.com/DanilaKorobkov/defi-monitoring/test/postgres"
+)
+
+// repositorySuite commits since the repository opens transactions itself, rows of the subject
+// and the position used by the test are removed after it.
+type repositorySuite struct {
+	suite.Suite
+
+	db       *sqlx.DB
+	outbox   *pg.OutboxRepository
+	subject  domain.Subject
+	position domain.LiquidityPoolPosition
+}
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(repositorySuite))
+}
+
+func (s *repositorySuite) SetupSuite() {
+	db, err := postgres.Connect()
+	s.Require().NoError(err)
+	s.db = db
+	s.outbox = pg.NewOutboxRepository(db)
+}
+
+func (s *repositorySuite) SetupTest() {
+	s.subject = generators.NewSubjectGenerator().Slim().Result()
+	s.position = generators.NewPositionGenerator().Slim().Result()
+}
+
+func (s *repositorySuite) TearDownTest() {
+	_, err := s.db.Exec(`DELETE FROM outbox WHERE telegram_user_id = $1`, s.subject.TelegramUserID)
+	s.Require().NoError(err)
+
+	_, err = s.db.Exec(`DELETE FROM positions_history WHERE position_id = $1`, s.position.ID)
+	s.Require().NoError(err)
+}
+
+func (s *repositorySuite) TestAdd_SnapshotsAndMessages_Saved() {
+	ctx := context.Background()
+
+	snapshot := domain.PositionSnapshot{Position: s.position, CheckedAt: time.Now()}
+	message := domain.NewOutboxMessage(s.subject, []domain.PositionEvent{{
+		Kind:     domain.PositionEventLeftRange,
+		Position: s.position,
+	}}, nil)
+
+	err := s.outbox.Add(ctx, []domain.PositionSnapshot{snapshot}, []domain.OutboxMessage{message})
+	s.Require().NoError(err)
+
+	timeline, err := historypg.NewPositionsHistoryRepository(s.db).
+		GetTimeline(ctx, s.position.GetKey(), time.Time{}, time.Now())
+	s.Require().NoError(err)
+	s.Require().Len(timeline, 1)
+
+	pending := s.getSubjectMessages(ctx, domain.OutboxStatusPending)
+	s.Require().Len(pending, 1)
+	s.Require().Equal(s.subject.TelegramUserID, pending[0].TelegramUserID)
+	s.Require().Equal(message.Deliveries, pending[0].Deliveries)
+	s.Require().Equal(domain.PositionEventLeftRange, pending[0].Events[0].Kind)
+}
+
+func (s *repositorySuite) TestUpdate_SentParts_Saved() {
+	ctx := context.Background()
+
+	s.subject.Channels = []domain.NotificationChannel{
+		domain.NewTelegramChannel(s.subject.TelegramUserID),
+		{Kind: domain.ChannelWebhook, Target: "https://example.com/hook", Secret: "secret"},
+	}
+	message := domain.NewOutboxMessage(s.subject, nil, []domain.LiquidityPoolPosition{s.position})
+	err := s.outbox.Add(ctx, nil, []domain.OutboxMessage{message})
+	s.Require().NoError(err)
+
+	pending := s.getSubjectMessages(ctx, domain.OutboxStatusPending)
+	s.Require().Len(pending, 1)
+
+	retried := pending[0]
+	retried.Deliveries = []domain.OutboxDelivery{{Channel: 0, SentParts: 2}}
+
+	err = s.outbox.Update(ctx, retried)
+	s.Require().NoError(err)
+
+	actual := s.getSubjectMessages(ctx, domain.OutboxStatusPending)
+	s.Require().Len(actual, 1)
+	s.Require().Equal(retried.Deliveries, actual[0].Deliveries)
+
+	var payload string
+	err = s.db.Get(&payload, `SELECT payload FROM outbox WHERE id = $1`, retried.ID)
+	s.Require().NoError(err)
+	s.Require().NotContains(payload, "secret")
+	s.Require().NotContains(payload, "https://example.com/hook")
+}
+
+func (s *repositorySuite) TestClaim_BrokenPayload_DeadLettered() {
+	ctx := context.Background()
+
+	_, err := s.db.Exec(`
+		INSERT INTO outbox (telegram_user_id, status, attempts, last_error, created_at, next_attempt_at, payload)
+		VALUES ($1, 'pending', 0, '', NOW(), NOW(), '{"deliveries": "broken"}')`,
+		s.subject.TelegramUserID,
+	)
+	s.Require().NoError(err)
+
+	claimed, err := s.outbox.Claim(ctx, 100, time.Hour)
+	s.Require().NoError(err)
+	s.Require().Empty(lo.Filter(claimed, func(item domain.OutboxMessage, _ int) bool {
+		return item.TelegramUserID == s.subject.TelegramUserID
+	}))
+
+	dead := s.getSubjectMessages(ctx, domain.OutboxStatusDead)
+	s.Require().Len(dead, 1)
+	s.Require().Contains(dead[0].LastError, "jsoniter.UnmarshalFromString")
+}
+
+func (s *repositorySuite) TestClaim_Due_Postponed() {
+	ctx := context.Background()
+
+	message := domain.NewOutboxMessage(s.subject, nil, []domain.LiquidityPoolPosition{s.position})
+	err := s.outbox.Add(ctx, nil, []domain.OutboxMessage{message})
+	s.Require().NoError(err)
+
+	claimed, err := s.outbox.Claim(ctx, 100, time.Hour)
+	s.Require().NoError(err)
+
+	claimed = lo.Filter(claimed, func(item domain.OutboxMessage, _ int) bool {
+		return item.TelegramUserID == s.subject.TelegramUserID
+	})
+	s.Require().Len(claimed, 1)
+	s.Require().WithinDuration(time.Now().Add(time.Hour), claimed[0].NextAttemptAt, time.Minute)
+
+	claimedAgain, err := s.outbox.Claim(ctx, 100, time.Hour)
+	s.Require().NoError(err)
+	s.Require().NotContains(lo.Map(claimedAgain, func(item domain.OutboxMessage, _ int) int64 {
+		return item.ID
+	}), claimed[0].ID)
+}
+
+func (s *repositorySuite) TestUpdate_Dead_GetByStatus() {
+	ctx := context.Background()
+
+	message := domain.NewOutboxMessage(s.subject, nil, []domain.LiquidityPoolPosition{s.position})
+	err := s.outbox.Add(ctx, nil, []domain.OutboxMessage{message})
+	s.Require().NoError(err)
+
+	pending := s.getSubjectMessages(ctx, domain.OutboxStatusPending)
+	s.Require().Len(pending, 1)
+
+	dead := pending[0]
+	dead.Status = domain.OutboxStatusDead
+	dead.Attempts = 5
+	dead.LastError = "telegram.Send: Forbidden"
+
+	err = s.outbox.Update(ctx, dead)
+	s.Require().NoError(err)
+
+	actual := s.getSubjectMessages(ctx, domain.OutboxStatusDead)
+	s.Require().Len(actual, 1)
+	s.Require().Equal(5, actual[0].Attempts)
+	s.Require().Equal("telegram.Send: Forbidden", actual[0].LastError)
+	s.Require().Empty(s.getSubjectMessages(ctx, domain.OutboxStatusPending))
+}
+
+func (s *repositorySuite) getSubjectMessages(ctx context.Context, status domain.OutboxStatus) []domain.OutboxMessage {
+	messages, err := s.outbox.GetByStatus(ctx, status, 1000)
+	s.Require().NoError(err)
+
+	return lo.Filter(messages, func(item domain.OutboxMessage, _ int) bool {
+		return item.TelegramUserID == s.subject.TelegramUserID
+	})
+}
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+type snapshotModel struct {
+	Chain        string    `db:"chain"`
+	Dex          string    `db:"dex"`
+	PositionID   string    `db:"position_id"`
+	Wallet       string    `db:"wallet"`
+	Token0       string    `db:"token0"`
+	Token1       string    `db:"token1"`
+	CheckedAt    time.Time `db:"checked_at"`
+	InRange      bool      `db:"in_range"`
+	TickLower    int       `db:"tick_lower"`
+	TickUpper    int       `db:"tick_upper"`
+	CurrentTick  int       `db:"current_tick"`
+	CurrentPrice float64   `db:"current_price"`
+	Payload      string    `db:"payload"`
+}
+
+func newSnapshotModel(snapshot domain.PositionSnapshot) (snapshotModel, error) {
+	position := snapshot.Position
+
+	dump, err := jsoniter.MarshalToString(newPositionPayloadModel(position))
+	if err != nil {
+		return snapshotModel{}, fmt.Errorf("jsoniter.MarshalToString: %w", err)
+	}
+
+	model := snapshotModel{
+		Chain:        string(position.Chain),
+		Dex:          string(position.Dex),
+		PositionID:   position.ID,
+		Wallet:       position.Wallet,
+		Token0:       position.Token0.Name,
+		Token1:       position.Token1.Name,
+		CheckedAt:    snapshot.CheckedAt,
+		InRange:      position.IsInRange(),
+		TickLower:    position.TickLower,
+		TickUpper:    position.TickUpper,
+		CurrentTick:  position.CurrentTick,
+		CurrentPrice: position.GetCurrentPrice(),
+		Payload:      dump,
+	}
+
+	return model, nil
+}
+
+func (model snapshotModel) mustToSnapshot() domain.PositionSnapshot {
+	payloadModel := positionPayloadModel{}
+
+	err := jsoniter.UnmarshalFromString(model.Payload, &payloadModel)
+	if err != nil {
+		message := "mustToSnapshot: jsoniter.UnmarshalFromString: " + err.Error()
+		panic(message)
+	}
+
+	return domain.PositionSnapshot{
+		Position:  payloadModel.toPosition(),
+		CheckedAt: model.CheckedAt.UTC(),
+	}
+}
+
+type positionPayloadModel struct {
+	ID           string       `db:"id"`
+	Wallet       string       `db:"wallet"`
+	Chain        string       `db:"chain"`
+	Dex          string       `db:"dex"`
+	PositionLink string       `db:"position_link"`
+	Token0       tokenPayload `db:"token0"`
+	Token1       tokenPayload `db:"token1"`
+	CurrentTick  int          `db:"current_tick"`
+	TickLower    int          `db:"tick_lower"`
+	TickUpper    int          `db:"tick_upper"`
+	Liquidity    *big.Int     `db:"liquidity"`
+	SqrtPriceX96 *big.Int     `db:"sqrt_price_x96"`
+
+	UncollectedFees0 *big.Int `db:"uncollected_fees0"`
+	UncollectedFees1 *big.Int `db:"uncollected_fees1"`
+
+	Deposits *depositsPayload `db:"deposits"`
+
+	Staked    bool              `db:"staked"`
+	Emissions *emissionsPayload `db:"emissions"`
+
+	Pool        string `db:"pool"`
+	NFTContract string `db:"nft_contract"`
+	NFTLink     string `db:"nft_link"`
+	PoolLink    string `db:"pool_link"`
+}
+
+type tokenPayload struct {
+	Name     string  `db:"name"`
+	Decimals int     `db:"decimals"`
+	Address  string  `db:"address"`
+	PriceUSD float64 `db:"price_usd"`
+}
+
+type depositsPayload struct {
+	Deposited0     float64   `db:"deposited0"`
+	Deposited1     float64   `db:"deposited1"`
+	Withdrawn0     float64   `db:"withdrawn0"`
+	Withdrawn1     float64   `db:"withdrawn1"`
+	CollectedFees0 float64   `db:"collected_fees0"`
+	CollectedFees1 float64   `db:"collected_fees1"`
+	OpenedAt       time.Time `db:"opened_at"`
+}
+
+type emissionsPayload struct {
+	Token   tokenPayload `db:"token"`
+	Pending *big.Int     `db:"pending"`
+}
+
+func newPositionPayloadModel(position domain.LiquidityPoolPosition) positionPayloadModel {
+	return positionPayloadModel{
+		ID:           position.ID,
+		Wallet:       position.Wallet,
+		Chain:        string(position.Chain),
+		Dex:          string(position.Dex),
+		PositionLink: position.PositionLink,
+		Token0:       tokenPayload(position.Token0),
+		Token1:       tokenPayload(position.Token1),
+		CurrentTick:  position.CurrentTick,
+		TickLower:    position.TickLower,
+		TickUpper:    position.TickUpper,
+		Liquidity:    position.Liquidity,
+		SqrtPriceX96: position.SqrtPriceX96,
+
+		UncollectedFees0: position.UncollectedFees0,
+		UncollectedFees1: position.UncollectedFees1,
+
+		Deposits: (*depositsPayload)(position.Deposits),
+
+		Staked:    position.Staked,
+		Emissions: newEmissionsPayload(position.Emissions),
+
+		Pool:        position.Pool,
+		NFTContract: position.NFTContract,
+		NFTLink:     position.NFTLink,
+		PoolLink:    position.PoolLink,
+	}
+}
+
+func (model positionPayloadModel) toPosition() domain.LiquidityPoolPosition {
+	return domain.LiquidityPoolPosition{
+		ID:           model.ID,
+		Wallet:       model.Wallet,
+		Chain:        domain.Chain(model.Chain),
+		Dex:          domain.Dex(model.Dex),
+		PositionLink: model.PositionLink,
+		Token0:       domain.Token(model.Token0),
+		Token1:       domain.Token(model.Token1),
+		CurrentTick:  model.CurrentTick,
+		TickLower:    model.TickLower,
+		TickUpper:    model.TickUpper,
+		Liquidity:    model.Liquidity,
+		SqrtPriceX96: model.SqrtPriceX96,
+
+		UncollectedFees0: model.UncollectedFees0,
+		UncollectedFees1: model.UncollectedFees1,
+
+		Deposits: (*domain.PositionDeposits)(model.Deposits),
+
+		Staked:    model.Staked,
+		Emissions: model.Emissions.toEmissions(),
+
+		Pool:        model.Pool,
+		NFTContract: model.NFTContract,
+		NFTLink:     model.NFTLink,
+		PoolLink:    model.PoolLink,
+	}
+}
+
+func newEmissionsPayload(emissions *domain.PositionEmissions) *emissionsPayload {
+	if emissions == nil {
+		return nil
+	}
+
+	return &emissionsPayload{
+		Token:   tokenPayload(emissions.Token),
+		Pending: emissions.Pending,
+	}
+}
+
+func (payload *emissionsPayload) toEmissions() *domain.PositionEmissions {
+	if payload == nil {
+		return nil
+	}
+
+	return &domain.PositionEmissions{
+		Token:   domain.Token(payload.Token),
+		Pending: payload.Pending,
+	}
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+// Executor allows repository work with sqlx.DB and sqlx.Tx as driver.
+//
+//nolint:revive // Unnecessary comments for technical interfaces.
+type Executor interface {
+	NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error)
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+type PositionsHistoryRepository struct {
+	db Executor
+}
+
+func NewPositionsHistoryRepository(db Executor) *PositionsHistoryRepository {
+	return &PositionsHistoryRepository{
+		db: db,
+	}
+}
+
+func (p PositionsHistoryRepository) Add(ctx context.Context, snapshots []domain.PositionSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	models := make([]snapshotModel, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		model, err := newSnapshotModel(snapshot)
+		if err != nil {
+			return err
+		}
+		models = append(models, model)
+	}
+
+	_, err := p.db.NamedExecContext(ctx, queryAddSnapshots, models)
+	if err != nil {
+		return fmt.Errorf("NamedExecContext: %w", err)
+	}
+
+	return nil
+}
+
+func (p PositionsHistoryRepository) GetTimeline(
+	ctx context.Context,
+	key domain.PositionKey,
+	from, to time.Time,
+) (domain.PositionTimeline, error) {
+	var models []snapshotModel
+
+	err := p.db.SelectContext(ctx, &models, queryGetTimeline, key.Chain, key.Dex, key.ID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("SelectContext: %w", err)
+	}
+
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	timeline := lo.Map(models, func(item snapshotModel, _ int) domain.PositionSnapshot {
+		return item.mustToSnapshot()
+	})
+
+	return timeline, nil
+}
+
+const queryAddSnapshots = `
+INSERT INTO 
+    positions_history (
+        chain, dex, position_id, wallet, token0, token1, checked_at, 
+        in_range, tick_lower, tick_upper, current_tick, current_price, payload
+    )
+VALUES 
+    (
+        :chain, :dex, :position_id, :wallet, :token0, :token1, :checked_at, 
+        :in_range, :tick_lower, :tick_upper, :current_tick, :current_price, :payload
+    )
+`
+
+const queryGetTimeline = `
+SELECT 
+    chain, dex, position_id, wallet, token0, token1, checked_at, 
+    in_range, tick_lower, tick_upper, current_tick, current_price, payload 
+FROM 
+    positions_history
+WHERE 
+    chain = $1 AND dex = $2 AND position_id = $3 AND checked_at >= $4 AND checked_at < $5
+ORDER BY 
+    checked_at
+`
@@ -0,0 +1,101 @@
+package postgres_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	pg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/positions_history/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+	"github.com/DanilaKorobkov/defi-monitoring/test/postgres"
+)
+
+type repositorySuite struct {
+	suite.Suite
+
+	db      *sqlx.DB
+	tx      *sqlx.Tx
+	history *pg.PositionsHistoryRepository
+}
+
+func TestRepository(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(repositorySuite))
+}
+
+func (s *repositorySuite) SetupSuite() {
+	db, err := postgres.Connect()
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *repositorySuite) SetupTest() {
+	tx, err := s.db.Beginx()
+	s.Require().NoError(err)
+	s.tx = tx
+
+	s.history = pg.NewPositionsHistoryRepository(tx)
+}
+
+func (s *repositorySuite) TearDownTest() {
+	err := s.tx.Rollback()
+	s.Require().NoError(err)
+}
+
+func (s *repositorySuite) TestGetTimeline_Empty() {
+	ctx := context.Background()
+
+	position := generators.NewPositionGenerator().Slim().Result()
+
+	timeline, err := s.history.GetTimeline(ctx, position.GetKey(), time.Time{}, time.Now())
+
+	s.Require().NoError(err)
+	s.Require().Nil(timeline)
+}
+
+func (s *repositorySuite) TestGetTimeline_WithinPeriod_OrderedByCheckTime() {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	position := generators.NewPositionGenerator().Slim().Result()
+	another := generators.NewPositionGenerator().Slim().Result()
+
+	before := domain.PositionSnapshot{Position: position, CheckedAt: now.Add(-2 * time.Hour)}
+	first := domain.PositionSnapshot{Position: position, CheckedAt: now.Add(-time.Hour)}
+	second := domain.PositionSnapshot{Position: position, CheckedAt: now}
+	anotherPosition := domain.PositionSnapshot{Position: another, CheckedAt: now}
+
+	err := s.history.Add(ctx, []domain.PositionSnapshot{second, anotherPosition, first, before})
+	s.Require().NoError(err)
+
+	timeline, err := s.history.GetTimeline(ctx, position.GetKey(), first.CheckedAt, now.Add(time.Second))
+
+	s.Require().NoError(err)
+	s.Require().Equal(domain.PositionTimeline{first, second}, timeline)
+}
+
+func (s *repositorySuite) TestGetTimeline_StakedWithEmissions_RoundTripped() {
+	ctx := context.Background()
+
+	position := generators.NewPositionGenerator().Slim().Result()
+	position.Staked = true
+	position.Emissions = &domain.PositionEmissions{
+		Token:   domain.Token{Name: "AERO", Decimals: 18, Address: "0xaero", PriceUSD: 1.25},
+		Pending: big.NewInt(1_500_000_000_000_000_000),
+	}
+
+	snapshot := domain.PositionSnapshot{Position: position, CheckedAt: time.Now().UTC().Truncate(time.Second)}
+
+	err := s.history.Add(ctx, []domain.PositionSnapshot{snapshot})
+	s.Require().NoError(err)
+
+	timeline, err := s.history.GetTimeline(ctx, position.GetKey(), snapshot.CheckedAt, snapshot.CheckedAt.Add(time.Second))
+
+	s.Require().NoError(err)
+	s.Require().Equal(domain.PositionTimeline{snapshot}, timeline)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/lib/pq"
+	"github.com/samber/lo"
 
 	jsoniter "github.com/json-iterator/go"
 
@@ -17,16 +18,33 @@ type subjectModel struct {
 }
 
 type subjectPayloadModel struct {
-	TelegramUserID int64          `db:"telegram_user_id"`
-	Wallets        pq.StringArray `db:"wallets"`
-	CheckInterval  time.Duration  `db:"check_interval"`
+	TelegramUserID     int64              `db:"telegram_user_id"`
+	Wallets            pq.StringArray     `db:"wallets"`
+	CheckInterval      time.Duration      `db:"check_interval"`
+	DigestInterval     time.Duration      `db:"digest_interval"`
+	EdgeWarningPercent float64            `db:"edge_warning_percent"`
+	FeesThresholds     map[string]float64 `db:"fees_thresholds"`
+	Channels           []channelModel     `db:"channels"`
+	LiveDashboard      bool               `db:"live_dashboard"`
+}
+
+type channelModel struct {
+	Kind        string `db:"kind"`
+	Target      string `db:"target"`
+	Secret      string `db:"secret"`
+	MinSeverity string `db:"min_severity"`
 }
 
 func newSubjectModel(subject domain.Subject) (subjectModel, error) {
 	payloadModel := subjectPayloadModel{
-		TelegramUserID: subject.TelegramUserID,
-		Wallets:        subject.Wallets,
-		CheckInterval:  subject.CheckInterval,
+		TelegramUserID:     subject.TelegramUserID,
+		Wallets:            subject.Wallets,
+		CheckInterval:      subject.CheckInterval,
+		DigestInterval:     subject.DigestInterval,
+		EdgeWarningPercent: subject.EdgeWarningPercent,
+		FeesThresholds:     subject.FeesThresholds,
+		Channels:           newChannelModels(subject.Channels),
+		LiveDashboard:      subject.LiveDashboard,
 	}
 
 	dump, err := jsoniter.MarshalToString(payloadModel)
@@ -52,8 +70,43 @@ func (model subjectModel) mustToSubject() domain.Subject {
 	}
 
 	return domain.Subject{
-		TelegramUserID: payloadModel.TelegramUserID,
-		Wallets:        payloadModel.Wallets,
-		CheckInterval:  payloadModel.CheckInterval,
+		TelegramUserID:     payloadModel.TelegramUserID,
+		Wallets:            payloadModel.Wallets,
+		CheckInterval:      payloadModel.CheckInterval,
+		DigestInterval:     payloadModel.DigestInterval,
+		EdgeWarningPercent: payloadModel.EdgeWarningPercent,
+		FeesThresholds:     payloadModel.FeesThresholds,
+		Channels:           toChannels(payloadModel.Channels),
+		LiveDashboard:      payloadModel.LiveDashboard,
+	}
+}
+
+func newChannelModels(channels []domain.NotificationChannel) []channelModel {
+	return lo.Map(channels, func(channel domain.NotificationChannel, _ int) channelModel {
+		return channelModel{
+			Kind:        string(channel.Kind),
+			Target:      channel.Target,
+			Secret:      channel.Secret,
+			MinSeverity: string(channel.MinSeverity),
+		}
+	})
+}
+
+func toChannels(models []channelModel) []domain.NotificationChannel {
+	if len(models) == 0 {
+		return nil
+	}
+
+	return lo.Map(models, func(model channelModel, _ int) domain.NotificationChannel {
+		return model.toChannel()
+	})
+}
+
+func (model channelModel) toChannel() domain.NotificationChannel {
+	return domain.NotificationChannel{
+		Kind:        domain.ChannelKind(model.Kind),
+		Target:      model.Target,
+		Secret:      model.Secret,
+		MinSeverity: domain.Severity(model.MinSeverity),
 	}
 }
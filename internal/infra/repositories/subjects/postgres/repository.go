@@ -42,6 +42,21 @@ func (p SubjectsRepository) Add(ctx context.Context, subject domain.Subject) err
 	return nil
 }
 
+func (p SubjectsRepository) Get(ctx context.Context, telegramUserID int64) (domain.Subject, error) {
+	var models []subjectModel
+
+	err := p.db.SelectContext(ctx, &models, queryGetSubject, telegramUserID)
+	if err != nil {
+		return domain.Subject{}, fmt.Errorf("SelectContext: %w", err)
+	}
+
+	if len(models) == 0 {
+		return domain.Subject{}, domain.ErrSubjectNotFound
+	}
+
+	return models[0].mustToSubject(), nil
+}
+
 func (p SubjectsRepository) GetAll(ctx context.Context) ([]domain.Subject, error) {
 	var models []subjectModel
 
@@ -69,6 +84,16 @@ FROM
     subjects
 `
 
+const queryGetSubject = `
+SELECT 
+    telegram_user_id, 
+    payload 
+FROM 
+    subjects
+WHERE 
+    telegram_user_id = $1
+`
+
 const queryAddSubject = `
 INSERT INTO 
     subjects (telegram_user_id, payload)
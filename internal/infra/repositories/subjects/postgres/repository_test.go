@@ -2,22 +2,15 @@ package postgres_test
 
 import (
 	"context"
-	"fmt"
-	"path"
-	"path/filepath"
-	"runtime"
 	"testing"
 
-	"github.com/caarlos0/env/v11"
 	"github.com/jmoiron/sqlx"
-	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/suite"
 
-	_ "github.com/lib/pq"
-
 	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
 	pg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/subjects/postgres"
 	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+	"github.com/DanilaKorobkov/defi-monitoring/test/postgres"
 )
 
 type repositorySuite struct {
@@ -34,17 +27,7 @@ func TestRepository(t *testing.T) {
 }
 
 func (s *repositorySuite) SetupSuite() {
-	projectDir := mustGetProjectDir(getCurrentFile())
-	envPath := path.Join(projectDir, "deploy/.env.local")
-
-	err := godotenv.Overload(envPath)
-	s.Require().NoError(err)
-
-	config := PostgresConfig{}
-	err = env.Parse(&config)
-	s.Require().NoError(err)
-
-	db, err := sqlx.Connect("postgres", config.MakeURL())
+	db, err := postgres.Connect()
 	s.Require().NoError(err)
 	s.db = db
 }
@@ -98,6 +81,29 @@ func (s *repositorySuite) TestAdd_Success() {
 	s.Require().Equal([]domain.Subject{subject}, subjects)
 }
 
+func (s *repositorySuite) TestGet_NotFound() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	_, err := s.subjects.Get(ctx, subject.TelegramUserID)
+
+	s.Require().ErrorIs(err, domain.ErrSubjectNotFound)
+}
+
+func (s *repositorySuite) TestGet_Success() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	err := s.subjects.Add(ctx, subject)
+	s.Require().NoError(err)
+
+	stored, err := s.subjects.Get(ctx, subject.TelegramUserID)
+	s.Require().NoError(err)
+	s.Require().Equal(subject, stored)
+}
+
 func (s *repositorySuite) TestGetAll_Empty() {
 	ctx := context.Background()
 
@@ -107,35 +113,38 @@ func (s *repositorySuite) TestGetAll_Empty() {
 	s.Require().Nil(subjects)
 }
 
-type PostgresConfig struct {
-	PostgresPort     string `env:"POSTGRES_PORT,required"`
-	PostgresUser     string `env:"POSTGRES_USER,required"`
-	PostgresPassword string `env:"POSTGRES_PASSWORD,required"`
-	PostgresDB       string `env:"POSTGRES_DB,required"`
-}
-
-func (config PostgresConfig) MakeURL() string {
-	return fmt.Sprintf(
-		"postgres://%s:%s@localhost:%s/%s?sslmode=disable",
-		config.PostgresUser, config.PostgresPassword, config.PostgresPort, config.PostgresDB,
-	)
-}
+func (s *repositorySuite) TestGet_Channels_Success() {
+	ctx := context.Background()
 
-func mustGetProjectDir(file string) string {
-	for dir := filepath.Dir(file); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
-		if filepath.Base(dir) == "internal" {
-			return filepath.Dir(dir)
-		}
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	subject.Channels = []domain.NotificationChannel{
+		domain.NewTelegramChannel(subject.TelegramUserID),
+		{
+			Kind:        domain.ChannelWebhook,
+			Target:      "https://example.com/hook",
+			Secret:      "secret",
+			MinSeverity: domain.SeverityCritical,
+		},
 	}
 
-	message := "getProjectDir: cannot find project directory: " + file
-	panic(message)
+	err := s.subjects.Add(ctx, subject)
+	s.Require().NoError(err)
+
+	actual, err := s.subjects.Get(ctx, subject.TelegramUserID)
+	s.Require().NoError(err)
+	s.Require().Equal(subject, actual)
 }
 
-func getCurrentFile() string {
-	_, filename, _, ok := runtime.Caller(1) //nolint:dogsled // Standard library
-	if !ok {
-		panic("Could not get current file")
-	}
-	return filename
+func (s *repositorySuite) TestGet_LiveDashboard_Success() {
+	ctx := context.Background()
+
+	subject := generators.NewSubjectGenerator().Slim().Result()
+	subject.LiveDashboard = true
+
+	err := s.subjects.Add(ctx, subject)
+	s.Require().NoError(err)
+
+	actual, err := s.subjects.Get(ctx, subject.TelegramUserID)
+	s.Require().NoError(err)
+	s.Require().Equal(subject, actual)
 }
@@ -4,18 +4,38 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
 	"github.com/urfave/cli/v3"
 
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain/services/pnl"
+	outboxpg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/outbox/postgres"
+	historypg "github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/positions_history/postgres"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/repositories/subjects/postgres"
 	"github.com/DanilaKorobkov/defi-monitoring/pkg/migrators"
 )
 
+var (
+	errInvalidSeverity = errors.New("severity must be one of info, warning, critical")
+	errUnknownChannel  = errors.New("severity is set for the channel which is not configured")
+)
+
 type Config struct {
-	DBCommandConfig DBCommandConfig
+	DBCommandConfig        DBCommandConfig
+	SubjectsCommandConfig  SubjectsCommandConfig
+	PositionsCommandConfig PositionsCommandConfig
+	OutboxCommandConfig    OutboxCommandConfig
 }
 
 type DBCommandConfig struct {
@@ -28,10 +48,25 @@ type MigratorCommandConfig struct {
 	Logger             *slog.Logger
 }
 
+type SubjectsCommandConfig struct {
+	PostgresURLEnvName string
+}
+
+type PositionsCommandConfig struct {
+	PostgresURLEnvName string
+}
+
+type OutboxCommandConfig struct {
+	PostgresURLEnvName string
+}
+
 func New(config Config) *cli.Command {
 	return &cli.Command{
 		Commands: []*cli.Command{
 			newDBCommands(config.DBCommandConfig),
+			newSubjectsCommands(config.SubjectsCommandConfig),
+			newPositionsCommands(config.PositionsCommandConfig),
+			newOutboxCommands(config.OutboxCommandConfig),
 		},
 	}
 }
@@ -74,6 +109,404 @@ func newMigrateCommand(config MigratorCommandConfig) *cli.Command {
 	}
 }
 
+func newSubjectsCommands(config SubjectsCommandConfig) *cli.Command {
+	return &cli.Command{
+		Name: "subjects",
+		Commands: []*cli.Command{
+			newAddSubjectCommand(config),
+		},
+	}
+}
+
+func newAddSubjectCommand(config SubjectsCommandConfig) *cli.Command {
+	var (
+		pgURL    string
+		subject  domain.Subject
+		channels channelsFlags
+	)
+
+	return &cli.Command{
+		Name:  "add",
+		Usage: "add subject to watch or override if already exists",
+		Flags: append(makeChannelsFlags(&channels), []cli.Flag{
+			makeToURLFlag(&pgURL, config.PostgresURLEnvName),
+			&cli.Int64Flag{
+				Name:        "telegram-user-id",
+				Usage:       "Telegram user to notify",
+				Required:    true,
+				Destination: &subject.TelegramUserID,
+			},
+			&cli.StringSliceFlag{
+				Name:        "wallet",
+				Usage:       "Wallet to watch, may be repeated",
+				Required:    true,
+				Destination: &subject.Wallets,
+			},
+			&cli.DurationFlag{
+				Name:        "check-interval",
+				Usage:       "How often positions are checked e.g: 30m",
+				Required:    true,
+				Destination: &subject.CheckInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "digest-interval",
+				Usage:       "How often full report is sent, disabled by default e.g: 24h",
+				Destination: &subject.DigestInterval,
+			},
+			&cli.Float64Flag{
+				Name:        "edge-warning-percent",
+				Usage:       "Warn when price is within this percent of the range width from its bound",
+				Destination: &subject.EdgeWarningPercent,
+			},
+			&cli.BoolFlag{
+				Name:        "live-dashboard",
+				Usage:       "Update the single pinned Telegram message instead of sending reports",
+				Destination: &subject.LiveDashboard,
+			},
+			&cli.StringMapFlag{
+				Name:  "fees-threshold",
+				Usage: "Remind to collect when token fees reach the amount e.g: USDC=10, may be repeated",
+				Action: func(_ context.Context, _ *cli.Command, value map[string]string) error {
+					thresholds, err := parseFeesThresholds(value)
+					subject.FeesThresholds = thresholds
+					return err
+				},
+			},
+		}...),
+		Action: func(ctx context.Context, _ *cli.Command) error {
+			var err error
+
+			subject.Channels, err = channels.makeChannels(subject.TelegramUserID)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("makeChannels: %s", err), -1)
+			}
+
+			db, err := sqlx.Connect("postgres", pgURL)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("sqlx.Connect: %s", err), -1)
+			}
+
+			err = postgres.NewSubjectsRepository(db).Add(ctx, subject)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("SubjectsRepository.Add: %s", err), -1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// channelsFlags are notification channels of the subject besides its Telegram chat.
+type channelsFlags struct {
+	discordWebhookURL string
+	slackWebhookURL   string
+	email             string
+	webhookURL        string
+	webhookSecret     string
+	minSeverities     map[string]string
+}
+
+func makeChannelsFlags(flags *channelsFlags) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "discord-webhook-url",
+			Usage:       "Discord webhook to post notifications to as well",
+			Destination: &flags.discordWebhookURL,
+		},
+		&cli.StringFlag{
+			Name:        "slack-webhook-url",
+			Usage:       "Slack incoming webhook to post notifications to as well",
+			Destination: &flags.slackWebhookURL,
+		},
+		&cli.StringFlag{
+			Name:        "email",
+			Usage:       "Email address to send notifications to as well",
+			Destination: &flags.email,
+		},
+		&cli.StringFlag{
+			Name:        "webhook-url",
+			Usage:       "Endpoint to post signed JSON notifications to as well",
+			Destination: &flags.webhookURL,
+		},
+		&cli.StringFlag{
+			Name:        "webhook-secret",
+			Usage:       "Secret to sign webhook payloads with HMAC-SHA256",
+			Destination: &flags.webhookSecret,
+		},
+		&cli.StringMapFlag{
+			Name:        "min-severity",
+			Usage:       "Least severity the channel is notified about e.g: telegram=warning, may be repeated",
+			Destination: &flags.minSeverities,
+		},
+	}
+}
+
+// makeChannels returns the Telegram chat and the configured channels filtered by their severities.
+func (flags channelsFlags) makeChannels(telegramUserID int64) ([]domain.NotificationChannel, error) {
+	channels := []domain.NotificationChannel{
+		domain.NewTelegramChannel(telegramUserID),
+		{Kind: domain.ChannelDiscord, Target: flags.discordWebhookURL},
+		{Kind: domain.ChannelSlack, Target: flags.slackWebhookURL},
+		{Kind: domain.ChannelEmail, Target: flags.email},
+		{Kind: domain.ChannelWebhook, Target: flags.webhookURL, Secret: flags.webhookSecret},
+	}
+	channels = lo.Filter(channels, func(channel domain.NotificationChannel, _ int) bool {
+		return channel.Target != ""
+	})
+
+	for kind, rawSeverity := range flags.minSeverities {
+		severity := domain.Severity(rawSeverity)
+		if !severity.IsValid() {
+			return nil, fmt.Errorf("%w: %s", errInvalidSeverity, rawSeverity)
+		}
+
+		index := slices.IndexFunc(channels, func(channel domain.NotificationChannel) bool {
+			return channel.Kind == domain.ChannelKind(kind)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", errUnknownChannel, kind)
+		}
+		channels[index].MinSeverity = severity
+	}
+
+	return channels, nil
+}
+
+func parseFeesThresholds(value map[string]string) (map[string]float64, error) {
+	thresholds := make(map[string]float64, len(value))
+
+	for token, rawAmount := range value {
+		amount, err := strconv.ParseFloat(rawAmount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("strconv.ParseFloat: %w", err)
+		}
+		thresholds[token] = amount
+	}
+
+	return thresholds, nil
+}
+
+func newPositionsCommands(config PositionsCommandConfig) *cli.Command {
+	return &cli.Command{
+		Name: "positions",
+		Commands: []*cli.Command{
+			newOutOfRangeCommand(config),
+			newPnLCommand(config),
+		},
+	}
+}
+
+func newOutOfRangeCommand(config PositionsCommandConfig) *cli.Command {
+	var (
+		pgURL  string
+		key    domain.PositionKey
+		period time.Duration
+	)
+
+	return &cli.Command{
+		Name:  "out-of-range",
+		Usage: "print how long the position was out of range during the period",
+		Flags: append(makePositionKeyFlags(&key), []cli.Flag{
+			makeToURLFlag(&pgURL, config.PostgresURLEnvName),
+			makePeriodFlag(&period),
+		}...),
+		Action: func(ctx context.Context, command *cli.Command) error {
+			db, err := sqlx.Connect("postgres", pgURL)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("sqlx.Connect: %s", err), -1)
+			}
+
+			now := time.Now()
+			history := historypg.NewPositionsHistoryRepository(db)
+
+			timeline, err := history.GetTimeline(ctx, key, now.Add(-period), now)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("PositionsHistoryRepository.GetTimeline: %s", err), -1)
+			}
+
+			_, err = fmt.Fprintf(command.Root().Writer, "%s\n", timeline.GetOutOfRangeDuration())
+			return err
+		},
+	}
+}
+
+func newPnLCommand(config PositionsCommandConfig) *cli.Command {
+	var (
+		pgURL  string
+		key    domain.PositionKey
+		period time.Duration
+	)
+
+	return &cli.Command{
+		Name:  "pnl",
+		Usage: "print PnL versus holding, impermanent loss and fee APR by the latest position snapshot",
+		Flags: append(makePositionKeyFlags(&key), []cli.Flag{
+			makeToURLFlag(&pgURL, config.PostgresURLEnvName),
+			makePeriodFlag(&period),
+		}...),
+		Action: func(ctx context.Context, command *cli.Command) error {
+			db, err := sqlx.Connect("postgres", pgURL)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("sqlx.Connect: %s", err), -1)
+			}
+
+			now := time.Now()
+			history := historypg.NewPositionsHistoryRepository(db)
+
+			timeline, err := history.GetTimeline(ctx, key, now.Add(-period), now)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("PositionsHistoryRepository.GetTimeline: %s", err), -1)
+			}
+
+			return printPnLReport(command.Root().Writer, timeline)
+		},
+	}
+}
+
+func newOutboxCommands(config OutboxCommandConfig) *cli.Command {
+	return &cli.Command{
+		Name: "outbox",
+		Commands: []*cli.Command{
+			newListOutboxCommand(config),
+		},
+	}
+}
+
+func newListOutboxCommand(config OutboxCommandConfig) *cli.Command {
+	var (
+		pgURL  string
+		status string
+		limit  int64
+	)
+
+	return &cli.Command{
+		Name:  "list",
+		Usage: "print latest notifications of the outbox with the status",
+		Flags: []cli.Flag{
+			makeToURLFlag(&pgURL, config.PostgresURLEnvName),
+			&cli.StringFlag{
+				Name:        "status",
+				Usage:       "Notifications status: pending, sent or dead",
+				Value:       string(domain.OutboxStatusDead),
+				Destination: &status,
+			},
+			&cli.Int64Flag{
+				Name:        "limit",
+				Usage:       "How many latest notifications to print",
+				Value:       20, //nolint:mnd // Fits the screen.
+				Destination: &limit,
+			},
+		},
+		Action: func(ctx context.Context, command *cli.Command) error {
+			db, err := sqlx.Connect("postgres", pgURL)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("sqlx.Connect: %s", err), -1)
+			}
+
+			messages, err := outboxpg.NewOutboxRepository(db).
+				GetByStatus(ctx, domain.OutboxStatus(status), int(limit))
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("OutboxRepository.GetByStatus: %s", err), -1)
+			}
+
+			return printOutboxMessages(command.Root().Writer, messages)
+		},
+	}
+}
+
+func printOutboxMessages(writer io.Writer, messages []domain.OutboxMessage) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0) //nolint:mnd // Column padding.
+
+	_, _ = fmt.Fprintln(table, "ID\tSUBJECT\tCONTENT\tATTEMPTS\tCREATED AT\tLAST ERROR")
+
+	for _, message := range messages {
+		content := fmt.Sprintf("%d events", len(message.Events))
+		if len(message.Events) == 0 {
+			content = fmt.Sprintf("%d positions", len(message.Positions))
+		}
+
+		_, _ = fmt.Fprintf(
+			table,
+			"%d\t%d\t%s\t%d\t%s\t%s\n",
+			message.ID,
+			message.TelegramUserID,
+			content,
+			message.Attempts,
+			message.CreatedAt.Format(time.RFC3339),
+			message.LastError,
+		)
+	}
+
+	return table.Flush()
+}
+
+// printPnLReport prints the report of the latest snapshot in the timeline.
+func printPnLReport(writer io.Writer, timeline domain.PositionTimeline) error {
+	if len(timeline) == 0 {
+		return cli.Exit("no snapshots of the position within the period", -1)
+	}
+
+	last := timeline[len(timeline)-1]
+
+	report, ok := pnl.Calculate(last.Position, last.CheckedAt)
+	if !ok {
+		return cli.Exit("position deposits or token prices are unknown", -1)
+	}
+
+	_, err := fmt.Fprintf(
+		writer,
+		"checked at: %s\nposition: $%.2f\nhodl: $%.2f\npnl: $%.2f\nfees: $%.2f\n"+
+			"impermanent loss: %.2f%%\nfee apr: %.2f%%\n",
+		last.CheckedAt.Format(time.RFC3339),
+		report.PositionUSD,
+		report.HODLUSD,
+		report.GetPnLUSD(),
+		report.FeesUSD,
+		report.ImpermanentLossPercent,
+		report.FeeAPRPercent,
+	)
+
+	return err
+}
+
+func makePositionKeyFlags(key *domain.PositionKey) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "chain",
+			Usage:    "Position chain e.g: Base",
+			Required: true,
+			Action: func(_ context.Context, _ *cli.Command, value string) error {
+				key.Chain = domain.Chain(value)
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:     "dex",
+			Usage:    "Position dex e.g: Uniswap V3",
+			Required: true,
+			Action: func(_ context.Context, _ *cli.Command, value string) error {
+				key.Dex = domain.Dex(value)
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:        "id",
+			Usage:       "Position ID",
+			Required:    true,
+			Destination: &key.ID,
+		},
+	}
+}
+
+func makePeriodFlag(destination *time.Duration) *cli.DurationFlag {
+	return &cli.DurationFlag{
+		Name:        "period",
+		Usage:       "Period till now",
+		Value:       7 * 24 * time.Hour,
+		Destination: destination,
+	}
+}
+
 func makeToURLFlag(destination *string, envName string) *cli.StringFlag {
 	return &cli.StringFlag{
 		Name:        "url",
@@ -0,0 +1,355 @@
+package telegrambot
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/infra/notifiers/telegram"
+)
+
+const (
+	minCheckInterval = time.Minute
+	// updatesTimeout is the long polling timeout in seconds.
+	updatesTimeout = 60
+	walletLength   = 42
+
+	helpText = `Commands:
+/addwallet <address> - watch positions of the wallet
+/removewallet <address> - stop watching the wallet
+/wallets - list watched wallets
+/interval <duration> - how often positions are checked e.g: 30m
+/dashboard <on|off> - update the single pinned message instead of sending reports
+/positions - report positions now`
+	failureText      = "Something went wrong, please try again later"
+	accessDeniedText = "Sorry, the bot is private"
+)
+
+// PositionsReporter sends the subject positions report on demand.
+type PositionsReporter interface {
+	// ReportPositions sends the subject positions or returns domain.ErrNoPositions.
+	ReportPositions(ctx context.Context, subject domain.Subject) error
+}
+
+type BotConfig struct {
+	TelegramBot telegram.TgBotApi
+	Subjects    domain.SubjectsRepository
+	Positions   PositionsReporter
+	// CheckInterval is set to subjects created by the bot.
+	CheckInterval time.Duration
+	// AllowedUserIDs are Telegram users who may use the bot, commands of others are rejected.
+	AllowedUserIDs []int64
+	// MaxWallets limits wallets watched by a subject.
+	MaxWallets int
+	Logger     *slog.Logger
+}
+
+// Bot handles commands of Telegram users allowing them to manage own subscription.
+type Bot struct {
+	telegramBot    telegram.TgBotApi
+	subjects       domain.SubjectsRepository
+	positions      PositionsReporter
+	checkInterval  time.Duration
+	allowedUserIDs []int64
+	maxWallets     int
+	logger         *slog.Logger
+	chats          *chatLocks
+}
+
+func NewBot(config BotConfig) *Bot {
+	return &Bot{
+		telegramBot:    config.TelegramBot,
+		subjects:       config.Subjects,
+		positions:      config.Positions,
+		checkInterval:  config.CheckInterval,
+		allowedUserIDs: config.AllowedUserIDs,
+		maxWallets:     config.MaxWallets,
+		logger:         config.Logger,
+		chats:          newChatLocks(),
+	}
+}
+
+// Run long polls updates and blocks until ctx is done or updates channel is closed. Commands are handled
+// concurrently, so slow reports do not hold others, commands of the chat are handled one by one.
+// Run returns once the started commands are handled.
+func (bot *Bot) Run(ctx context.Context) {
+	updateConfig := tgbotapi.NewUpdate(0)
+	updateConfig.Timeout = updatesTimeout
+
+	updates := bot.telegramBot.GetUpdatesChan(updateConfig)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			bot.telegramBot.StopReceivingUpdates()
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				bot.handleMessage(ctx, update.Message)
+			}()
+		}
+	}
+}
+
+// accept reports whether the message is a command of the allowed user in the private chat, other users are
+// told the bot is private.
+func (bot *Bot) accept(message *tgbotapi.Message) bool {
+	if message == nil || message.Chat == nil || !message.Chat.IsPrivate() || !message.IsCommand() {
+		return false
+	}
+
+	if slices.Contains(bot.allowedUserIDs, message.Chat.ID) {
+		return true
+	}
+
+	bot.logger.Warn("command of not allowed user", slog.Int64("subject", message.Chat.ID))
+	bot.reply(message.Chat.ID, accessDeniedText)
+
+	return false
+}
+
+func (bot *Bot) addWallet(ctx context.Context, subject domain.Subject, args string) (string, error) {
+	wallet := strings.ToLower(args)
+	if !isWalletAddress(wallet) {
+		return "Usage: /addwallet <address>", nil
+	}
+
+	if slices.Contains(subject.Wallets, wallet) {
+		return "Wallet is already watched", nil
+	}
+
+	if len(subject.Wallets) >= bot.maxWallets {
+		return fmt.Sprintf("At most %d wallets can be watched, remove one with /removewallet <address>", bot.maxWallets), nil
+	}
+
+	subject.Wallets = append(subject.Wallets, wallet)
+
+	return "Wallet added", bot.save(ctx, subject)
+}
+
+func (bot *Bot) getHandlers() map[string]commandHandler {
+	return map[string]commandHandler{
+		"start":        bot.start,
+		"addwallet":    bot.addWallet,
+		"removewallet": bot.removeWallet,
+		"wallets":      bot.listWallets,
+		"interval":     bot.setInterval,
+		"dashboard":    bot.setDashboard,
+		"positions":    bot.reportPositions,
+	}
+}
+
+// getSubject returns the stored subject or the new one with default settings.
+func (bot *Bot) getSubject(ctx context.Context, telegramUserID int64) (domain.Subject, error) {
+	subject, err := bot.subjects.Get(ctx, telegramUserID)
+	if errors.Is(err, domain.ErrSubjectNotFound) {
+		return domain.Subject{
+			TelegramUserID: telegramUserID,
+			CheckInterval:  bot.checkInterval,
+		}, nil
+	}
+	if err != nil {
+		return domain.Subject{}, fmt.Errorf("SubjectsRepository.Get: %w", err)
+	}
+
+	return subject, nil
+}
+
+func (bot *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
+	if !bot.accept(message) {
+		return
+	}
+
+	unlock := bot.chats.lock(message.Chat.ID)
+	defer unlock()
+
+	handler, ok := bot.getHandlers()[message.Command()]
+	if !ok {
+		bot.reply(message.Chat.ID, helpText)
+		return
+	}
+
+	text, err := bot.handleCommand(ctx, message.Chat.ID, handler, strings.TrimSpace(message.CommandArguments()))
+	if err != nil {
+		bot.logger.Error(
+			"handleCommand",
+			slog.String("command", message.Command()),
+			slog.Int64("subject", message.Chat.ID),
+			slog.String("err", err.Error()),
+		)
+		text = failureText
+	}
+
+	if text != "" {
+		bot.reply(message.Chat.ID, text)
+	}
+}
+
+func (bot *Bot) handleCommand(
+	ctx context.Context,
+	telegramUserID int64,
+	handler commandHandler,
+	args string,
+) (string, error) {
+	subject, err := bot.getSubject(ctx, telegramUserID)
+	if err != nil {
+		return "", fmt.Errorf("getSubject: %w", err)
+	}
+
+	return handler(ctx, subject, args)
+}
+
+func (*Bot) listWallets(_ context.Context, subject domain.Subject, _ string) (string, error) {
+	if len(subject.Wallets) == 0 {
+		return "No wallets are watched, add one with /addwallet <address>", nil
+	}
+
+	return "Watched wallets:\n" + strings.Join(subject.Wallets, "\n"), nil
+}
+
+func (bot *Bot) removeWallet(ctx context.Context, subject domain.Subject, args string) (string, error) {
+	wallet := strings.ToLower(args)
+	if !slices.Contains(subject.Wallets, wallet) {
+		return "Wallet is not watched", nil
+	}
+
+	subject.Wallets = slices.DeleteFunc(slices.Clone(subject.Wallets), func(item string) bool {
+		return item == wallet
+	})
+
+	return "Wallet removed", bot.save(ctx, subject)
+}
+
+func (bot *Bot) reply(chatID int64, text string) {
+	_, err := bot.telegramBot.Send(tgbotapi.NewMessage(chatID, text))
+	if err != nil {
+		bot.logger.Error("telegram.Send", slog.Int64("subject", chatID), slog.String("err", err.Error()))
+	}
+}
+
+// reportPositions replies nothing on success since the report is the answer.
+func (bot *Bot) reportPositions(ctx context.Context, subject domain.Subject, _ string) (string, error) {
+	err := bot.positions.ReportPositions(ctx, subject)
+	if errors.Is(err, domain.ErrNoPositions) {
+		return "No positions with liquidity found", nil
+	}
+	if errors.Is(err, domain.ErrWalletsUnavailable) {
+		return "Positions of some wallets could not be read, please try again later", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("PositionsReporter.ReportPositions: %w", err)
+	}
+
+	return "", nil
+}
+
+func (bot *Bot) save(ctx context.Context, subject domain.Subject) error {
+	err := bot.subjects.Add(ctx, subject)
+	if err != nil {
+		return fmt.Errorf("SubjectsRepository.Add: %w", err)
+	}
+
+	return nil
+}
+
+func (bot *Bot) setDashboard(ctx context.Context, subject domain.Subject, args string) (string, error) {
+	switch args {
+	case "on":
+		subject.LiveDashboard = true
+	case "off":
+		subject.LiveDashboard = false
+	default:
+		return "Usage: /dashboard <on|off>", nil
+	}
+
+	return "Live dashboard turned " + args, bot.save(ctx, subject)
+}
+
+func (bot *Bot) setInterval(ctx context.Context, subject domain.Subject, args string) (string, error) {
+	interval, err := time.ParseDuration(args)
+	if err != nil || interval < minCheckInterval {
+		return fmt.Sprintf("Usage: /interval <duration>, at least %s e.g: 30m", minCheckInterval), nil
+	}
+
+	subject.CheckInterval = interval
+
+	return "Check interval set to " + interval.String(), bot.save(ctx, subject)
+}
+
+// start only greets the user, the subject is saved with the first added wallet.
+func (*Bot) start(context.Context, domain.Subject, string) (string, error) {
+	return "Welcome! Add a wallet to watch its liquidity positions.\n\n" + helpText, nil
+}
+
+type commandHandler func(ctx context.Context, subject domain.Subject, args string) (string, error)
+
+// chatLocks serializes commands of the chat, so concurrent commands do not overwrite the subject changes.
+// Locks are dropped once no command of the chat is handled.
+type chatLocks struct {
+	mu    sync.Mutex
+	chats map[int64]*chatLock
+}
+
+type chatLock struct {
+	mu      sync.Mutex
+	holders int
+}
+
+func newChatLocks() *chatLocks {
+	return &chatLocks{
+		chats: make(map[int64]*chatLock),
+	}
+}
+
+// lock blocks until commands of the chat handled before are done, the returned func unlocks the chat.
+func (locks *chatLocks) lock(chatID int64) func() {
+	locks.mu.Lock()
+	chat, ok := locks.chats[chatID]
+	if !ok {
+		chat = &chatLock{}
+		locks.chats[chatID] = chat
+	}
+	chat.holders++
+	locks.mu.Unlock()
+
+	chat.mu.Lock()
+
+	return func() {
+		chat.mu.Unlock()
+
+		locks.mu.Lock()
+		chat.holders--
+		if chat.holders == 0 {
+			delete(locks.chats, chatID)
+		}
+		locks.mu.Unlock()
+	}
+}
+
+// isWalletAddress reports whether the value is a hex encoded EVM address.
+func isWalletAddress(value string) bool {
+	if len(value) != walletLength || !strings.HasPrefix(value, "0x") {
+		return false
+	}
+
+	_, err := hex.DecodeString(value[2:])
+
+	return err == nil
+}
@@ -0,0 +1,400 @@
+package telegrambot_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+	"github.com/DanilaKorobkov/defi-monitoring/internal/presentation/telegrambot"
+	mocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/domain"
+	tgmocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/infra/notifiers/telegram"
+	botmocks "github.com/DanilaKorobkov/defi-monitoring/mocks/internal_/presentation/telegrambot"
+	"github.com/DanilaKorobkov/defi-monitoring/test/generators"
+)
+
+const (
+	wallet     = "0x1111111111111111111111111111111111111111"
+	maxWallets = 10
+)
+
+type botSuite struct {
+	suite.Suite
+}
+
+func TestBot(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(botSuite))
+}
+
+func (s *botSuite) TestRun_Start_GreetWithoutSaving() {
+	telegramUserID := int64(42)
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, telegramUserID).
+		Return(domain.Subject{}, domain.ErrSubjectNotFound).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(telegramUserID, "/start"))
+	expectReply(tgBot, telegramUserID, "Welcome!")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), telegramUserID)
+}
+
+func (s *botSuite) TestRun_NotAllowedUser_Rejected() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/addwallet "+wallet))
+	expectReply(tgBot, subject.TelegramUserID, "Sorry, the bot is private")
+
+	s.runBot(tgBot, mocks.NewSubjectsRepository(s.T()), botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID+1)
+}
+
+func (s *botSuite) TestRun_AddFirstWallet_SubscribeNewSubject() {
+	subject := domain.Subject{
+		TelegramUserID: 42,
+		Wallets:        []string{wallet},
+		CheckInterval:  time.Hour,
+	}
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(domain.Subject{}, domain.ErrSubjectNotFound).
+		Once()
+	subjects.EXPECT().
+		Add(mock.Anything, subject).
+		Return(nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/addwallet "+wallet))
+	expectReply(tgBot, subject.TelegramUserID, "Wallet added")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_AddWalletOverLimit_NotSaved() {
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets(generators.GeneratePlenty(maxWallets, uuid.NewString)).
+		Result()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/addwallet "+wallet))
+	expectReply(tgBot, subject.TelegramUserID, "At most 10 wallets can be watched")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_AddWallet_SaveLowerCase() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	updated := subject
+	updated.Wallets = append(slices.Clone(subject.Wallets), wallet)
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+	subjects.EXPECT().
+		Add(mock.Anything, updated).
+		Return(nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/addwallet 0x"+strings.ToUpper(wallet[2:])))
+	expectReply(tgBot, subject.TelegramUserID, "Wallet added")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_InvalidArguments_ReplyUsage() {
+	type TestCase struct {
+		name          string
+		text          string
+		expectedReply string
+	}
+
+	testCases := []TestCase{
+		{name: "Add not address", text: "/addwallet vitalik", expectedReply: "Usage: /addwallet"},
+		{name: "Remove not watched", text: "/removewallet " + wallet, expectedReply: "Wallet is not watched"},
+		{name: "Interval too short", text: "/interval 10s", expectedReply: "Usage: /interval"},
+		{name: "Dashboard unknown mode", text: "/dashboard maybe", expectedReply: "Usage: /dashboard"},
+		{name: "Unknown command", text: "/unknown", expectedReply: "Commands:"},
+	}
+	for _, testCase := range testCases {
+		s.Run(testCase.name, func() {
+			subject := generators.NewSubjectGenerator().Slim().Result()
+
+			subjects := mocks.NewSubjectsRepository(s.T())
+			subjects.EXPECT().
+				Get(mock.Anything, subject.TelegramUserID).
+				Return(subject, nil).
+				Maybe()
+
+			tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, testCase.text))
+			expectReply(tgBot, subject.TelegramUserID, testCase.expectedReply)
+
+			s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+		})
+	}
+}
+
+func (s *botSuite) TestRun_Interval_SaveInterval() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	updated := subject
+	updated.CheckInterval = 15 * time.Minute
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+	subjects.EXPECT().
+		Add(mock.Anything, updated).
+		Return(nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/interval 15m"))
+	expectReply(tgBot, subject.TelegramUserID, "Check interval set to 15m0s")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_Dashboard_SaveLiveDashboard() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	updated := subject
+	updated.LiveDashboard = true
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+	subjects.EXPECT().
+		Add(mock.Anything, updated).
+		Return(nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/dashboard on"))
+	expectReply(tgBot, subject.TelegramUserID, "Live dashboard turned on")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_Positions_Report() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+
+	reporter := botmocks.NewPositionsReporter(s.T())
+	reporter.EXPECT().
+		ReportPositions(mock.Anything, subject).
+		Return(nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/positions"))
+
+	s.runBot(tgBot, subjects, reporter, subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_PositionsOfSomeWalletsUnavailable_TryLater() {
+	subject := generators.NewSubjectGenerator().Slim().Result()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+
+	reporter := botmocks.NewPositionsReporter(s.T())
+	reporter.EXPECT().
+		ReportPositions(mock.Anything, subject).
+		Return(domain.ErrWalletsUnavailable).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/positions"))
+	expectReply(tgBot, subject.TelegramUserID, "Positions of some wallets could not be read")
+
+	s.runBot(tgBot, subjects, reporter, subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_SlowReport_OtherChatsHandled() {
+	slow := generators.NewSubjectGenerator().Slim().Result()
+	other := generators.NewSubjectGenerator().Slim().WithWallets([]string{wallet}).Result()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().Get(mock.Anything, slow.TelegramUserID).Return(slow, nil).Once()
+	subjects.EXPECT().Get(mock.Anything, other.TelegramUserID).Return(other, nil).Once()
+
+	otherReplied := make(chan struct{})
+
+	reporter := botmocks.NewPositionsReporter(s.T())
+	reporter.EXPECT().
+		ReportPositions(mock.Anything, slow).
+		RunAndReturn(func(context.Context, domain.Subject) error {
+			<-otherReplied
+			return nil
+		}).
+		Once()
+
+	tgBot := newTgBot(s.T(),
+		makeCommandUpdate(slow.TelegramUserID, "/positions"),
+		makeCommandUpdate(other.TelegramUserID, "/wallets"),
+	)
+	expectReply(tgBot, other.TelegramUserID, "Watched wallets").
+		Run(func(mock.Arguments) { close(otherReplied) })
+
+	s.runBot(tgBot, subjects, reporter, slow.TelegramUserID, other.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_ContextDone_StopReceivingUpdates() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tgBot := tgmocks.NewTgBotApi(s.T())
+	tgBot.EXPECT().
+		GetUpdatesChan(mock.Anything).
+		Return(make(chan tgbotapi.Update)).
+		Once()
+	tgBot.EXPECT().
+		StopReceivingUpdates().
+		Once()
+
+	bot := telegrambot.NewBot(telegrambot.BotConfig{
+		TelegramBot: tgBot,
+		Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	bot.Run(ctx)
+}
+
+func (s *botSuite) TestRun_RemoveWallet_Save() {
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{wallet, "0x2"}).
+		Result()
+
+	updated := subject
+	updated.Wallets = []string{"0x2"}
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+	subjects.EXPECT().
+		Add(mock.Anything, updated).
+		Return(nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/removewallet "+wallet))
+	expectReply(tgBot, subject.TelegramUserID, "Wallet removed")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) TestRun_Wallets_List() {
+	subject := generators.NewSubjectGenerator().
+		Slim().
+		WithWallets([]string{"0x1", "0x2"}).
+		Result()
+
+	subjects := mocks.NewSubjectsRepository(s.T())
+	subjects.EXPECT().
+		Get(mock.Anything, subject.TelegramUserID).
+		Return(subject, nil).
+		Once()
+
+	tgBot := newTgBot(s.T(), makeCommandUpdate(subject.TelegramUserID, "/wallets"))
+	expectReply(tgBot, subject.TelegramUserID, "Watched wallets:\n0x1\n0x2")
+
+	s.runBot(tgBot, subjects, botmocks.NewPositionsReporter(s.T()), subject.TelegramUserID)
+}
+
+func (s *botSuite) runBot(
+	tgBot *tgmocks.TgBotApi,
+	subjects domain.SubjectsRepository,
+	reporter telegrambot.PositionsReporter,
+	allowedUserIDs ...int64,
+) {
+	bot := telegrambot.NewBot(telegrambot.BotConfig{
+		TelegramBot:    tgBot,
+		Subjects:       subjects,
+		Positions:      reporter,
+		CheckInterval:  time.Hour,
+		AllowedUserIDs: allowedUserIDs,
+		MaxWallets:     maxWallets,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	bot.Run(context.Background())
+}
+
+// newTgBot makes the bot api mock which delivers the updates and closes the channel.
+func newTgBot(t *testing.T, updates ...tgbotapi.Update) *tgmocks.TgBotApi {
+	t.Helper()
+
+	channel := make(chan tgbotapi.Update, len(updates))
+	for _, update := range updates {
+		channel <- update
+	}
+	close(channel)
+
+	tgBot := tgmocks.NewTgBotApi(t)
+	tgBot.EXPECT().
+		GetUpdatesChan(mock.Anything).
+		Return(channel).
+		Once()
+
+	return tgBot
+}
+
+// expectReply expects the single reply starting with the text.
+func expectReply(tgBot *tgmocks.TgBotApi, chatID int64, text string) *mock.Call {
+	isReply := func(message tgbotapi.MessageConfig) bool {
+		return message.ChatID == chatID && strings.HasPrefix(message.Text, text)
+	}
+
+	return tgBot.EXPECT().
+		Send(mock.MatchedBy(isReply)).
+		Return(tgbotapi.Message{}, nil).
+		Once()
+}
+
+func makeCommandUpdate(chatID int64, text string) tgbotapi.Update {
+	command, _, _ := strings.Cut(text, " ")
+
+	return tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			Chat: &tgbotapi.Chat{
+				ID:   chatID,
+				Type: "private",
+			},
+			Text: text,
+			Entities: []tgbotapi.MessageEntity{
+				{Type: "bot_command", Offset: 0, Length: len(command)},
+			},
+		},
+	}
+}
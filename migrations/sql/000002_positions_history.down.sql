@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE positions_history;
+
+COMMIT;
\ No newline at end of file
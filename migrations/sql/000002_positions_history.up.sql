@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE positions_history (
+    id BIGSERIAL PRIMARY KEY,
+    chain TEXT NOT NULL,
+    dex TEXT NOT NULL,
+    position_id TEXT NOT NULL,
+    wallet TEXT NOT NULL,
+    token0 TEXT NOT NULL,
+    token1 TEXT NOT NULL,
+    checked_at TIMESTAMPTZ NOT NULL,
+    in_range BOOLEAN NOT NULL,
+    tick_lower INTEGER NOT NULL,
+    tick_upper INTEGER NOT NULL,
+    current_tick INTEGER NOT NULL,
+    current_price DOUBLE PRECISION NOT NULL,
+    payload JSONB NOT NULL
+);
+
+CREATE INDEX positions_history_position_idx ON positions_history (chain, dex, position_id, checked_at);
+
+COMMIT;
\ No newline at end of file
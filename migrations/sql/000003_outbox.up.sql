@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    telegram_user_id BIGINT NOT NULL,
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    last_error TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    payload JSONB NOT NULL
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX outbox_status_idx ON outbox (status, created_at);
+
+COMMIT;
\ No newline at end of file
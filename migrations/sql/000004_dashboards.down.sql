@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE dashboards;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+CREATE TABLE dashboards (
+    telegram_user_id BIGINT PRIMARY KEY,
+    message_id BIGINT NOT NULL
+);
+
+COMMIT;
\ No newline at end of file
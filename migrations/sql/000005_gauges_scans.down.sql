@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE gauges_scans;
+
+COMMIT;
\ No newline at end of file
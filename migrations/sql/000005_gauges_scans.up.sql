@@ -0,0 +1,11 @@
+BEGIN;
+
+CREATE TABLE gauges_scans (
+    chain TEXT NOT NULL,
+    wallet TEXT NOT NULL,
+    next_block BIGINT NOT NULL,
+    gauges JSONB NOT NULL,
+    PRIMARY KEY (chain, wallet)
+);
+
+COMMIT;
\ No newline at end of file
@@ -84,6 +84,68 @@ func (_c *Notifier_NotifyLiquidityPoolPositions_Call) RunAndReturn(run func(cont
 	return _c
 }
 
+// NotifyPositionsEvents provides a mock function with given fields: ctx, subject, events
+func (_m *Notifier) NotifyPositionsEvents(ctx context.Context, subject domain.Subject, events ...domain.PositionEvent) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, subject)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyPositionsEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Subject, ...domain.PositionEvent) error); ok {
+		r0 = rf(ctx, subject, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Notifier_NotifyPositionsEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotifyPositionsEvents'
+type Notifier_NotifyPositionsEvents_Call struct {
+	*mock.Call
+}
+
+// NotifyPositionsEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subject domain.Subject
+//   - events ...domain.PositionEvent
+func (_e *Notifier_Expecter) NotifyPositionsEvents(ctx interface{}, subject interface{}, events ...interface{}) *Notifier_NotifyPositionsEvents_Call {
+	return &Notifier_NotifyPositionsEvents_Call{Call: _e.mock.On("NotifyPositionsEvents",
+		append([]interface{}{ctx, subject}, events...)...)}
+}
+
+func (_c *Notifier_NotifyPositionsEvents_Call) Run(run func(ctx context.Context, subject domain.Subject, events ...domain.PositionEvent)) *Notifier_NotifyPositionsEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]domain.PositionEvent, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(domain.PositionEvent)
+			}
+		}
+		run(args[0].(context.Context), args[1].(domain.Subject), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *Notifier_NotifyPositionsEvents_Call) Return(_a0 error) *Notifier_NotifyPositionsEvents_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Notifier_NotifyPositionsEvents_Call) RunAndReturn(run func(context.Context, domain.Subject, ...domain.PositionEvent) error) *Notifier_NotifyPositionsEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewNotifier creates a new instance of Notifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewNotifier(t interface {
@@ -21,6 +21,112 @@ func (_m *TgBotApi) EXPECT() *TgBotApi_Expecter {
 	return &TgBotApi_Expecter{mock: &_m.Mock}
 }
 
+// GetUpdatesChan provides a mock function with given fields: config
+func (_m *TgBotApi) GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	ret := _m.Called(config)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUpdatesChan")
+	}
+
+	var r0 tgbotapi.UpdatesChannel
+	if rf, ok := ret.Get(0).(func(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel); ok {
+		r0 = rf(config)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(tgbotapi.UpdatesChannel)
+		}
+	}
+
+	return r0
+}
+
+// TgBotApi_GetUpdatesChan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUpdatesChan'
+type TgBotApi_GetUpdatesChan_Call struct {
+	*mock.Call
+}
+
+// GetUpdatesChan is a helper method to define mock.On call
+//   - config tgbotapi.UpdateConfig
+func (_e *TgBotApi_Expecter) GetUpdatesChan(config interface{}) *TgBotApi_GetUpdatesChan_Call {
+	return &TgBotApi_GetUpdatesChan_Call{Call: _e.mock.On("GetUpdatesChan", config)}
+}
+
+func (_c *TgBotApi_GetUpdatesChan_Call) Run(run func(config tgbotapi.UpdateConfig)) *TgBotApi_GetUpdatesChan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(tgbotapi.UpdateConfig))
+	})
+	return _c
+}
+
+func (_c *TgBotApi_GetUpdatesChan_Call) Return(_a0 tgbotapi.UpdatesChannel) *TgBotApi_GetUpdatesChan_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TgBotApi_GetUpdatesChan_Call) RunAndReturn(run func(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel) *TgBotApi_GetUpdatesChan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Request provides a mock function with given fields: c
+func (_m *TgBotApi) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Request")
+	}
+
+	var r0 *tgbotapi.APIResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(tgbotapi.Chattable) (*tgbotapi.APIResponse, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(tgbotapi.Chattable) *tgbotapi.APIResponse); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tgbotapi.APIResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(tgbotapi.Chattable) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TgBotApi_Request_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Request'
+type TgBotApi_Request_Call struct {
+	*mock.Call
+}
+
+// Request is a helper method to define mock.On call
+//   - c tgbotapi.Chattable
+func (_e *TgBotApi_Expecter) Request(c interface{}) *TgBotApi_Request_Call {
+	return &TgBotApi_Request_Call{Call: _e.mock.On("Request", c)}
+}
+
+func (_c *TgBotApi_Request_Call) Run(run func(c tgbotapi.Chattable)) *TgBotApi_Request_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(tgbotapi.Chattable))
+	})
+	return _c
+}
+
+func (_c *TgBotApi_Request_Call) Return(_a0 *tgbotapi.APIResponse, _a1 error) *TgBotApi_Request_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TgBotApi_Request_Call) RunAndReturn(run func(tgbotapi.Chattable) (*tgbotapi.APIResponse, error)) *TgBotApi_Request_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Send provides a mock function with given fields: c
 func (_m *TgBotApi) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
 	ret := _m.Called(c)
@@ -77,6 +183,38 @@ func (_c *TgBotApi_Send_Call) RunAndReturn(run func(tgbotapi.Chattable) (tgbotap
 	return _c
 }
 
+// StopReceivingUpdates provides a mock function with no fields
+func (_m *TgBotApi) StopReceivingUpdates() {
+	_m.Called()
+}
+
+// TgBotApi_StopReceivingUpdates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'StopReceivingUpdates'
+type TgBotApi_StopReceivingUpdates_Call struct {
+	*mock.Call
+}
+
+// StopReceivingUpdates is a helper method to define mock.On call
+func (_e *TgBotApi_Expecter) StopReceivingUpdates() *TgBotApi_StopReceivingUpdates_Call {
+	return &TgBotApi_StopReceivingUpdates_Call{Call: _e.mock.On("StopReceivingUpdates")}
+}
+
+func (_c *TgBotApi_StopReceivingUpdates_Call) Run(run func()) *TgBotApi_StopReceivingUpdates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *TgBotApi_StopReceivingUpdates_Call) Return() *TgBotApi_StopReceivingUpdates_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TgBotApi_StopReceivingUpdates_Call) RunAndReturn(run func()) *TgBotApi_StopReceivingUpdates_Call {
+	_c.Run(run)
+	return _c
+}
+
 // NewTgBotApi creates a new instance of TgBotApi. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTgBotApi(t interface {
@@ -0,0 +1,182 @@
+package ethrpc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// WordSize is the size of the ABI encoded static value.
+	WordSize = 32
+
+	selectorSize = 4
+	addressSize  = 20
+)
+
+// Words is the ABI encoded data split by words.
+type Words [][]byte
+
+// MustEncodeCall encodes method call data from hex selector e.g: "70a08231" and encoded arguments.
+func MustEncodeCall(selector string, args ...[]byte) []byte {
+	data, err := hex.DecodeString(strings.TrimPrefix(selector, "0x"))
+	if err != nil || len(data) != selectorSize {
+		message := "MustEncodeCall: invalid selector: " + selector
+		panic(message)
+	}
+
+	for _, arg := range args {
+		data = append(data, arg...)
+	}
+
+	return data
+}
+
+// EncodeAddress encodes hex address as the ABI word.
+func EncodeAddress(address string) ([]byte, error) {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString: %w", err)
+	}
+
+	if len(decoded) != addressSize {
+		return nil, fmt.Errorf("%w: address %s", ErrMalformedData, address)
+	}
+
+	return leftPad(decoded), nil
+}
+
+// EncodeInt encodes signed integer as two's complement ABI word.
+func EncodeInt(value int64) []byte {
+	return EncodeUint(toTwosComplement(big.NewInt(value)))
+}
+
+// EncodeUint encodes unsigned integer as the ABI word.
+func EncodeUint(value *big.Int) []byte {
+	return value.FillBytes(make([]byte, WordSize))
+}
+
+// DecodeWords splits data by words and ensures there are at least count words.
+func DecodeWords(data []byte, count int) (Words, error) {
+	if len(data)%WordSize != 0 || len(data)/WordSize < count {
+		return nil, fmt.Errorf("%w: expected %d words, got %d bytes", ErrMalformedData, count, len(data))
+	}
+
+	words := make(Words, 0, len(data)/WordSize)
+	for offset := 0; offset < len(data); offset += WordSize {
+		words = append(words, data[offset:offset+WordSize])
+	}
+
+	return words, nil
+}
+
+// DecodeString decodes dynamic string result. Legacy tokens returning bytes32 are supported as well.
+func DecodeString(data []byte) (string, error) {
+	if len(data) == WordSize {
+		return strings.TrimRight(string(data), "\x00"), nil
+	}
+
+	words, err := DecodeWords(data, 2) //nolint:mnd // Offset and length.
+	if err != nil {
+		return "", err
+	}
+
+	offset, ok := toBound(words.Uint(0), len(data)-WordSize)
+	if !ok {
+		return "", fmt.Errorf("%w: string offset out of bounds", ErrMalformedData)
+	}
+
+	start := offset + WordSize
+
+	length, ok := toBound(new(big.Int).SetBytes(data[offset:start]), len(data)-start)
+	if !ok {
+		return "", fmt.Errorf("%w: string length out of bounds", ErrMalformedData)
+	}
+
+	return string(data[start : start+length]), nil
+}
+
+// DecodeUints decodes dynamic uint256[] result.
+func DecodeUints(data []byte) ([]*big.Int, error) {
+	words, err := DecodeWords(data, 2) //nolint:mnd // Offset and length.
+	if err != nil {
+		return nil, err
+	}
+
+	offset, ok := toBound(words.Uint(0), len(data)-WordSize)
+	if !ok {
+		return nil, fmt.Errorf("%w: array offset out of bounds", ErrMalformedData)
+	}
+
+	start := offset + WordSize
+
+	length, ok := toBound(new(big.Int).SetBytes(data[offset:start]), (len(data)-start)/WordSize)
+	if !ok {
+		return nil, fmt.Errorf("%w: array length out of bounds", ErrMalformedData)
+	}
+
+	values := make([]*big.Int, length)
+	for i := range values {
+		item := start + i*WordSize
+		values[i] = new(big.Int).SetBytes(data[item : item+WordSize])
+	}
+
+	return values, nil
+}
+
+// Keccak256 hashes the concatenated data, e.g. ABI encoded key of the contract mapping.
+func Keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, chunk := range data {
+		hash.Write(chunk)
+	}
+
+	return hash.Sum(nil)
+}
+
+// Address decodes i-th word as lower case hex address.
+func (words Words) Address(i int) string {
+	return "0x" + hex.EncodeToString(words[i][WordSize-addressSize:])
+}
+
+// Int decodes i-th word as two's complement signed integer.
+func (words Words) Int(i int) *big.Int {
+	value := words.Uint(i)
+	if words[i][0]&0x80 == 0 {
+		return value
+	}
+	return value.Sub(value, twoPow256())
+}
+
+// Uint decodes i-th word as unsigned integer.
+func (words Words) Uint(i int) *big.Int {
+	return new(big.Int).SetBytes(words[i])
+}
+
+func leftPad(data []byte) []byte {
+	word := make([]byte, WordSize)
+	copy(word[WordSize-len(data):], data)
+	return word
+}
+
+// toBound converts value to int if it does not exceed upperBound.
+func toBound(value *big.Int, upperBound int) (int, bool) {
+	if upperBound < 0 || value.Cmp(big.NewInt(int64(upperBound))) > 0 {
+		return 0, false
+	}
+	return int(value.Int64()), true
+}
+
+func toTwosComplement(value *big.Int) *big.Int {
+	if value.Sign() >= 0 {
+		return value
+	}
+	return new(big.Int).Add(value, twoPow256())
+}
+
+func twoPow256() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), WordSize*8) //nolint:mnd // Bits in byte.
+}
@@ -0,0 +1,65 @@
+package ethrpc_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+type abiSuite struct {
+	suite.Suite
+}
+
+func TestABI(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(abiSuite))
+}
+
+func (s *abiSuite) TestDecodeUints_Array_Values() {
+	data := concat(uintWord(ethrpc.WordSize), uintWord(2), uintWord(7), uintWord(8))
+
+	values, err := ethrpc.DecodeUints(data)
+	s.Require().NoError(err)
+	s.Require().Equal([]*big.Int{big.NewInt(7), big.NewInt(8)}, values)
+}
+
+func (s *abiSuite) TestDecodeUints_EmptyArray_Empty() {
+	values, err := ethrpc.DecodeUints(concat(uintWord(ethrpc.WordSize), uintWord(0)))
+	s.Require().NoError(err)
+	s.Require().Empty(values)
+}
+
+func (s *abiSuite) TestDecodeUints_Malformed_Error() {
+	cases := map[string][]byte{
+		"short":                uintWord(ethrpc.WordSize),
+		"not aligned":          append(concat(uintWord(ethrpc.WordSize), uintWord(0)), 0),
+		"offset out of bounds": concat(uintWord(2*ethrpc.WordSize), uintWord(0)),
+		"huge offset":          concat(powerOfTwoWord(255), uintWord(0)),
+		"length out of bounds": concat(uintWord(ethrpc.WordSize), uintWord(2), uintWord(7)),
+		"length beyond int":    concat(uintWord(ethrpc.WordSize), powerOfTwoWord(200)),
+	}
+
+	for name, data := range cases {
+		_, err := ethrpc.DecodeUints(data)
+		s.Require().ErrorIs(err, ethrpc.ErrMalformedData, name)
+	}
+}
+
+func powerOfTwoWord(exponent uint) []byte {
+	return ethrpc.EncodeUint(new(big.Int).Lsh(big.NewInt(1), exponent))
+}
+
+func uintWord(value uint64) []byte {
+	return ethrpc.EncodeUint(new(big.Int).SetUint64(value))
+}
+
+func concat(words ...[]byte) []byte {
+	var data []byte
+	for _, word := range words {
+		data = append(data, word...)
+	}
+	return data
+}
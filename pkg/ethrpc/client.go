@@ -0,0 +1,189 @@
+package ethrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const jsonRPCVersion = "2.0"
+
+// Client is a minimal Ethereum JSON-RPC client for read only contract calls and logs.
+type Client struct {
+	url        string
+	httpClient *http.Client
+	lastID     atomic.Int64
+}
+
+func NewClient(url string, httpClient *http.Client) *Client {
+	return &Client{
+		url:        url,
+		httpClient: httpClient,
+	}
+}
+
+// Call executes eth_call against the latest block and returns raw result bytes.
+func (client *Client) Call(ctx context.Context, to string, data []byte) ([]byte, error) {
+	params := []any{
+		callParams{
+			To:   to,
+			Data: "0x" + hex.EncodeToString(data),
+		},
+		"latest",
+	}
+
+	var result string
+
+	err := client.do(ctx, "eth_call", params, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// BlockNumber returns number of the latest block.
+func (client *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result string
+
+	err := client.do(ctx, "eth_blockNumber", []any{}, &result)
+	if err != nil {
+		return 0, err
+	}
+
+	number, err := strconv.ParseUint(strings.TrimPrefix(result, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("strconv.ParseUint: %w", err)
+	}
+
+	return number, nil
+}
+
+// GetLogs returns logs of the inclusive blocks range matching the topics, nil topic matches any value.
+func (client *Client) GetLogs(ctx context.Context, fromBlock, toBlock uint64, topics ...[]byte) ([]Log, error) {
+	encodedTopics := make([]any, len(topics))
+	for i, topic := range topics {
+		if topic != nil {
+			encodedTopics[i] = "0x" + hex.EncodeToString(topic)
+		}
+	}
+
+	params := []any{
+		logsParams{
+			FromBlock: encodeQuantity(fromBlock),
+			ToBlock:   encodeQuantity(toBlock),
+			Topics:    encodedTopics,
+		},
+	}
+
+	var logs []Log
+
+	err := client.do(ctx, "eth_getLogs", params, &logs)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (client *Client) do(ctx context.Context, method string, params []any, result any) error {
+	body, err := jsoniter.Marshal(request{
+		JSONRPC: jsonRPCVersion,
+		ID:      client.lastID.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("jsoniter.Marshal: %w", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, client.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := client.httpClient.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf("http.Do: %w", err)
+	}
+	defer httpResponse.Body.Close() //nolint:errcheck // Nothing to do with the error.
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, httpResponse.Status)
+	}
+
+	rawResponse, err := io.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	return decodeResponse(rawResponse, result)
+}
+
+func encodeQuantity(value uint64) string {
+	return "0x" + strconv.FormatUint(value, 16)
+}
+
+func decodeResponse(rawResponse []byte, result any) error {
+	var decoded response
+
+	err := jsoniter.Unmarshal(rawResponse, &decoded)
+	if err != nil {
+		return fmt.Errorf("jsoniter.Unmarshal: %w", err)
+	}
+
+	if decoded.Error != nil {
+		return decoded.Error
+	}
+
+	err = jsoniter.Unmarshal(decoded.Result, result)
+	if err != nil {
+		return fmt.Errorf("jsoniter.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type callParams struct {
+	To   string `json:"to"`
+	Data string `json:"data"`
+}
+
+type logsParams struct {
+	FromBlock string `json:"fromBlock"`
+	ToBlock   string `json:"toBlock"`
+	Topics    []any  `json:"topics"`
+}
+
+// Log is the event emitted by the contract, topics and data are hex encoded.
+type Log struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+type response struct {
+	Result jsoniter.RawMessage `json:"result"`
+	Error  *Error              `json:"error"`
+}
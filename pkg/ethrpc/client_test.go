@@ -0,0 +1,108 @@
+package ethrpc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/DanilaKorobkov/defi-monitoring/pkg/ethrpc"
+)
+
+type clientSuite struct {
+	suite.Suite
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(clientSuite))
+}
+
+func (s *clientSuite) TestBlockNumber_Quantity_Decoded() {
+	node := &nodeStub{result: `"0x1b4"`}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	number, err := ethrpc.NewClient(server.URL, server.Client()).BlockNumber(context.Background())
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(436), number)
+	s.Require().Equal("eth_blockNumber", node.request.Method)
+}
+
+func (s *clientSuite) TestBlockNumber_MalformedQuantity_Error() {
+	server := httptest.NewServer(&nodeStub{result: `"0xzz"`})
+	defer server.Close()
+
+	_, err := ethrpc.NewClient(server.URL, server.Client()).BlockNumber(context.Background())
+	s.Require().Error(err)
+}
+
+func (s *clientSuite) TestGetLogs_Topics_FilterAndLogs() {
+	node := &nodeStub{result: `[{"address": "0x01", "topics": ["0x02"], "data": "0x03"}]`}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	logs, err := ethrpc.NewClient(server.URL, server.Client()).
+		GetLogs(context.Background(), 16, 31, []byte{0xab}, nil, []byte{0xcd})
+	s.Require().NoError(err)
+
+	s.Require().Equal([]ethrpc.Log{{Address: "0x01", Topics: []string{"0x02"}, Data: "0x03"}}, logs)
+	s.Require().Equal("eth_getLogs", node.request.Method)
+	s.Require().JSONEq(
+		`[{"fromBlock": "0x10", "toBlock": "0x1f", "topics": ["0xab", null, "0xcd"]}]`,
+		string(node.request.Params),
+	)
+}
+
+func (s *clientSuite) TestGetLogs_NodeError_Error() {
+	server := httptest.NewServer(&nodeStub{error: `{"code": -32005, "message": "query returned more than 10000 results"}`})
+	defer server.Close()
+
+	_, err := ethrpc.NewClient(server.URL, server.Client()).GetLogs(context.Background(), 0, 1)
+
+	var rpcErr *ethrpc.Error
+	s.Require().ErrorAs(err, &rpcErr)
+	s.Require().Equal(-32005, rpcErr.Code)
+}
+
+func (s *clientSuite) TestGetLogs_UnexpectedStatus_Error() {
+	server := httptest.NewServer(&nodeStub{status: http.StatusTooManyRequests})
+	defer server.Close()
+
+	_, err := ethrpc.NewClient(server.URL, server.Client()).GetLogs(context.Background(), 0, 1)
+	s.Require().ErrorIs(err, ethrpc.ErrUnexpectedStatus)
+}
+
+// nodeStub answers any request with the raw result or error and keeps the last request.
+type nodeStub struct {
+	status int
+	result string
+	error  string
+
+	request struct {
+		ID     int64               `json:"id"`
+		Method string              `json:"method"`
+		Params jsoniter.RawMessage `json:"params"`
+	}
+}
+
+func (stub *nodeStub) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if stub.status != 0 {
+		writer.WriteHeader(stub.status)
+		return
+	}
+
+	_ = jsoniter.NewDecoder(request.Body).Decode(&stub.request)
+
+	response := map[string]any{"jsonrpc": "2.0", "id": stub.request.ID}
+	if stub.error != "" {
+		response["error"] = jsoniter.RawMessage(stub.error)
+	} else {
+		response["result"] = jsoniter.RawMessage(stub.result)
+	}
+
+	_ = jsoniter.NewEncoder(writer).Encode(response)
+}
@@ -0,0 +1,21 @@
+package ethrpc
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected http status")
+	ErrMalformedData    = errors.New("malformed abi data")
+)
+
+// Error is an error returned by JSON-RPC node e.g: reverted call.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
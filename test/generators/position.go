@@ -0,0 +1,79 @@
+package generators
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/DanilaKorobkov/defi-monitoring/internal/domain"
+)
+
+const (
+	maxPositionID = 1_000_000
+
+	minTick       = -200_000
+	maxTick       = 200_000
+	minRangeTicks = 10
+	maxRangeTicks = 2_000
+
+	maxLiquidity = 1_000_000_000
+)
+
+type PositionGenerator struct {
+	buffer domain.LiquidityPoolPosition
+}
+
+func NewPositionGenerator() *PositionGenerator {
+	return &PositionGenerator{}
+}
+
+func (gen *PositionGenerator) Base(position domain.LiquidityPoolPosition) *PositionGenerator {
+	gen.buffer = position
+	return gen
+}
+
+func (gen *PositionGenerator) Result() domain.LiquidityPoolPosition {
+	return gen.buffer
+}
+
+func (gen *PositionGenerator) Slim() *PositionGenerator {
+	gen.buffer.Chain = domain.ChainBase
+	gen.buffer.Dex = domain.DexUniswapV3
+	gen.buffer.Token0 = domain.Token{Name: "WETH", Decimals: 18}
+	gen.buffer.Token1 = domain.Token{Name: "USDC", Decimals: 6}
+
+	return gen.
+		WithID().
+		WithWallet().
+		WithTicks().
+		WithLiquidity()
+}
+
+func (gen *PositionGenerator) WithID(id ...string) *PositionGenerator {
+	set(&gen.buffer.ID, generatePositionID, id...)
+	gen.buffer.PositionLink = "https://app.uniswap.org/positions/v3/base/" + gen.buffer.ID
+	return gen
+}
+
+// WithLiquidity generates liquidity and the exact price matching the current tick.
+func (gen *PositionGenerator) WithLiquidity() *PositionGenerator {
+	gen.buffer.Liquidity = big.NewInt(int64(RandomInt(1, maxLiquidity)))
+	gen.buffer.SqrtPriceX96 = domain.GetSqrtRatioAtTick(gen.buffer.CurrentTick)
+	return gen
+}
+
+// WithTicks generates in range position ticks.
+func (gen *PositionGenerator) WithTicks() *PositionGenerator {
+	gen.buffer.TickLower = RandomInt(minTick, maxTick)
+	gen.buffer.TickUpper = gen.buffer.TickLower + RandomInt(minRangeTicks, maxRangeTicks)
+	gen.buffer.CurrentTick = RandomInt(gen.buffer.TickLower, gen.buffer.TickUpper)
+	return gen
+}
+
+func (gen *PositionGenerator) WithWallet(wallet ...string) *PositionGenerator {
+	set(&gen.buffer.Wallet, generateWallet, wallet...)
+	return gen
+}
+
+func generatePositionID() string {
+	return strconv.Itoa(RandomInt(1, maxPositionID))
+}
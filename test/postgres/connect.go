@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+
+	_ "github.com/lib/pq"
+)
+
+type Config struct {
+	PostgresPort     string `env:"POSTGRES_PORT,required"`
+	PostgresUser     string `env:"POSTGRES_USER,required"`
+	PostgresPassword string `env:"POSTGRES_PASSWORD,required"`
+	PostgresDB       string `env:"POSTGRES_DB,required"`
+}
+
+func (config Config) MakeURL() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@localhost:%s/%s?sslmode=disable",
+		config.PostgresUser, config.PostgresPassword, config.PostgresPort, config.PostgresDB,
+	)
+}
+
+// Connect connects to the local environment database described in deploy/.env.local.
+func Connect() (*sqlx.DB, error) {
+	projectDir := mustGetProjectDir(getCurrentFile())
+	envPath := path.Join(projectDir, "deploy/.env.local")
+
+	err := godotenv.Overload(envPath)
+	if err != nil {
+		return nil, fmt.Errorf("godotenv.Overload: %w", err)
+	}
+
+	config := Config{}
+
+	err = env.Parse(&config)
+	if err != nil {
+		return nil, fmt.Errorf("env.Parse: %w", err)
+	}
+
+	db, err := sqlx.Connect("postgres", config.MakeURL())
+	if err != nil {
+		return nil, fmt.Errorf("sqlx.Connect: %w", err)
+	}
+
+	return db, nil
+}
+
+func mustGetProjectDir(file string) string {
+	for dir := filepath.Dir(file); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if filepath.Base(dir) == "test" {
+			return filepath.Dir(dir)
+		}
+	}
+
+	message := "getProjectDir: cannot find project directory: " + file
+	panic(message)
+}
+
+func getCurrentFile() string {
+	_, filename, _, ok := runtime.Caller(1) //nolint:dogsled // Standard library
+	if !ok {
+		panic("Could not get current file")
+	}
+	return filename
+}